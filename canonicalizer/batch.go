@@ -0,0 +1,152 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canonicalizer
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+// Result is the result of canonicalizing one input with CanonicalizeAll.
+type Result struct {
+	Url *url.Url
+	Err error
+}
+
+// CanonicalizeAll parses and canonicalizes inputs concurrently using the given number of workers.
+// If workers is less than one, runtime.GOMAXPROCS(0) workers are used.
+//
+// The result for inputs[i] is found at index i of the returned slice. Errors for individual inputs are reported in
+// the results and do not stop the processing. The returned error is only set if ctx is done before all inputs are
+// processed, in which case the results for the unprocessed inputs have Err set to the context's error.
+func (p *Profile) CanonicalizeAll(ctx context.Context, inputs []string, workers int) ([]Result, error) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(inputs) {
+		workers = len(inputs)
+	}
+
+	results := make([]Result, len(inputs))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				u, err := p.ParseContext(ctx, inputs[i])
+				results[i] = Result{Url: u, Err: err}
+			}
+		}()
+	}
+
+	var err error
+	i := 0
+	for ; i < len(inputs); i++ {
+		select {
+		case indexes <- i:
+			continue
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		break
+	}
+	close(indexes)
+	wg.Wait()
+
+	for ; i < len(inputs); i++ {
+		results[i] = Result{Err: err}
+	}
+	return results, err
+}
+
+// maxStreamLineLength is the longest input line accepted by CanonicalizeStream.
+const maxStreamLineLength = 1024 * 1024
+
+// tsvFieldReplacer replaces the characters which would break a line of tab-separated values.
+var tsvFieldReplacer = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+
+// CanonicalizeStream reads newline-delimited urls from r and writes their canonical forms, as returned by String, to w.
+// One line is written for each line read. If an url can not be parsed, an empty line is written to keep the output
+// aligned with the input.
+//
+// The urls are processed one at a time and writing blocks when w does not keep up, so memory use does not grow with
+// the size of the input. The returned error is only set if reading or writing fails.
+func (p *Profile) CanonicalizeStream(r io.Reader, w io.Writer) error {
+	_, err := p.CanonicalizeStreamStats(r, w, false)
+	return err
+}
+
+// CanonicalizeStreamTSV is like CanonicalizeStream, but writes tab-separated lines with the original url, the
+// canonical url and the error message, if any. Tabs and carriage returns in the original url and tabs and newlines in
+// error messages are replaced by spaces.
+func (p *Profile) CanonicalizeStreamTSV(r io.Reader, w io.Writer) error {
+	_, err := p.CanonicalizeStreamStats(r, w, true)
+	return err
+}
+
+// StreamStats counts the lines processed by CanonicalizeStreamStats.
+type StreamStats struct {
+	// Lines is the number of lines read.
+	Lines int
+	// Failed is the number of lines which could not be canonicalized.
+	Failed int
+}
+
+// CanonicalizeStreamStats is like CanonicalizeStream, or CanonicalizeStreamTSV if tsv is true, but also returns how
+// many lines were read and how many of them could not be canonicalized.
+func (p *Profile) CanonicalizeStreamStats(r io.Reader, w io.Writer, tsv bool) (StreamStats, error) {
+	var stats StreamStats
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineLength)
+	bw := bufio.NewWriter(w)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		stats.Lines++
+		var canonical, errMsg string
+		if u, err := p.Parse(line); err != nil {
+			stats.Failed++
+			errMsg = err.Error()
+		} else {
+			canonical = p.String(u)
+		}
+
+		if tsv {
+			bw.WriteString(tsvFieldReplacer.Replace(line))
+			bw.WriteByte('\t')
+			bw.WriteString(canonical)
+			bw.WriteByte('\t')
+			bw.WriteString(tsvFieldReplacer.Replace(errMsg))
+		} else {
+			bw.WriteString(canonical)
+		}
+		if err := bw.WriteByte('\n'); err != nil {
+			return stats, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return stats, err
+	}
+	return stats, bw.Flush()
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canonicalizer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestProfile_CanonicalizeAll(t *testing.T) {
+	var inputs []string
+	for i := 0; i < 100; i++ {
+		inputs = append(inputs, fmt.Sprintf("http://www.example.com/%d#frag", i))
+	}
+	inputs = append(inputs, "http://[::1")
+
+	results, err := GoogleSafeBrowsing.CanonicalizeAll(context.Background(), inputs, 4)
+	if err != nil {
+		t.Fatalf("CanonicalizeAll() error = %v", err)
+	}
+	if len(results) != len(inputs) {
+		t.Fatalf("CanonicalizeAll() returned %d results, want %d", len(results), len(inputs))
+	}
+	for i := 0; i < 100; i++ {
+		want := fmt.Sprintf("http://www.example.com/%d", i)
+		if results[i].Err != nil || results[i].Url.String() != want {
+			t.Errorf("CanonicalizeAll() result %d = %v, %v, want %v", i, results[i].Url, results[i].Err, want)
+		}
+	}
+	if results[100].Err == nil {
+		t.Errorf("CanonicalizeAll() result 100 error = nil, want error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err = GoogleSafeBrowsing.CanonicalizeAll(ctx, inputs, 0)
+	if err != context.Canceled {
+		t.Errorf("CanonicalizeAll() error = %v, want %v", err, context.Canceled)
+	}
+	if len(results) != len(inputs) {
+		t.Errorf("CanonicalizeAll() returned %d results, want %d", len(results), len(inputs))
+	}
+}
+
+func TestProfile_CanonicalizeStream(t *testing.T) {
+	input := "www.example.com/a#b\r\nhttp://[::1\n\nhttp://EXAMPLE.com:80/\nhttp://example.com/\ta\tb\n"
+	tests := []struct {
+		name string
+		tsv  bool
+		want string
+	}{
+		{"1", false, "http://www.example.com/a\n\n\nhttp://example.com/\nhttp://example.com/ab\n"},
+		{"2", true, "www.example.com/a#b\thttp://www.example.com/a\t\n" +
+			"http://[::1\t\t" + errorMessage(t, "http://[::1") + "\n" +
+			"\t\t" + errorMessage(t, "") + "\n" +
+			"http://EXAMPLE.com:80/\thttp://example.com/\t\n" +
+			"http://example.com/ a b\thttp://example.com/ab\t\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			var err error
+			if tt.tsv {
+				err = GoogleSafeBrowsing.CanonicalizeStreamTSV(strings.NewReader(input), &sb)
+			} else {
+				err = GoogleSafeBrowsing.CanonicalizeStream(strings.NewReader(input), &sb)
+			}
+			if err != nil {
+				t.Fatalf("CanonicalizeStream() error = %v", err)
+			}
+			if sb.String() != tt.want {
+				t.Errorf("CanonicalizeStream() = %q, want %q", sb.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestProfile_CanonicalizeStreamStats(t *testing.T) {
+	var sb strings.Builder
+	stats, err := WhatWg.CanonicalizeStreamStats(strings.NewReader("http://a/\nhttp://[::1\n\nhttp://b/\n"), &sb, false)
+	if err != nil {
+		t.Fatalf("CanonicalizeStreamStats() error = %v", err)
+	}
+	if want := (StreamStats{Lines: 4, Failed: 2}); stats != want {
+		t.Errorf("CanonicalizeStreamStats() = %+v, want %+v", stats, want)
+	}
+	if want := "http://a/\n\n\nhttp://b/\n"; sb.String() != want {
+		t.Errorf("CanonicalizeStreamStats() wrote %q, want %q", sb.String(), want)
+	}
+}
+
+func errorMessage(t *testing.T, rawUrl string) string {
+	_, err := GoogleSafeBrowsing.Parse(rawUrl)
+	if err == nil {
+		t.Fatalf("Parse(%v) error = nil, want error", rawUrl)
+	}
+	return err.Error()
+}
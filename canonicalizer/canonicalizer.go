@@ -17,16 +17,26 @@
 package canonicalizer
 
 import (
-	"strings"
+	"context"
+	"fmt"
 
 	"github.com/nlnwa/whatwg-url/errors"
+	"github.com/nlnwa/whatwg-url/percent"
 	"github.com/nlnwa/whatwg-url/url"
 )
 
-func New(opts ...url.ParserOption) url.Parser {
-	p := &profile{
-		Parser:    url.NewParser(opts...),
-		sortQuery: NoSort,
+// New creates a canonicalization profile.
+//
+// The profile parses URLs with a parser configured by the url.ParserOption values in opts. The options from this
+// package add canonicalization rules to the profile. The rules are applied in the order the options are given.
+// An option for a stock rule (e.g. WithRemovePort or WithSortQuery) replaces a rule with the same name which is
+// already in the profile instead of adding it again, while WithRule, WithHostRule and WithSURTPrefixRule always add
+// their rules.
+func New(opts ...url.ParserOption) *Profile {
+	p := &Profile{
+		// The rules keep percent-encoded strings in the query parameters, so the parameters must be serialized with
+		// the query percent-encode set, which does not encode '%'.
+		Parser: url.NewParser(append([]url.ParserOption{url.WithQueryPercentEncodeSetForSearchParams()}, opts...)...),
 	}
 	for _, opt := range opts {
 		if o, ok := opt.(canonParserOption); ok {
@@ -36,36 +46,160 @@ func New(opts ...url.ParserOption) url.Parser {
 	return p
 }
 
-type profile struct {
+// Profile is a URL parser which canonicalizes the parsed URLs by applying a chain of rules.
+type Profile struct {
 	url.Parser
-	removeUserInfo          bool
-	removePort              bool
-	removeFragment          bool
-	sortQuery               querySort
-	repeatedPercentDecoding bool
-	defaultScheme           string
+	rules             []Rule
+	defaultScheme     string
+	keyHash           KeyHash
+	keepFragment      bool
+	unicodeHost       bool
+	verifyIdempotency bool
+	logger            Logger
 }
 
-func (p *profile) Parse(rawUrl string) (*url.Url, error) {
-	u, err := p.Parser.Parse(rawUrl)
+// Rules returns the canonicalization rules of the profile in the order they are applied.
+func (p *Profile) Rules() []Rule {
+	rules := make([]Rule, len(p.rules))
+	copy(rules, p.rules)
+	return rules
+}
+
+// With returns a new profile with the same configuration as this profile, modified by opts.
+// Rules added by opts are applied after the rules of this profile, while options for a stock rule already in this
+// profile replace it in place, e.g. WhatWgSortQuery.With(WithSortQuery(NoSort)) does not sort the query.
+// The original profile is not changed.
+func (p *Profile) With(opts ...url.ParserOption) url.Parser {
+	np := *p
+	np.Parser = p.Parser.With(opts...)
+	np.rules = p.Rules()
+	for _, opt := range opts {
+		if o, ok := opt.(canonParserOption); ok {
+			o.applyProfile(&np)
+		}
+	}
+	return &np
+}
+
+// setRule replaces the rule with the same name as r, or adds r after the other rules if there is no such rule.
+func (p *Profile) setRule(r Rule) {
+	p.replaceRule(RuleName(r), r)
+}
+
+// replaceRule replaces the rule named name by r, or adds r after the other rules if there is no such rule.
+func (p *Profile) replaceRule(name string, r Rule) {
+	if name != "" {
+		for i, pr := range p.rules {
+			if RuleName(pr) == name {
+				p.rules[i] = r
+				return
+			}
+		}
+	}
+	p.rules = append(p.rules, r)
+}
+
+// removeRules removes the rules with the given names.
+func (p *Profile) removeRules(names ...string) {
+	rules := p.rules[:0]
+	for _, r := range p.rules {
+		keep := true
+		for _, name := range names {
+			if RuleName(r) == name {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			rules = append(rules, r)
+		}
+	}
+	p.rules = rules
+}
+
+func (p *Profile) Parse(rawUrl string) (*url.Url, error) {
+	return p.ParseContext(context.Background(), rawUrl)
+}
+
+func (p *Profile) ParseContext(ctx context.Context, rawUrl string) (*url.Url, error) {
+	u, err := p.parse(ctx, rawUrl)
+	if err != nil {
+		return nil, err
+	}
+	return p.Canonicalize(u)
+}
+
+// ParseBytes is like Parse, but parses rawUrl without first copying it into a string. See url.Parser.ParseBytes.
+func (p *Profile) ParseBytes(rawUrl []byte) (*url.Url, error) {
+	u, err := p.Parser.ParseBytes(rawUrl)
 	if err != nil {
 		if errors.Type(err) == errors.MissingSchemeNonRelativeURL && p.defaultScheme != "" {
-			rawUrl = p.defaultScheme + "://" + rawUrl
-			u, err = p.Parser.Parse(rawUrl)
+			u, err = p.Parser.Parse(p.withDefaultScheme(string(rawUrl)))
 		}
 		if err != nil {
 			return nil, err
 		}
 	}
-
 	return p.Canonicalize(u)
 }
 
-func (p *profile) ParseRef(rawUrl, ref string) (*url.Url, error) {
+// ParseInto is like Parse, but stores the canonicalized url in u. See url.Parser.ParseInto.
+func (p *Profile) ParseInto(rawUrl string, u *url.Url) error {
+	err := p.Parser.ParseInto(rawUrl, u)
+	if err != nil && errors.Type(err) == errors.MissingSchemeNonRelativeURL && p.defaultScheme != "" {
+		err = p.Parser.ParseInto(p.withDefaultScheme(rawUrl), u)
+	}
+	if err != nil {
+		return err
+	}
+	if err := p.canonicalize(u, nil); err != nil {
+		u.Reset()
+		return err
+	}
+	if err := p.verifyIdempotent(u); err != nil {
+		u.Reset()
+		return err
+	}
+	return nil
+}
+
+// ParseWithProvenance is like Parse, but also returns the changes made by each rule of the profile, in the order
+// they were made. This is meant for debugging, e.g. to find out why two urls got different canonical forms.
+func (p *Profile) ParseWithProvenance(rawUrl string) (*url.Url, []Change, error) {
+	u, err := p.parse(context.Background(), rawUrl)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes := []Change{}
+	if err := p.canonicalize(u, &changes); err != nil {
+		return nil, changes, err
+	}
+	if err := p.verifyIdempotent(u); err != nil {
+		return nil, changes, err
+	}
+	return u, changes, nil
+}
+
+// parse parses rawUrl, retrying with the default scheme if rawUrl has no scheme.
+func (p *Profile) parse(ctx context.Context, rawUrl string) (*url.Url, error) {
+	u, err := p.Parser.ParseContext(ctx, rawUrl)
+	if err != nil {
+		if errors.Type(err) == errors.MissingSchemeNonRelativeURL && p.defaultScheme != "" {
+			rawUrl = p.withDefaultScheme(rawUrl)
+			u, err = p.Parser.ParseContext(ctx, rawUrl)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+func (p *Profile) ParseRef(rawUrl, ref string) (*url.Url, error) {
 	b, err := p.Parser.Parse(rawUrl)
 	if err != nil {
 		if errors.Type(err) == errors.MissingSchemeNonRelativeURL && p.defaultScheme != "" {
-			rawUrl = p.defaultScheme + "://" + rawUrl
+			rawUrl = p.withDefaultScheme(rawUrl)
 			b, err = p.Parser.Parse(rawUrl)
 		}
 		if err != nil {
@@ -81,44 +215,95 @@ func (p *profile) ParseRef(rawUrl, ref string) (*url.Url, error) {
 	return p.Canonicalize(u)
 }
 
-func (p *profile) Canonicalize(u *url.Url) (*url.Url, error) {
-	if p.repeatedPercentDecoding {
-		if u.Hostname() != "" {
-			u.SetHostname(decodeEncode(u.Hostname(), url.HostPercentEncodeSet))
+// Canonicalize applies the rules of the profile to u. The url is modified in place.
+// If a rule returns an error, the remaining rules are skipped and the error is returned.
+func (p *Profile) Canonicalize(u *url.Url) (*url.Url, error) {
+	if err := p.canonicalize(u, nil); err != nil {
+		return nil, err
+	}
+	if err := p.verifyIdempotent(u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// canonicalize applies the rules of the profile to u. If changes is not nil, the changes made by each rule are
+// appended to it.
+func (p *Profile) canonicalize(u *url.Url, changes *[]Change) error {
+	fragment := u.Hash()
+	for i, r := range p.rules {
+		var before components
+		if changes != nil {
+			before = componentsOf(u)
 		}
-		if u.Pathname() != "" {
-			u.SetPathname(decodeEncode(u.Pathname(), LaxPathPercentEncodeSet))
+		if err := r.Apply(u); err != nil {
+			p.warn("canonicalization rule failed", "rule", RuleName(r), "url", u.Href(false), "error", err)
+			return err
 		}
-		if u.Search() != "" {
-			u.SearchParams().Iterate(func(pair *url.NameValuePair) {
-				pair.Name = decodeEncode(pair.Name, RepeatedQueryPercentDecodeSet)
-				pair.Value = decodeEncode(pair.Value, RepeatedQueryPercentDecodeSet)
-			})
+		if changes != nil {
+			*changes = appendChanges(*changes, i, RuleName(r), before, componentsOf(u))
 		}
-		if u.Hash() != "" {
-			u.SetHash(decodeEncode(strings.TrimPrefix(u.Hash(), "#"), url.HostPercentEncodeSet))
+	}
+	if p.keepFragment && u.Hash() != fragment {
+		if changes != nil {
+			*changes = append(*changes, Change{Rule: -1, Name: "keepFragment", Component: "fragment", Before: u.Hash(), After: fragment})
 		}
+		u.SetHash(fragment)
+	}
+	return nil
+}
+
+// IdempotencyError is returned when a profile configured with WithVerifyIdempotency gives a different result when
+// canonicalizing its own output.
+type IdempotencyError struct {
+	// First is the result of the first canonicalization.
+	First string
+	// Second is the result of canonicalizing First, or the empty string if First could not be parsed.
+	Second string
+	// Err is the error from parsing or canonicalizing First, if any.
+	Err error
+}
+
+func (e *IdempotencyError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("canonicalization is not idempotent: %q could not be canonicalized again: %v", e.First, e.Err)
 	}
+	return fmt.Sprintf("canonicalization is not idempotent: %q became %q", e.First, e.Second)
+}
+
+func (e *IdempotencyError) Unwrap() error {
+	return e.Err
+}
 
-	if p.removePort {
-		u.SetPort("")
+// verifyIdempotent canonicalizes the serialization of u once more and returns an IdempotencyError if the result
+// differs. Nothing is done unless the profile is configured with WithVerifyIdempotency.
+func (p *Profile) verifyIdempotent(u *url.Url) error {
+	if !p.verifyIdempotency {
+		return nil
 	}
-	if p.removeUserInfo {
-		u.SetUsername("")
-		u.SetPassword("")
+	first := u.Href(false)
+	v, err := p.parse(context.Background(), first)
+	if err == nil {
+		err = p.canonicalize(v, nil)
 	}
-	if p.removeFragment {
-		u.SetHash("")
+	if err != nil {
+		err = &IdempotencyError{First: first, Err: err}
+	} else if second := v.Href(false); second != first {
+		err = &IdempotencyError{First: first, Second: second}
 	}
-
-	switch p.sortQuery {
-	case SortKeys:
-		u.SearchParams().Sort()
-	case SortParameter:
-		u.SearchParams().SortAbsolute()
+	if err != nil {
+		p.warn("canonicalization is not idempotent", "url", first, "error", err)
 	}
+	return err
+}
 
-	return u, nil
+// String returns the canonical string of u. This is the same as u.String() unless the profile is configured
+// with WithUnicodeHost, in which case domains are serialized in Unicode form. Key always uses the ASCII form.
+func (p *Profile) String(u *url.Url) string {
+	if p.unicodeHost {
+		return u.UnicodeHref(false)
+	}
+	return u.Href(false)
 }
 
 func decodeEncode(s string, tr *url.PercentEncodeSet) string {
@@ -130,7 +315,7 @@ func decodeEncode(s string, tr *url.PercentEncodeSet) string {
 func repeatedDecode(s string) string {
 	var r string
 	for {
-		r = decodePercentEncoded(s)
+		r = percent.Decode(s)
 		if s == r {
 			break
 		}
@@ -140,51 +325,5 @@ func repeatedDecode(s string) string {
 }
 
 func percentEncode(s string, tr *url.PercentEncodeSet) string {
-	sb := strings.Builder{}
-	for _, b := range []byte(s) {
-		sb.WriteString(percentEncodeByte(b, tr.Set('%')))
-	}
-	return sb.String()
-}
-
-func percentEncodeByte(b byte, tr *url.PercentEncodeSet) string {
-	if tr != nil && !tr.ByteShouldBeEncoded(b) {
-		return string(b)
-	}
-
-	percentEncoded := make([]byte, 3)
-	percentEncoded[0] = '%'
-	percentEncoded[1] = "0123456789ABCDEF"[b>>4]
-	percentEncoded[2] = "0123456789ABCDEF"[b&15]
-	return string(percentEncoded)
-}
-
-func decodePercentEncoded(s string) string {
-	sb := strings.Builder{}
-	bytes := []byte(s)
-	for i := 0; i < len(bytes); i++ {
-		if bytes[i] != '%' {
-			sb.WriteByte(bytes[i])
-		} else if len(bytes) < (i+3) ||
-			(!url.ASCIIHexDigit.Test(uint(bytes[i+1])) || !url.ASCIIHexDigit.Test(uint(bytes[i+2]))) {
-			sb.WriteByte(bytes[i])
-		} else {
-			b := unhex(bytes[i+1])<<4 | unhex(bytes[i+2])
-			sb.WriteByte(b)
-			i += 2
-		}
-	}
-	return sb.String()
-}
-
-func unhex(c byte) byte {
-	switch {
-	case '0' <= c && c <= '9':
-		return c - '0'
-	case 'a' <= c && c <= 'f':
-		return c - 'a' + 10
-	case 'A' <= c && c <= 'F':
-		return c - 'A' + 10
-	}
-	return 0
+	return percent.Encode(s, tr.Set('%'))
 }
@@ -0,0 +1,389 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canonicalizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/encoding/charmap"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+// Config describes a canonicalization profile in a form which can be stored in a JSON or YAML configuration file.
+//
+// Example:
+//
+//	{
+//	  "base": "whatwg",
+//	  "percentEncodeSets": {
+//	    "myQuery": {"base": "query", "add": "'", "remove": "\""}
+//	  },
+//	  "options": [
+//	    {"name": "laxHostParsing"},
+//	    {"name": "queryPercentEncodeSet", "value": "myQuery"},
+//	    {"name": "removeFragment"},
+//	    {"name": "sortQuery", "value": "keys"}
+//	  ]
+//	}
+//
+// The same profile in YAML:
+//
+//	base: whatwg
+//	percentEncodeSets:
+//	  myQuery: {base: query, add: "'", remove: '"'}
+//	options:
+//	  - name: laxHostParsing
+//	  - {name: queryPercentEncodeSet, value: myQuery}
+//	  - name: removeFragment
+//	  - {name: sortQuery, value: keys}
+type Config struct {
+	// Base is the name of a predefined profile to extend (whatwg, whatwgSortQuery, googleSafeBrowsing, semantic,
+	// warcUrlKey, heritrix or pywb). If empty, the profile starts without any parser options or rules.
+	Base string `json:"base,omitempty" yaml:"base,omitempty"`
+
+	// PercentEncodeSets defines named percent-encode sets which can be used by the options.
+	PercentEncodeSets map[string]PercentEncodeSetConfig `json:"percentEncodeSets,omitempty" yaml:"percentEncodeSets,omitempty"`
+
+	// Options are applied in the order given. Options adding rules add them in this order. An option for a stock
+	// rule already in the base profile replaces it, e.g. {"name": "sortQuery", "value": "none"} removes the
+	// sorting of whatwgSortQuery, and withoutRules removes rules by name.
+	Options []OptionConfig `json:"options" yaml:"options"`
+}
+
+// PercentEncodeSetConfig defines a percent-encode set based on a predefined set.
+type PercentEncodeSetConfig struct {
+	// Base is the name of the predefined set to start from (c0Control, c0ControlOrSpace, fragment, query,
+	// specialQuery, path, userinfo, host, laxPath, laxQuery or repeatedQueryDecode).
+	Base string `json:"base" yaml:"base"`
+	// Add contains the ASCII characters to add to the set.
+	Add string `json:"add,omitempty" yaml:"add,omitempty"`
+	// Remove contains the ASCII characters to remove from the set.
+	Remove string `json:"remove,omitempty" yaml:"remove,omitempty"`
+}
+
+// OptionConfig is an option with an optional value.
+//
+// The name is either one of the url parser options or canonicalizer options without the "With" prefix and with
+// a lower case first letter (e.g. "laxHostParsing" or "removePort"), or the name of a rule registered with
+// RegisterRule. Options taking an argument get it from Value:
+//
+//   - percent-encode set options take the name of a set
+//   - sortQuery takes none, keys or parameter
+//   - encodingOverride takes the name of a charmap (e.g. "ISO 8859-1")
+//   - ipv6Style takes compressed or expanded
+//   - requireDottedHost takes a comma separated allow list
+//   - stripDefaultDocument takes a comma separated list of file names
+//   - upgradeScheme takes a comma separated list of host patterns
+//   - keyHash takes xxhash64, sha1 or sha256
+//   - removeTrackingParameters takes a comma separated list of names added to the built-in list
+//   - withoutRules takes a comma separated list of rule names to remove (see RuleName)
+type OptionConfig struct {
+	Name  string `json:"name" yaml:"name"`
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+var (
+	registeredRulesMu sync.RWMutex
+	registeredRules   = map[string]Rule{}
+)
+
+// RegisterRule makes a custom rule available to configurations by name.
+// Registering a name used by a built-in option has no effect, since built-in options take precedence.
+// A rule without a name (see RuleName) gets the registered name.
+func RegisterRule(name string, r Rule) {
+	if RuleName(r) == "" {
+		r = NamedRule(name, r)
+	}
+	registeredRulesMu.Lock()
+	defer registeredRulesMu.Unlock()
+	registeredRules[name] = r
+}
+
+// LoadConfigFile reads a Config from a file and creates a profile from it. Files with the extension ".yaml" or ".yml"
+// are decoded as YAML, other files as JSON.
+func LoadConfigFile(path string) (*Profile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		return LoadConfigYAML(f)
+	}
+	return LoadConfig(f)
+}
+
+// LoadConfig reads a JSON encoded Config and creates a profile from it. Unknown fields are rejected.
+func LoadConfig(r io.Reader) (*Profile, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	c := &Config{}
+	if err := dec.Decode(c); err != nil {
+		return nil, fmt.Errorf("canonicalizer: could not decode config: %w", err)
+	}
+	return NewFromConfig(c)
+}
+
+// LoadConfigYAML reads a YAML encoded Config and creates a profile from it. Unknown fields are rejected.
+func LoadConfigYAML(r io.Reader) (*Profile, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	c := &Config{}
+	if err := dec.Decode(c); err != nil {
+		return nil, fmt.Errorf("canonicalizer: could not decode config: %w", err)
+	}
+	return NewFromConfig(c)
+}
+
+// NewFromConfig creates a profile from a Config.
+func NewFromConfig(c *Config) (*Profile, error) {
+	sets := make(map[string]*url.PercentEncodeSet, len(c.PercentEncodeSets))
+	for name, sc := range c.PercentEncodeSets {
+		base, ok := predefinedPercentEncodeSets[sc.Base]
+		if !ok {
+			return nil, fmt.Errorf("canonicalizer: percent-encode set %q: unknown base set %q", name, sc.Base)
+		}
+		set, err := changeSet(base, sc.Add, (*url.PercentEncodeSet).Set)
+		if err != nil {
+			return nil, fmt.Errorf("canonicalizer: percent-encode set %q: %w", name, err)
+		}
+		if set, err = changeSet(set, sc.Remove, (*url.PercentEncodeSet).Clear); err != nil {
+			return nil, fmt.Errorf("canonicalizer: percent-encode set %q: %w", name, err)
+		}
+		sets[name] = set
+	}
+
+	opts := make([]url.ParserOption, 0, len(c.Options))
+	for _, oc := range c.Options {
+		opt, err := newOption(oc, sets)
+		if err != nil {
+			return nil, fmt.Errorf("canonicalizer: option %q: %w", oc.Name, err)
+		}
+		opts = append(opts, opt)
+	}
+
+	if c.Base == "" {
+		return New(opts...), nil
+	}
+	base, ok := predefinedProfiles()[c.Base]
+	if !ok {
+		return nil, fmt.Errorf("canonicalizer: unknown base profile %q", c.Base)
+	}
+	return base.With(opts...).(*Profile), nil
+}
+
+// changeSet returns a copy of set where f is applied to the characters in chars.
+func changeSet(set *url.PercentEncodeSet, chars string, f func(*url.PercentEncodeSet, ...uint) *url.PercentEncodeSet) (*url.PercentEncodeSet, error) {
+	if chars == "" {
+		return set, nil
+	}
+	var bytes []uint
+	for _, c := range chars {
+		if c > 0x7f {
+			return nil, fmt.Errorf("%q is not an ASCII character", c)
+		}
+		bytes = append(bytes, uint(c))
+	}
+	return f(set, bytes...), nil
+}
+
+func predefinedProfiles() map[string]*Profile {
+	return map[string]*Profile{
+		"whatwg":             WhatWg,
+		"whatwgSortQuery":    WhatWgSortQuery,
+		"googleSafeBrowsing": GoogleSafeBrowsing,
+		"semantic":           Semantic,
+		"warcUrlKey":         WarcUrlKey,
+		"heritrix":           Heritrix,
+		"pywb":               Pywb,
+	}
+}
+
+var predefinedPercentEncodeSets = map[string]*url.PercentEncodeSet{
+	"c0Control":           url.C0PercentEncodeSet,
+	"c0ControlOrSpace":    url.C0OrSpacePercentEncodeSet,
+	"fragment":            url.FragmentPercentEncodeSet,
+	"query":               url.QueryPercentEncodeSet,
+	"specialQuery":        url.SpecialQueryPercentEncodeSet,
+	"path":                url.PathPercentEncodeSet,
+	"userinfo":            url.UserInfoPercentEncodeSet,
+	"host":                url.HostPercentEncodeSet,
+	"laxPath":             LaxPathPercentEncodeSet,
+	"laxQuery":            LaxQueryPercentEncodeSet,
+	"repeatedQueryDecode": RepeatedQueryPercentDecodeSet,
+}
+
+// flagOptions are the options without arguments.
+var flagOptions = map[string]func() url.ParserOption{
+	"reportValidationErrors":              url.WithReportValidationErrors,
+	"failOnValidationError":               url.WithFailOnValidationError,
+	"laxHostParsing":                      url.WithLaxHostParsing,
+	"collapseConsecutiveSlashes":          url.WithCollapseConsecutiveSlashes,
+	"acceptInvalidCodepoints":             url.WithAcceptInvalidCodepoints,
+	"percentEncodeSinglePercentSign":      url.WithPercentEncodeSinglePercentSign,
+	"allowSettingPathForNonBaseUrl":       url.WithAllowSettingPathForNonBaseUrl,
+	"skipWindowsDriveLetterNormalization": url.WithSkipWindowsDriveLetterNormalization,
+	"skipTrailingSlashNormalization":      url.WithSkipTrailingSlashNormalization,
+	"skipEqualsForEmptySearchParamsValue": url.WithSkipEqualsForEmptySearchParamsValue,
+	"allowIPv6ZoneID":                     url.WithAllowIPv6ZoneID,
+	"stripTrailingDot":                    url.WithStripTrailingDot,
+	"forbidLoopback":                      url.WithForbidLoopback,
+	"forbidPrivateAddresses":              url.WithForbidPrivateAddresses,
+	"forbidAmbiguousIPv4":                 url.WithForbidAmbiguousIPv4,
+	"verifyDNSLength":                     url.WithVerifyDNSLength,
+	"removeUserInfo":                      WithRemoveUserInfo,
+	"removePort":                          WithRemovePort,
+	"removeFragment":                      WithRemoveFragment,
+	"removeQuery":                         WithRemoveQuery,
+	"keepFragment":                        WithKeepFragment,
+	"unicodeHost":                         WithUnicodeHost,
+	"verifyIdempotency":                   WithVerifyIdempotency,
+	"repeatedPercentDecoding":             WithRepeatedPercentDecoding,
+	"lowercase":                           WithLowercase,
+	"stripSessionIDs":                     WithStripSessionIDs,
+	"stripTrailingSlash":                  WithStripTrailingSlash,
+	"stripWWW":                            WithStripWWW,
+	"removeDuplicateQueryParams":          WithRemoveDuplicateQueryParams,
+	"removeRepeatedQueryNames":            WithRemoveRepeatedQueryNames,
+	"removeEmptyQueryAndFragment":         WithRemoveEmptyQueryAndFragment,
+	"decodeUnreserved":                    WithDecodeUnreserved,
+	"uppercasePercentEscapes":             WithUppercasePercentEscapes,
+	"lowercasePercentEscapes":             WithLowercasePercentEscapes,
+	"stripCacheBusters":                   WithStripCacheBusters,
+}
+
+// setOptions are the options taking a percent-encode set as argument.
+var setOptions = map[string]func(*url.PercentEncodeSet) url.ParserOption{
+	"pathPercentEncodeSet":            url.WithPathPercentEncodeSet,
+	"queryPercentEncodeSet":           url.WithQueryPercentEncodeSet,
+	"specialQueryPercentEncodeSet":    url.WithSpecialQueryPercentEncodeSet,
+	"fragmentPercentEncodeSet":        url.WithFragmentPathPercentEncodeSet,
+	"specialFragmentPercentEncodeSet": url.WithSpecialFragmentPathPercentEncodeSet,
+}
+
+// newOption creates the option described by oc. Percent-encode sets are looked up in sets before the predefined sets.
+func newOption(oc OptionConfig, sets map[string]*url.PercentEncodeSet) (url.ParserOption, error) {
+	if f, ok := flagOptions[oc.Name]; ok {
+		if oc.Value != "" {
+			return nil, fmt.Errorf("option does not take a value")
+		}
+		return f(), nil
+	}
+	if f, ok := setOptions[oc.Name]; ok {
+		set, ok := sets[oc.Value]
+		if !ok {
+			set, ok = predefinedPercentEncodeSets[oc.Value]
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown percent-encode set %q", oc.Value)
+		}
+		return f(set), nil
+	}
+
+	switch oc.Name {
+	case "defaultScheme":
+		return WithDefaultScheme(oc.Value), nil
+	case "sortQuery":
+		switch oc.Value {
+		case "none":
+			return WithSortQuery(NoSort), nil
+		case "keys":
+			return WithSortQuery(SortKeys), nil
+		case "parameter":
+			return WithSortQuery(SortParameter), nil
+		}
+		return nil, fmt.Errorf("unknown sort type %q, must be one of none, keys or parameter", oc.Value)
+	case "encodingOverride":
+		for _, e := range charmap.All {
+			if cm, ok := e.(*charmap.Charmap); ok && strings.EqualFold(cm.String(), oc.Value) {
+				return url.WithEncodingOverride(cm), nil
+			}
+		}
+		return nil, fmt.Errorf("unknown encoding %q", oc.Value)
+	case "idnaCacheSize":
+		size, err := strconv.Atoi(oc.Value)
+		if err != nil {
+			return nil, err
+		}
+		return url.WithIDNACache(size), nil
+	case "ipv6Style":
+		switch oc.Value {
+		case "compressed":
+			return url.WithIPv6Style(url.IPv6Compressed), nil
+		case "expanded":
+			return url.WithIPv6Style(url.IPv6Expanded), nil
+		}
+		return nil, fmt.Errorf("unknown IPv6 style %q, must be compressed or expanded", oc.Value)
+	case "stripDefaultDocument":
+		var names []string
+		if oc.Value != "" {
+			names = strings.Split(oc.Value, ",")
+		}
+		return WithStripDefaultDocument(names...), nil
+	case "upgradeScheme":
+		if oc.Value == "" {
+			return WithUpgradeScheme(), nil
+		}
+		patterns := strings.Split(oc.Value, ",")
+		if _, err := url.NewHostMatcher(patterns...); err != nil {
+			return nil, err
+		}
+		return WithUpgradeScheme(patterns...), nil
+	case "keyHash":
+		for _, h := range []KeyHash{XXHash64, SHA1, SHA256} {
+			if h.String() == oc.Value {
+				return WithKeyHash(h), nil
+			}
+		}
+		return nil, fmt.Errorf("unknown key hash %q, must be one of xxhash64, sha1 or sha256", oc.Value)
+	case "removeTrackingParameters":
+		var names []string
+		if oc.Value != "" {
+			names = strings.Split(oc.Value, ",")
+		}
+		return WithRemoveTrackingParameters(names...), nil
+	case "withoutRules":
+		if oc.Value == "" {
+			return nil, fmt.Errorf("option takes a comma separated list of rule names")
+		}
+		return WithoutRules(strings.Split(oc.Value, ",")...), nil
+	case "requireDottedHost":
+		var allowList []string
+		if oc.Value != "" {
+			allowList = strings.Split(oc.Value, ",")
+		}
+		return url.WithRequireDottedHost(allowList...), nil
+	}
+
+	registeredRulesMu.RLock()
+	r, ok := registeredRules[oc.Name]
+	registeredRulesMu.RUnlock()
+	if ok {
+		return WithRule(r), nil
+	}
+	return nil, fmt.Errorf("unknown option")
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canonicalizer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+func TestLoadConfig(t *testing.T) {
+	RegisterRule("test-add-path", RuleFunc(func(u *url.Url) error {
+		u.SetPathname(u.Pathname() + "x")
+		return nil
+	}))
+
+	tests := []struct {
+		name    string
+		config  string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"1", `{"options": [{"name": "removeFragment"}, {"name": "sortQuery", "value": "keys"}]}`,
+			"http://example.com/?b&a#c", "http://example.com/?a=&b=", false},
+		{"2", `{"base": "googleSafeBrowsing", "options": [{"name": "defaultScheme", "value": "https"}]}`,
+			"www.google.com/a#b", "https://www.google.com/a", false},
+		{"3", `{"percentEncodeSets": {"q": {"base": "query", "add": "'"}}, "options": [{"name": "queryPercentEncodeSet", "value": "q"}]}`,
+			"http://example.com/?a'b", "http://example.com/?a%27b", false},
+		{"4", `{"options": [{"name": "removePort"}, {"name": "test-add-path"}]}`,
+			"http://example.com:8080/a", "http://example.com/ax", false},
+		{"5", `{"options": [{"name": "unknown"}]}`, "", "", true},
+		{"6", `{"options": [{"name": "removePort", "value": "x"}]}`, "", "", true},
+		{"7", `{"base": "unknown", "options": []}`, "", "", true},
+		{"8", `{"options": [{"name": "sortQuery", "value": "random"}]}`, "", "", true},
+		{"9", `{"options": [], "extra": true}`, "", "", true},
+		{"10", `{"percentEncodeSets": {"q": {"base": "unknown"}}, "options": []}`, "", "", true},
+		{"11", `{"options": [{"name": "keyHash", "value": "md5"}]}`, "", "", true},
+		{"12", `{"base": "whatwgSortQuery", "options": [{"name": "sortQuery", "value": "none"}]}`,
+			"http://example.com/?b=1&a=2", "http://example.com/?b=1&a=2", false},
+		{"13", `{"base": "heritrix", "options": [{"name": "withoutRules", "value": "stripWWW,lowercase"}]}`,
+			"http://www.example.com/A", "http://www.example.com/A", false},
+		{"14", `{"options": [{"name": "removePort"}, {"name": "upgradeScheme"}, {"name": "upgradeScheme", "value": "a.example"}]}`,
+			"http://b.example:8080/", "http://b.example/", false},
+		{"15", `{"options": [{"name": "withoutRules"}]}`, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := LoadConfig(strings.NewReader(tt.config))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, err := p.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", tt.input, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Parse(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"1", "options:\n  - name: removeFragment\n  - {name: sortQuery, value: keys}\n",
+			"http://example.com/?b&a#c", "http://example.com/?a=&b=", false},
+		{"2", "base: googleSafeBrowsing\noptions:\n  - name: defaultScheme\n    value: https\n",
+			"www.google.com/a#b", "https://www.google.com/a", false},
+		{"3", "percentEncodeSets:\n  q: {base: query, add: \"'\"}\noptions:\n  - {name: queryPercentEncodeSet, value: q}\n",
+			"http://example.com/?a'b", "http://example.com/?a%27b", false},
+		{"4", "options:\n  - name: unknown\n", "", "", true},
+		{"5", "options: []\nextra: true\n", "", "", true},
+		{"6", "options: [\n", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := LoadConfigYAML(strings.NewReader(tt.config))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfigYAML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, err := p.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", tt.input, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Parse(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"profile.json": `{"options": [{"name": "removeFragment"}]}`,
+		"profile.yaml": "options:\n  - name: removeFragment\n",
+		"profile.yml":  "options:\n  - name: removeFragment\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		p, err := LoadConfigFile(path)
+		if err != nil {
+			t.Fatalf("LoadConfigFile(%v) error = %v", name, err)
+		}
+		got, err := p.Parse("http://example.com/a#b")
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if want := "http://example.com/a"; got.String() != want {
+			t.Errorf("LoadConfigFile(%v) profile gave %v, want %v", name, got, want)
+		}
+	}
+}
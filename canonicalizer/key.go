@@ -0,0 +1,80 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canonicalizer
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+// KeyHash is the hash algorithm used by Profile.Key.
+type KeyHash int
+
+const (
+	// XXHash64 is the 64-bit xxHash algorithm. It is fast, but not cryptographically secure. This is the default.
+	XXHash64 KeyHash = iota
+	// SHA1 is the SHA-1 algorithm.
+	SHA1
+	// SHA256 is the SHA-256 algorithm.
+	SHA256
+)
+
+func (h KeyHash) String() string {
+	switch h {
+	case XXHash64:
+		return "xxhash64"
+	case SHA1:
+		return "sha1"
+	case SHA256:
+		return "sha256"
+	}
+	return fmt.Sprintf("KeyHash(%d)", int(h))
+}
+
+// Key returns a stable hash of the serialized url as a lowercase hexadecimal string. The hash algorithm is set
+// with WithKeyHash. The key is suitable for deduplication and sharding.
+//
+// u is expected to be canonicalized by this profile, e.g. the result of Parse. Key does not apply the rules itself.
+func (p *Profile) Key(u *url.Url) (string, error) {
+	s := []byte(u.Href(false))
+	switch p.keyHash {
+	case XXHash64:
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], xxhash64(s))
+		return hex.EncodeToString(b[:]), nil
+	case SHA1:
+		sum := sha1.Sum(s)
+		return hex.EncodeToString(sum[:]), nil
+	case SHA256:
+		sum := sha256.Sum256(s)
+		return hex.EncodeToString(sum[:]), nil
+	}
+	return "", fmt.Errorf("unknown key hash %v", p.keyHash)
+}
+
+// Key64 returns the 64-bit xxHash of the serialized url. This is the numeric form of the key returned by Key with
+// the default XXHash64, and is not affected by WithKeyHash. It is meant for compact in-memory indexes.
+//
+// u is expected to be canonicalized by this profile, e.g. the result of Parse. Key64 does not apply the rules itself.
+func (p *Profile) Key64(u *url.Url) uint64 {
+	return xxhash64([]byte(u.Href(false)))
+}
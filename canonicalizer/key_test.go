@@ -0,0 +1,86 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canonicalizer
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestProfile_Key(t *testing.T) {
+	tests := []struct {
+		name    string
+		hash    KeyHash
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"1", SHA1, "HTTP://Example.com/?b=2&a=1#frag", "0677b9d64c52a49b37afb7aa9c1e53fb4e82c701", false},
+		{"2", SHA256, "http://example.com:80/?a=1&b=2", "7f736dfa93d0d288f84ab047a2c6dafaa850a23f5066974d928c79004ce7b9d0", false},
+		{"3", KeyHash(42), "http://example.com/", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := WhatWgSortQuery.With(WithRemoveFragment(), WithKeyHash(tt.hash)).(*Profile)
+			u, err := p.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", tt.input, err)
+			}
+			got, err := p.Key(u)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Key() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Key() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfile_Key_XXHash64(t *testing.T) {
+	p := WhatWgSortQuery.With(WithRemoveFragment()).(*Profile)
+	var keys []string
+	for _, input := range []string{"HTTP://Example.com/?b=2&a=1#frag", "http://example.com:80/?a=1&b=2"} {
+		u, err := p.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%v) error = %v", input, err)
+		}
+		key, err := p.Key(u)
+		if err != nil {
+			t.Fatalf("Key() error = %v", err)
+		}
+		if len(key) != 16 {
+			t.Errorf("Key() = %v, want 16 hex digits", key)
+		}
+		keys = append(keys, key)
+	}
+	if keys[0] != keys[1] {
+		t.Errorf("Key() of equivalent urls differ: %v != %v", keys[0], keys[1])
+	}
+}
+
+func TestProfile_Key64(t *testing.T) {
+	p := WhatWg.With(WithKeyHash(SHA256)).(*Profile)
+	u, err := p.Parse("http://example.com/")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	key, _ := WhatWg.Key(u)
+	if got := fmt.Sprintf("%016x", p.Key64(u)); got != key {
+		t.Errorf("Key64() = %v, want %v", got, key)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canonicalizer
+
+import (
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+// Logger receives warnings from a profile, e.g. when a rule fails or the default scheme is added to a url. The
+// arguments after msg are alternating keys and values. This is the signature of the Warn method of *slog.Logger,
+// so a *slog.Logger can be used as is.
+//
+// This API is EXPERIMENTAL.
+type Logger interface {
+	Warn(msg string, keysAndValues ...interface{})
+}
+
+// WithLogger sets a logger which receives the warnings of the profile. By default, profiles are silent.
+//
+// This API is EXPERIMENTAL.
+func WithLogger(l Logger) url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.logger = l
+		},
+	}
+}
+
+// warn logs msg if the profile has a logger.
+func (p *Profile) warn(msg string, keysAndValues ...interface{}) {
+	if p.logger != nil {
+		p.logger.Warn(msg, keysAndValues...)
+	}
+}
+
+// withDefaultScheme returns rawUrl prefixed with the default scheme of the profile.
+func (p *Profile) withDefaultScheme(rawUrl string) string {
+	p.warn("url has no scheme, adding default scheme", "url", rawUrl, "scheme", p.defaultScheme)
+	return p.defaultScheme + "://" + rawUrl
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canonicalizer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+type testLogger struct {
+	msgs []string
+}
+
+func (l *testLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.msgs = append(l.msgs, msg)
+}
+
+func TestWithLogger(t *testing.T) {
+	failing := NamedRule("failing", RuleFunc(func(u *url.Url) error {
+		return errors.New("failed")
+	}))
+	unstable := RuleFunc(func(u *url.Url) error {
+		u.SetPathname(u.Pathname() + "x")
+		return nil
+	})
+	tests := []struct {
+		name     string
+		opts     []url.ParserOption
+		input    string
+		wantMsgs []string
+	}{
+		{"1", nil, "http://example.com/", nil},
+		{"2", []url.ParserOption{WithDefaultScheme("http")}, "example.com", []string{"url has no scheme, adding default scheme"}},
+		{"3", []url.ParserOption{WithRule(failing)}, "http://example.com/", []string{"canonicalization rule failed"}},
+		{"4", []url.ParserOption{WithRule(unstable), WithVerifyIdempotency()}, "http://example.com/",
+			[]string{"canonicalization is not idempotent"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &testLogger{}
+			_, _ = New(append(tt.opts, WithLogger(l))...).Parse(tt.input)
+			if !reflect.DeepEqual(l.msgs, tt.wantMsgs) {
+				t.Errorf("Warn() called with %v, want %v", l.msgs, tt.wantMsgs)
+			}
+		})
+	}
+}
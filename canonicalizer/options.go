@@ -4,17 +4,17 @@ import "github.com/nlnwa/whatwg-url/url"
 
 // canonParserOption configures how we canonicalize a URL.
 type canonParserOption interface {
-	applyProfile(*profile)
+	applyProfile(*Profile)
 }
 
-// funcCanonParserOption wraps a function that modifies profile into an
+// funcCanonParserOption wraps a function that modifies Profile into an
 // implementation of both the url.ParserOption and canonParserOption interface.
 type funcCanonParserOption struct {
 	url.EmptyParserOption
-	f func(*profile)
+	f func(*Profile)
 }
 
-func (cpo *funcCanonParserOption) applyProfile(p *profile) {
+func (cpo *funcCanonParserOption) applyProfile(p *Profile) {
 	cpo.f(p)
 }
 
@@ -23,8 +23,8 @@ func (cpo *funcCanonParserOption) applyProfile(p *profile) {
 // This API is EXPERIMENTAL.
 func WithRemoveUserInfo() url.ParserOption {
 	return &funcCanonParserOption{
-		f: func(p *profile) {
-			p.removeUserInfo = true
+		f: func(p *Profile) {
+			p.setRule(RemoveUserInfo)
 		},
 	}
 }
@@ -34,8 +34,8 @@ func WithRemoveUserInfo() url.ParserOption {
 // This API is EXPERIMENTAL.
 func WithRemovePort() url.ParserOption {
 	return &funcCanonParserOption{
-		f: func(p *profile) {
-			p.removePort = true
+		f: func(p *Profile) {
+			p.setRule(RemovePort)
 		},
 	}
 }
@@ -45,8 +45,57 @@ func WithRemovePort() url.ParserOption {
 // This API is EXPERIMENTAL.
 func WithRemoveFragment() url.ParserOption {
 	return &funcCanonParserOption{
-		f: func(p *profile) {
-			p.removeFragment = true
+		f: func(p *Profile) {
+			p.setRule(RemoveFragment)
+		},
+	}
+}
+
+// WithRemoveQuery removes the query part of the url.
+//
+// This API is EXPERIMENTAL.
+func WithRemoveQuery() url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.setRule(RemoveQuery)
+		},
+	}
+}
+
+// WithKeepFragment keeps the fragment as parsed, even if a rule of the profile removes or modifies it.
+// This allows the fragment to be retained when deriving from a profile which removes it, e.g.
+// GoogleSafeBrowsing.With(WithRemoveQuery(), WithKeepFragment()).
+//
+// This API is EXPERIMENTAL.
+func WithKeepFragment() url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.keepFragment = true
+		},
+	}
+}
+
+// WithUnicodeHost makes Profile.String serialize domains in Unicode form (e.g. "bücher.example" instead of
+// "xn--bcher-kva.example"). This is meant for human-facing output. The parsed url and Profile.Key are not affected.
+//
+// This API is EXPERIMENTAL.
+func WithUnicodeHost() url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.unicodeHost = true
+		},
+	}
+}
+
+// WithVerifyIdempotency makes the profile canonicalize its own output once more and return an IdempotencyError if
+// the second pass gives a different result. This doubles the cost of canonicalization and is meant for testing a
+// profile before it is used to build long-lived indexes.
+//
+// This API is EXPERIMENTAL.
+func WithVerifyIdempotency() url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.verifyIdempotency = true
 		},
 	}
 }
@@ -56,8 +105,8 @@ func WithRemoveFragment() url.ParserOption {
 // This API is EXPERIMENTAL.
 func WithRepeatedPercentDecoding() url.ParserOption {
 	return &funcCanonParserOption{
-		f: func(p *profile) {
-			p.repeatedPercentDecoding = true
+		f: func(p *Profile) {
+			p.setRule(RepeatedPercentDecoding)
 		},
 	}
 }
@@ -67,7 +116,7 @@ func WithRepeatedPercentDecoding() url.ParserOption {
 // This API is EXPERIMENTAL.
 func WithDefaultScheme(scheme string) url.ParserOption {
 	return &funcCanonParserOption{
-		f: func(p *profile) {
+		f: func(p *Profile) {
 			p.defaultScheme = scheme
 		},
 	}
@@ -75,12 +124,270 @@ func WithDefaultScheme(scheme string) url.ParserOption {
 
 // WithSortQuery sets sort type for query parameters.
 // if query should be sorted: 0 = no sort, 1 = sort keys, but leave repeated keys in same order, 2 = sort key,value
+// NoSort removes the sortQuery rule, e.g. from a profile derived from WhatWgSortQuery.
 //
 // This API is EXPERIMENTAL.
 func WithSortQuery(sortType querySort) url.ParserOption {
 	return &funcCanonParserOption{
-		f: func(p *profile) {
-			p.sortQuery = sortType
+		f: func(p *Profile) {
+			if sortType == NoSort {
+				p.removeRules("sortQuery")
+			} else {
+				p.setRule(SortQuery(sortType))
+			}
+		},
+	}
+}
+
+// WithLowercase converts the path and the query to lower case. The host is always lower case.
+//
+// This API is EXPERIMENTAL.
+func WithLowercase() url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.setRule(Lowercase)
+		},
+	}
+}
+
+// WithStripSessionIDs removes session ids (jsessionid, phpsessid, sid, aspsessionid and cfid/cftoken) from the query
+// and ASP.NET session ids from the path.
+//
+// This API is EXPERIMENTAL.
+func WithStripSessionIDs() url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.setRule(StripSessionIDs)
+		},
+	}
+}
+
+// WithStripTrailingSlash removes a trailing slash from the path unless the path is "/".
+//
+// This API is EXPERIMENTAL.
+func WithStripTrailingSlash() url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.setRule(StripTrailingSlash)
+		},
+	}
+}
+
+// WithStripWWW removes a leading "www." label, optionally with digits (e.g. "www2."), from domains.
+// This makes www and non-www variants of a URL canonicalize to the same URL.
+//
+// This API is EXPERIMENTAL.
+func WithStripWWW() url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.setRule(StripWWW)
+		},
+	}
+}
+
+// WithStripDefaultDocument removes the last path segment if it is one of names (e.g. "index.html").
+// If no names are given, DefaultDocuments is used.
+//
+// This API is EXPERIMENTAL.
+func WithStripDefaultDocument(names ...string) url.ParserOption {
+	r := StripDefaultDocument(names...)
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.setRule(r)
+		},
+	}
+}
+
+// WithRemoveDuplicateQueryParams removes query parameters with the same name and value as a previous parameter.
+//
+// This API is EXPERIMENTAL.
+func WithRemoveDuplicateQueryParams() url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.setRule(RemoveDuplicateQueryParams)
+		},
+	}
+}
+
+// WithRemoveRepeatedQueryNames keeps only the last query parameter with a given name.
+//
+// This API is EXPERIMENTAL.
+func WithRemoveRepeatedQueryNames() url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.setRule(RemoveRepeatedQueryNames)
+		},
+	}
+}
+
+// WithRemoveEmptyQueryAndFragment removes a query or fragment which is present, but empty.
+//
+// This API is EXPERIMENTAL.
+func WithRemoveEmptyQueryAndFragment() url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.setRule(RemoveEmptyQueryAndFragment)
+		},
+	}
+}
+
+// WithDecodeUnreserved decodes percent-escapes of unreserved characters in the path and the query (RFC 3986).
+// This is a less aggressive normalization than WithRepeatedPercentDecoding.
+//
+// This API is EXPERIMENTAL.
+func WithDecodeUnreserved() url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.setRule(DecodeUnreserved)
+		},
+	}
+}
+
+// WithUppercasePercentEscapes rewrites the hex digits of percent-escapes to uppercase, e.g. "%3a" becomes "%3A".
+//
+// This API is EXPERIMENTAL.
+func WithUppercasePercentEscapes() url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.setRule(UppercasePercentEscapes)
+		},
+	}
+}
+
+// WithLowercasePercentEscapes rewrites the hex digits of percent-escapes to lowercase, e.g. "%3A" becomes "%3a".
+//
+// This API is EXPERIMENTAL.
+func WithLowercasePercentEscapes() url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.setRule(LowercasePercentEscapes)
+		},
+	}
+}
+
+// WithUpgradeScheme changes the scheme from http to https and from ws to wss for hosts matching one of the patterns.
+// See url.MatchHost for the syntax of the patterns. If no patterns are given, all hosts are upgraded.
+// If a pattern is invalid, the rule returns an error for every url.
+//
+// This API is EXPERIMENTAL.
+func WithUpgradeScheme(patterns ...string) url.ParserOption {
+	var r Rule
+	if len(patterns) == 0 {
+		r = UpgradeScheme(nil)
+	} else if m, err := url.NewHostMatcher(patterns...); err != nil {
+		r = RuleFunc(func(u *url.Url) error {
+			return err
+		})
+	} else {
+		r = UpgradeScheme(m)
+	}
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.setRule(r)
+		},
+	}
+}
+
+// WithStripCacheBusters removes query parameters used to defeat caching. See StripCacheBusters.
+//
+// This API is EXPERIMENTAL.
+func WithStripCacheBusters() url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.setRule(StripCacheBusters)
+		},
+	}
+}
+
+// WithKeyHash sets the hash algorithm used by Profile.Key. Default is XXHash64.
+//
+// This API is EXPERIMENTAL.
+func WithKeyHash(h KeyHash) url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.keyHash = h
+		},
+	}
+}
+
+// WithHostRule adds rules applied only to urls with a host matching one of the patterns, e.g. stripping session ids
+// only on "*.example.org". See url.MatchHost for the syntax of the patterns. If a pattern is invalid, the rule
+// returns an error for every url.
+//
+// This API is EXPERIMENTAL.
+func WithHostRule(patterns []string, rules ...Rule) url.ParserOption {
+	var r Rule
+	if m, err := url.NewHostMatcher(patterns...); err != nil {
+		r = RuleFunc(func(u *url.Url) error {
+			return err
+		})
+	} else {
+		r = ForHosts(m, rules...)
+	}
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.rules = append(p.rules, r)
+		},
+	}
+}
+
+// WithSURTPrefixRule adds rules applied only to urls whose SURT form starts with one of prefixes.
+// See ForSURTPrefixes for how the prefixes are compared.
+//
+// This API is EXPERIMENTAL.
+func WithSURTPrefixRule(prefixes []string, rules ...Rule) url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.rules = append(p.rules, ForSURTPrefixes(prefixes, rules...))
+		},
+	}
+}
+
+// WithRemoveTrackingParameters removes the query parameters in TrackingParameters and the additional names given.
+// To replace the list instead of extending it, use WithRule(RemoveTrackingParameters(names...)).
+//
+// This API is EXPERIMENTAL.
+func WithRemoveTrackingParameters(names ...string) url.ParserOption {
+	r := RemoveTrackingParameters(append(append([]string{}, TrackingParameters...), names...)...)
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.setRule(r)
+		},
+	}
+}
+
+// WithRule adds custom rules to the profile. Together with the other options adding rules, this allows
+// custom rules to be applied between the stock rules.
+//
+// This API is EXPERIMENTAL.
+func WithRule(rules ...Rule) url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.rules = append(p.rules, rules...)
+		},
+	}
+}
+
+// WithoutRules removes the rules with the given names (see RuleName) from the profile, e.g.
+// Heritrix.With(WithoutRules("stripWWW")). Names not in the profile are ignored.
+//
+// This API is EXPERIMENTAL.
+func WithoutRules(names ...string) url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.removeRules(names...)
+		},
+	}
+}
+
+// WithReplaceRule replaces the rule with the given name (see RuleName) by r, keeping its position in the chain.
+// If the profile has no rule with that name, r is added after the other rules.
+//
+// This API is EXPERIMENTAL.
+func WithReplaceRule(name string, r Rule) url.ParserOption {
+	return &funcCanonParserOption{
+		f: func(p *Profile) {
+			p.replaceRule(name, r)
 		},
 	}
 }
@@ -17,7 +17,6 @@
 package canonicalizer
 
 import (
-	"regexp"
 	"strings"
 
 	"golang.org/x/text/encoding/charmap"
@@ -51,15 +50,12 @@ var GoogleSafeBrowsing = New(
 	url.WithAcceptInvalidCodepoints(),
 	url.WithPercentEncodeSinglePercentSign(),
 	url.WithPreParseHostFunc(func(u *url.Url, host string) string {
-		host = strings.Trim(host, ".")
-		var re = regexp.MustCompile(`\.\.+`)
-		host = re.ReplaceAllString(host, ".")
-		return host
+		return collapseDots(host)
 	}),
 	url.WithSkipEqualsForEmptySearchParamsValue(),
+	WithRepeatedPercentDecoding(),
 	WithRemovePort(),
 	WithRemoveFragment(),
-	WithRepeatedPercentDecoding(),
 	WithDefaultScheme("http"),
 )
 
@@ -74,9 +70,7 @@ var Semantic = New(
 	url.WithEncodingOverride(charmap.ISO8859_1),
 	url.WithPreParseHostFunc(func(u *url.Url, host string) string {
 		if host != "" {
-			host = strings.Trim(host, ".")
-			var re = regexp.MustCompile(`\.\.+`)
-			host = re.ReplaceAllString(host, ".")
+			host = collapseDots(host)
 			if host == "" {
 				host = "0.0.0.0"
 			}
@@ -92,9 +86,56 @@ var Semantic = New(
 		"wss":    "443",
 		"gopher": "70",
 	}),
-	WithRemoveUserInfo(),
 	WithDefaultScheme("http"),
-	WithSortQuery(SortKeys),
 	WithRepeatedPercentDecoding(),
+	WithRemoveUserInfo(),
 	WithRemoveFragment(),
+	WithSortQuery(SortKeys),
 )
+
+// Heritrix is a profile that follows the canonicalization rules of the default canonicalization policy in
+// [Heritrix] (lowercase, strip userinfo, strip www, strip session ids, fix up query string and collapse
+// consecutive slashes). The fragment is removed since Heritrix never keeps it.
+//
+// Some differences remain because the url is parsed according to the WHATWG standard before the rules are
+// applied: percent-encoding follows the standard, IPv4 addresses are normalized (e.g. "0x7f.1" becomes "127.0.0.1"),
+// backslashes in special URLs are treated as slashes and dot segments are always resolved.
+//
+// [Heritrix]: https://github.com/internetarchive/heritrix3
+var Heritrix = New(
+	url.WithCollapseConsecutiveSlashes(),
+	url.WithAcceptInvalidCodepoints(),
+	WithLowercase(),
+	WithRemoveUserInfo(),
+	WithStripWWW(),
+	WithStripSessionIDs(),
+	WithRule(fixupQueryString),
+	WithRemoveEmptyQueryAndFragment(),
+	WithRemoveFragment(),
+)
+
+// fixupQueryString removes leading and trailing '&' from the query like Heritrix' FixupQueryString rule.
+var fixupQueryString Rule = NamedRule("fixupQueryString", RuleFunc(func(u *url.Url) error {
+	if query := strings.Trim(u.Query(), "&"); query != u.Query() {
+		u.SetSearch(query)
+	}
+	return nil
+}))
+
+// collapseDots removes leading and trailing dots from host and replaces consecutive dots with a single dot,
+// e.g. ".www..example.com." becomes "www.example.com".
+func collapseDots(host string) string {
+	host = strings.Trim(host, ".")
+	if !strings.Contains(host, "..") {
+		return host
+	}
+	var sb strings.Builder
+	sb.Grow(len(host))
+	for i := 0; i < len(host); i++ {
+		if host[i] == '.' && host[i-1] == '.' {
+			continue
+		}
+		sb.WriteByte(host[i])
+	}
+	return sb.String()
+}
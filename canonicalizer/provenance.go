@@ -0,0 +1,53 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canonicalizer
+
+import "github.com/nlnwa/whatwg-url/url"
+
+// Change is a modification of one url component made by a canonicalization rule.
+type Change struct {
+	// Rule is the index of the rule in Profile.Rules, or -1 if the change was made by the profile itself
+	// (e.g. restoring the fragment for WithKeepFragment).
+	Rule int
+	// Name is the name of the rule as returned by RuleName.
+	Name string
+	// Component is the name of the changed component: scheme, username, password, host, port, path, query or fragment.
+	Component string
+	// Before is the serialized component before the rule was applied.
+	Before string
+	// After is the serialized component after the rule was applied.
+	After string
+}
+
+var componentNames = [...]string{"scheme", "username", "password", "host", "port", "path", "query", "fragment"}
+
+// components holds the serialized components of an url in the order of componentNames.
+type components [len(componentNames)]string
+
+func componentsOf(u *url.Url) components {
+	return components{u.Scheme(), u.Username(), u.Password(), u.Hostname(), u.Port(), u.Pathname(), u.Search(), u.Hash()}
+}
+
+// appendChanges appends a Change for each component which differs between before and after.
+func appendChanges(changes []Change, rule int, name string, before, after components) []Change {
+	for i := range before {
+		if before[i] != after[i] {
+			changes = append(changes, Change{Rule: rule, Name: name, Component: componentNames[i], Before: before[i], After: after[i]})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canonicalizer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+func TestProfile_ParseWithProvenance(t *testing.T) {
+	custom := RuleFunc(func(u *url.Url) error {
+		u.SetPathname("/custom")
+		return nil
+	})
+	tests := []struct {
+		name  string
+		p     *Profile
+		input string
+		want  []Change
+	}{
+		{"1", New(WithRemoveUserInfo(), WithRemoveFragment(), WithSortQuery(SortKeys)), "http://user:pw@example.com/?b=1&a=2#c", []Change{
+			{Rule: 0, Name: "removeUserInfo", Component: "username", Before: "user", After: ""},
+			{Rule: 0, Name: "removeUserInfo", Component: "password", Before: "pw", After: ""},
+			{Rule: 1, Name: "removeFragment", Component: "fragment", Before: "#c", After: ""},
+			{Rule: 2, Name: "sortQuery", Component: "query", Before: "?b=1&a=2", After: "?a=2&b=1"},
+		}},
+		{"2", New(WithRemoveUserInfo(), WithRemoveFragment()), "http://example.com/", []Change{}},
+		{"3", New(WithRule(custom, NamedRule("named", custom))), "http://example.com/a", []Change{
+			{Rule: 0, Name: "", Component: "path", Before: "/a", After: "/custom"},
+		}},
+		{"4", GoogleSafeBrowsing.With(WithKeepFragment()).(*Profile), "http://example.com:8080/#c", []Change{
+			{Rule: 1, Name: "removePort", Component: "port", Before: "8080", After: ""},
+			{Rule: 2, Name: "removeFragment", Component: "fragment", Before: "#c", After: ""},
+			{Rule: -1, Name: "keepFragment", Component: "fragment", Before: "", After: "#c"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, got, err := tt.p.ParseWithProvenance(tt.input)
+			if err != nil {
+				t.Fatalf("ParseWithProvenance(%v) error = %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseWithProvenance(%v) changes = %+v, want %+v", tt.input, got, tt.want)
+			}
+			want, err := tt.p.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", tt.input, err)
+			}
+			if u.String() != want.String() {
+				t.Errorf("ParseWithProvenance(%v) = %v, want %v", tt.input, u, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,408 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canonicalizer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nlnwa/whatwg-url/percent"
+	"github.com/nlnwa/whatwg-url/surt"
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+// Rule is a canonicalization step applied to a parsed url.
+type Rule interface {
+	// Apply canonicalizes u in place.
+	Apply(u *url.Url) error
+}
+
+// RuleFunc is an adapter allowing an ordinary function to be used as a Rule.
+type RuleFunc func(u *url.Url) error
+
+// Apply calls f(u).
+func (f RuleFunc) Apply(u *url.Url) error {
+	return f(u)
+}
+
+// NamedRule returns a rule which applies r and has the given name. The name identifies the rule in the changes
+// reported by Profile.ParseWithProvenance. The stock rules are named after their configuration option names.
+func NamedRule(name string, r Rule) Rule {
+	return &namedRule{name: name, Rule: r}
+}
+
+type namedRule struct {
+	name string
+	Rule
+}
+
+// String returns the name of the rule.
+func (r *namedRule) String() string {
+	return r.name
+}
+
+// RuleName returns the name of r if it implements fmt.Stringer, e.g. rules created with NamedRule.
+// Otherwise the empty string is returned.
+func RuleName(r Rule) string {
+	if s, ok := r.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return ""
+}
+
+// RepeatedPercentDecoding repeatedly percent decodes host, path, query and fragment until there are no more
+// percent-escapes and then percent encodes the result.
+var RepeatedPercentDecoding Rule = NamedRule("repeatedPercentDecoding", RuleFunc(func(u *url.Url) error {
+	if u.Hostname() != "" {
+		u.SetHostname(decodeEncode(u.Hostname(), url.HostPercentEncodeSet))
+	}
+	if u.Pathname() != "" {
+		u.SetPathname(decodeEncode(u.Pathname(), LaxPathPercentEncodeSet))
+	}
+	if u.Search() != "" {
+		u.SearchParams().Iterate(func(pair *url.NameValuePair) {
+			pair.Name = decodeEncode(pair.Name, RepeatedQueryPercentDecodeSet)
+			pair.Value = decodeEncode(pair.Value, RepeatedQueryPercentDecodeSet)
+		})
+	}
+	if u.Hash() != "" {
+		u.SetHash(decodeEncode(strings.TrimPrefix(u.Hash(), "#"), url.HostPercentEncodeSet))
+	}
+	return nil
+}))
+
+// RemovePort removes the port.
+var RemovePort Rule = NamedRule("removePort", RuleFunc(func(u *url.Url) error {
+	u.SetPort("")
+	return nil
+}))
+
+// RemoveUserInfo removes username and password.
+var RemoveUserInfo Rule = NamedRule("removeUserInfo", RuleFunc(func(u *url.Url) error {
+	u.SetUsername("")
+	u.SetPassword("")
+	return nil
+}))
+
+// RemoveFragment removes the fragment.
+var RemoveFragment Rule = NamedRule("removeFragment", RuleFunc(func(u *url.Url) error {
+	u.SetHash("")
+	return nil
+}))
+
+// RemoveQuery removes the query.
+var RemoveQuery Rule = NamedRule("removeQuery", RuleFunc(func(u *url.Url) error {
+	u.SetSearch("")
+	return nil
+}))
+
+// Lowercase converts the path and the query to lower case.
+var Lowercase Rule = NamedRule("lowercase", RuleFunc(func(u *url.Url) error {
+	if !u.OpaquePath() {
+		u.SetPathname(strings.ToLower(u.Pathname()))
+	}
+	if u.Search() != "" {
+		u.SetSearch(strings.ToLower(u.Search()))
+	}
+	return nil
+}))
+
+// StripSessionIDs removes session ids from the path and the query.
+var StripSessionIDs Rule = NamedRule("stripSessionIDs", RuleFunc(func(u *url.Url) error {
+	stripSessionIDs(u)
+	return nil
+}))
+
+// StripTrailingSlash removes a trailing slash from the path unless the path is "/".
+var StripTrailingSlash Rule = NamedRule("stripTrailingSlash", RuleFunc(func(u *url.Url) error {
+	if u.OpaquePath() {
+		return nil
+	}
+	if path := u.Pathname(); len(path) > 1 && strings.HasSuffix(path, "/") {
+		u.SetPathname(path[:len(path)-1])
+	}
+	return nil
+}))
+
+var wwwPrefix = regexp.MustCompile(`^www\d*\.`)
+
+// StripWWW removes a leading "www." label, optionally with digits (e.g. "www2."), from domains.
+// The host is left unchanged if nothing would remain after the removal.
+var StripWWW Rule = NamedRule("stripWWW", RuleFunc(func(u *url.Url) error {
+	h := u.ParsedHost()
+	if h == nil || h.Kind != url.DomainHost {
+		return nil
+	}
+	if stripped := wwwPrefix.ReplaceAllString(h.ASCII, ""); stripped != h.ASCII && stripped != "" && stripped != "." {
+		u.SetHostname(stripped)
+	}
+	return nil
+}))
+
+// DefaultDocuments are the file names removed by StripDefaultDocument when no names are given.
+var DefaultDocuments = []string{"index.html", "index.htm", "index.php", "default.asp", "default.aspx"}
+
+// StripDefaultDocument returns a rule removing the last path segment if it is one of names, leaving the path
+// ending with a slash (e.g. "/a/index.html" becomes "/a/"). Names are compared case-insensitively with the
+// percent decoded segment. If no names are given, DefaultDocuments is used.
+func StripDefaultDocument(names ...string) Rule {
+	if len(names) == 0 {
+		names = DefaultDocuments
+	}
+	return NamedRule("stripDefaultDocument", RuleFunc(func(u *url.Url) error {
+		segments := u.PathSegments()
+		if len(segments) == 0 {
+			return nil
+		}
+		last := percent.Decode(segments[len(segments)-1])
+		for _, name := range names {
+			if strings.EqualFold(last, name) {
+				segments[len(segments)-1] = ""
+				u.SetPathname("/" + strings.Join(segments, "/"))
+				return nil
+			}
+		}
+		return nil
+	}))
+}
+
+// RemoveDuplicateQueryParams removes query parameters with the same name and value as a previous parameter.
+// e.g. "?a=1&b=2&a=1" becomes "?a=1&b=2".
+var RemoveDuplicateQueryParams Rule = NamedRule("removeDuplicateQueryParams", RuleFunc(func(u *url.Url) error {
+	if u.Search() == "" {
+		return nil
+	}
+	seen := make(map[url.NameValuePair]bool)
+	u.SearchParams().Filter(func(pair *url.NameValuePair) bool {
+		if seen[*pair] {
+			return false
+		}
+		seen[*pair] = true
+		return true
+	})
+	return nil
+}))
+
+// RemoveRepeatedQueryNames keeps only the last query parameter with a given name.
+// e.g. "?a=1&b=2&a=3" becomes "?b=2&a=3".
+var RemoveRepeatedQueryNames Rule = NamedRule("removeRepeatedQueryNames", RuleFunc(func(u *url.Url) error {
+	if u.Search() == "" {
+		return nil
+	}
+	last := make(map[string]*url.NameValuePair)
+	u.SearchParams().Iterate(func(pair *url.NameValuePair) {
+		last[pair.Name] = pair
+	})
+	u.SearchParams().Filter(func(pair *url.NameValuePair) bool {
+		return last[pair.Name] == pair
+	})
+	return nil
+}))
+
+// RemoveEmptyQueryAndFragment removes a query or fragment which is present, but empty.
+// e.g. "http://example.com/?#" becomes "http://example.com/".
+var RemoveEmptyQueryAndFragment Rule = NamedRule("removeEmptyQueryAndFragment", RuleFunc(func(u *url.Url) error {
+	if u.Search() == "" {
+		u.SetSearch("")
+	}
+	if u.Hash() == "" {
+		u.SetHash("")
+	}
+	return nil
+}))
+
+// DecodeUnreserved decodes percent-escapes of unreserved characters (ALPHA, DIGIT, "-", ".", "_" and "~") in the
+// path and the query as described in RFC 3986 section 6.2.2.2. Other percent-escapes are kept as they are.
+// e.g. "/%7Euser/%41%2F" becomes "/~user/A%2F".
+var DecodeUnreserved Rule = NamedRule("decodeUnreserved", RuleFunc(func(u *url.Url) error {
+	if !u.OpaquePath() {
+		if path := decodeUnreserved(u.Pathname()); path != u.Pathname() {
+			u.SetPathname(path)
+		}
+	}
+	if query := decodeUnreserved(u.Query()); query != u.Query() {
+		u.SetSearch(query)
+	}
+	return nil
+}))
+
+// decodeUnreserved decodes percent-escapes of unreserved characters.
+func decodeUnreserved(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+	sb := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) && percent.IsHex(s[i+1]) && percent.IsHex(s[i+2]) {
+			if b := percent.Unhex(s[i+1])<<4 | percent.Unhex(s[i+2]); isUnreserved(b) {
+				sb.WriteByte(b)
+				i += 2
+				continue
+			}
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+func isUnreserved(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9' ||
+		b == '-' || b == '.' || b == '_' || b == '~'
+}
+
+// UppercasePercentEscapes rewrites the hex digits of percent-escapes in the userinfo, path, query and fragment to
+// uppercase without decoding them, e.g. "%3a" becomes "%3A".
+var UppercasePercentEscapes = percentEscapeCase("uppercasePercentEscapes", strings.ToUpper)
+
+// LowercasePercentEscapes rewrites the hex digits of percent-escapes in the userinfo, path, query and fragment to
+// lowercase without decoding them, e.g. "%3A" becomes "%3a".
+var LowercasePercentEscapes = percentEscapeCase("lowercasePercentEscapes", strings.ToLower)
+
+// percentEscapeCase returns a rule with the given name applying convert to the hex digits of all percent-escapes.
+func percentEscapeCase(name string, convert func(string) string) Rule {
+	return NamedRule(name, RuleFunc(func(u *url.Url) error {
+		if s := convertPercentEscapes(u.Username(), convert); s != u.Username() {
+			u.SetUsername(s)
+		}
+		if s := convertPercentEscapes(u.Password(), convert); s != u.Password() {
+			u.SetPassword(s)
+		}
+		if !u.OpaquePath() {
+			if s := convertPercentEscapes(u.Pathname(), convert); s != u.Pathname() {
+				u.SetPathname(s)
+			}
+		}
+		if s := convertPercentEscapes(u.Query(), convert); s != u.Query() {
+			u.SetSearch(s)
+		}
+		if s := convertPercentEscapes(u.Fragment(), convert); s != u.Fragment() {
+			u.SetHash(s)
+		}
+		return nil
+	}))
+}
+
+// convertPercentEscapes applies convert to the hex digits of all percent-escapes in s.
+func convertPercentEscapes(s string, convert func(string) string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+	sb := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) && percent.IsHex(s[i+1]) && percent.IsHex(s[i+2]) {
+			sb.WriteByte('%')
+			sb.WriteString(convert(s[i+1 : i+3]))
+			i += 2
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+// UpgradeScheme returns a rule changing the scheme from http to https and from ws to wss for hosts matched by m.
+// If m is nil, all hosts are upgraded. A port equal to the default port of the new scheme is removed
+// (e.g. "http://example.com:443/" becomes "https://example.com/"), other explicit ports are kept.
+func UpgradeScheme(m *url.HostMatcher) Rule {
+	return NamedRule("upgradeScheme", RuleFunc(func(u *url.Url) error {
+		var scheme string
+		switch u.Scheme() {
+		case "http":
+			scheme = "https"
+		case "ws":
+			scheme = "wss"
+		default:
+			return nil
+		}
+		if m != nil {
+			h := u.ParsedHost()
+			if h == nil || !m.MatchHost(h) {
+				return nil
+			}
+		}
+		u.SetProtocol(scheme)
+		return nil
+	}))
+}
+
+// SortQuery returns a rule sorting the query parameters. The query is serialized as
+// application/x-www-form-urlencoded also when the parameters are already sorted, so that e.g. "a+b" and "a%20b"
+// get the same canonical form whether or not the order changed.
+func SortQuery(sortType querySort) Rule {
+	return NamedRule("sortQuery", RuleFunc(func(u *url.Url) error {
+		if sortType == NoSort || u.Search() == "" {
+			return nil
+		}
+		// Parse the query again, since rules like RepeatedPercentDecoding leave percent-encoded strings in the
+		// parameters.
+		u.SetSearch(u.Search())
+		sp := u.SearchParams()
+		switch sortType {
+		case SortKeys:
+			sp.Sort()
+		case SortParameter:
+			sp.SortAbsolute()
+		}
+		if q := sp.String(); q != u.Query() {
+			u.SetSearch("?" + q)
+		}
+		return nil
+	}))
+}
+
+// ForHosts returns a rule applying rules, in order, only to urls with a host matched by m.
+func ForHosts(m *url.HostMatcher, rules ...Rule) Rule {
+	return NamedRule("forHosts", RuleFunc(func(u *url.Url) error {
+		h := u.ParsedHost()
+		if h == nil || !m.MatchHost(h) {
+			return nil
+		}
+		return applyRules(u, rules)
+	}))
+}
+
+// ForSURTPrefixes returns a rule applying rules, in order, only to urls whose SURT form starts with one of prefixes.
+// A prefix including the scheme (e.g. "http://(org,example,") is compared with the default SURT form. Other
+// prefixes (e.g. "(org,example," or "org,example,") are compared with the SURT form without scheme.
+func ForSURTPrefixes(prefixes []string, rules ...Rule) Rule {
+	withScheme, withoutScheme := surt.NewPrefixTrie(), surt.NewPrefixTrie()
+	for _, prefix := range prefixes {
+		if strings.Contains(prefix, "://") {
+			withScheme.Add(prefix)
+		} else {
+			withoutScheme.Add(strings.TrimPrefix(prefix, "("))
+		}
+	}
+	return NamedRule("forSURTPrefixes", RuleFunc(func(u *url.Url) error {
+		if (withScheme.Len() > 0 && withScheme.MatchUrl(u)) ||
+			(withoutScheme.Len() > 0 && withoutScheme.MatchUrl(u, surt.WithScheme(false), surt.WithOpenParenthesis(false))) {
+			return applyRules(u, rules)
+		}
+		return nil
+	}))
+}
+
+// applyRules applies rules to u until a rule returns an error.
+func applyRules(u *url.Url, rules []Rule) error {
+	for _, r := range rules {
+		if err := r.Apply(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
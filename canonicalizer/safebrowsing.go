@@ -0,0 +1,93 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canonicalizer
+
+import (
+	"strings"
+
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+// SafeBrowsingExpressions returns the host suffix/path prefix expressions used for [Google Safe Browsing] lookups.
+// u is expected to be canonicalized with the GoogleSafeBrowsing profile.
+//
+// At most 30 expressions are returned, combining up to five host suffixes with up to six path prefixes. The host
+// suffixes are the exact hostname and the up to four hostnames formed by starting with the last five components and
+// successively removing the leading component. The top-level domain is skipped and IP addresses are only used as is.
+// The path prefixes are the exact path with and without the query and the up to four paths formed by starting at the
+// root and successively appending path components, including a trailing slash.
+//
+// e.g. "http://a.b.c/1/2.html?param=1" gives the expressions "a.b.c/1/2.html?param=1", "a.b.c/1/2.html", "a.b.c/",
+// "a.b.c/1/", "b.c/1/2.html?param=1", "b.c/1/2.html", "b.c/" and "b.c/1/".
+//
+// [Google Safe Browsing]: https://developers.google.com/safe-browsing/v4/urls-hashing#suffixprefix-expressions
+func SafeBrowsingExpressions(u *url.Url) []string {
+	hosts := safeBrowsingHostSuffixes(u)
+	paths := safeBrowsingPathPrefixes(u)
+	expressions := make([]string, 0, len(hosts)*len(paths))
+	for _, h := range hosts {
+		for _, p := range paths {
+			expressions = append(expressions, h+p)
+		}
+	}
+	return expressions
+}
+
+func safeBrowsingHostSuffixes(u *url.Url) []string {
+	host := u.Hostname()
+	hosts := []string{host}
+	if u.IsIPv4() || u.IsIPv6() {
+		return hosts
+	}
+	components := strings.Split(host, ".")
+	start := len(components) - 5
+	if start < 1 {
+		start = 1
+	}
+	for i := start; i < len(components)-1; i++ {
+		hosts = append(hosts, strings.Join(components[i:], "."))
+	}
+	return hosts
+}
+
+func safeBrowsingPathPrefixes(u *url.Url) []string {
+	path := u.Pathname()
+	pathAndQuery := strings.TrimPrefix(u.Href(true), u.Protocol()+"//"+u.Host())
+	paths := []string{pathAndQuery}
+	seen := map[string]bool{pathAndQuery: true}
+	add := func(p string) {
+		if !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+	}
+	add(path)
+	if u.OpaquePath() {
+		return paths
+	}
+
+	segments := u.PathSegments()
+	prefix := "/"
+	for i := 0; i < 4; i++ {
+		add(prefix)
+		if i >= len(segments)-1 {
+			break
+		}
+		prefix += segments[i] + "/"
+	}
+	return paths
+}
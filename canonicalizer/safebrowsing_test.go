@@ -0,0 +1,65 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canonicalizer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSafeBrowsingExpressions(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"1", "http://a.b.c/1/2.html?param=1", []string{
+			"a.b.c/1/2.html?param=1", "a.b.c/1/2.html", "a.b.c/", "a.b.c/1/",
+			"b.c/1/2.html?param=1", "b.c/1/2.html", "b.c/", "b.c/1/",
+		}},
+		{"2", "http://a.b.c.d.e.f.g/1.html", []string{
+			"a.b.c.d.e.f.g/1.html", "a.b.c.d.e.f.g/",
+			"c.d.e.f.g/1.html", "c.d.e.f.g/",
+			"d.e.f.g/1.html", "d.e.f.g/",
+			"e.f.g/1.html", "e.f.g/",
+			"f.g/1.html", "f.g/",
+		}},
+		{"3", "http://1.2.3.4/1/", []string{"1.2.3.4/1/", "1.2.3.4/"}},
+		{"4", "http://a.b/saw-cgi/eBayISAPI.dll/", []string{
+			"a.b/saw-cgi/eBayISAPI.dll/", "a.b/", "a.b/saw-cgi/",
+		}},
+		{"5", "http://a.b/c/d/e/f/g/h", []string{
+			"a.b/c/d/e/f/g/h", "a.b/", "a.b/c/", "a.b/c/d/", "a.b/c/d/e/",
+		}},
+		{"6", "http://www.google.com/q?", []string{
+			"www.google.com/q?", "www.google.com/q", "www.google.com/",
+			"google.com/q?", "google.com/q", "google.com/",
+		}},
+		{"7", "http://localhost:8080/", []string{"localhost/"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := GoogleSafeBrowsing.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", tt.input, err)
+			}
+			if got := SafeBrowsingExpressions(u); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SafeBrowsingExpressions(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
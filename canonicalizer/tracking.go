@@ -0,0 +1,97 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canonicalizer
+
+import (
+	"strings"
+
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+// TrackingParametersVersion identifies the revision of TrackingParameters. It changes whenever the list changes,
+// so it can be stored together with keys created by profiles removing tracking parameters.
+const TrackingParametersVersion = "2026-10-16"
+
+// TrackingParameters are the names of query parameters used for tracking clicks and campaigns, which are removed by
+// RemoveTrackingParameters when no names are given. A name ending with '*' matches all names with that prefix.
+//
+// The list is not meant to be modified. Use WithRemoveTrackingParameters to extend it or RemoveTrackingParameters
+// to replace it.
+var TrackingParameters = []string{
+	// Google Analytics and Ads
+	"utm_*", "gclid", "gclsrc", "dclid", "gbraid", "wbraid", "_ga", "_gl",
+	// Meta
+	"fbclid", "igshid",
+	// Microsoft
+	"msclkid",
+	// Mailchimp
+	"mc_cid", "mc_eid",
+	// HubSpot
+	"_hsenc", "_hsmi", "__hssc", "__hstc", "__hsfp", "hsctatracking",
+	// Marketo
+	"mkt_tok",
+	// Yandex
+	"yclid", "_openstat",
+	// Twitter/X, TikTok and LinkedIn
+	"twclid", "ttclid", "li_fat_id",
+	// Adobe
+	"s_cid", "ef_id",
+	// Others
+	"oly_anon_id", "oly_enc_id", "rb_clickid", "vero_id", "vero_conv", "wickedid", "srsltid",
+}
+
+// RemoveTrackingParameters returns a rule removing query parameters with one of the given names. Names are compared
+// case-insensitively and a name ending with '*' matches all names with that prefix. If no names are given,
+// TrackingParameters is used. The query is removed if no parameters remain.
+func RemoveTrackingParameters(names ...string) Rule {
+	if len(names) == 0 {
+		names = TrackingParameters
+	}
+	var exact = make(map[string]bool)
+	var prefixes []string
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if strings.HasSuffix(name, "*") {
+			prefixes = append(prefixes, strings.TrimSuffix(name, "*"))
+		} else {
+			exact[name] = true
+		}
+	}
+
+	return NamedRule("removeTrackingParameters", RuleFunc(func(u *url.Url) error {
+		if u.Search() == "" {
+			return nil
+		}
+		sp := u.SearchParams()
+		sp.Filter(func(pair *url.NameValuePair) bool {
+			name := strings.ToLower(pair.Name)
+			if exact[name] {
+				return false
+			}
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(name, prefix) {
+					return false
+				}
+			}
+			return true
+		})
+		if u.Search() == "" {
+			u.SetSearch("")
+		}
+		return nil
+	}))
+}
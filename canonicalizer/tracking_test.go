@@ -0,0 +1,50 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canonicalizer
+
+import (
+	"testing"
+
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+func TestRemoveTrackingParameters(t *testing.T) {
+	tests := []struct {
+		name  string
+		p     url.Parser
+		input string
+		want  string
+	}{
+		{"1", New(WithRemoveTrackingParameters()), "http://example.com/?id=1&utm_source=x&UTM_Medium=y&fbclid=z", "http://example.com/?id=1"},
+		{"2", New(WithRemoveTrackingParameters()), "http://example.com/a?gclid=1&mc_eid=2#f", "http://example.com/a#f"},
+		{"3", New(WithRemoveTrackingParameters()), "http://example.com/?utm=1&ref=2", "http://example.com/?utm=1&ref=2"},
+		{"4", New(WithRemoveTrackingParameters("ref")), "http://example.com/?utm_id=1&ref=2&q=3", "http://example.com/?q=3"},
+		{"5", New(WithRule(RemoveTrackingParameters("ref"))), "http://example.com/?utm_id=1&ref=2&q=3", "http://example.com/?utm_id=1&q=3"},
+		{"6", New(WithRemoveTrackingParameters()), "http://example.com/?", "http://example.com/?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.p.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", tt.input, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Parse(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canonicalizer
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/nlnwa/whatwg-url/surt"
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+// The session id patterns are the same as used by the Internet Archive's URL canonicalizer.
+var pathSessionIDs = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)^(.*/)(\((?:[a-z]\([0-9a-z]{24}\))+\)/)([^?]+\.aspx.*)$`),
+	regexp.MustCompile(`(?i)^(.*/)(\([0-9a-z]{24}\)/)([^?]+\.aspx.*)$`),
+}
+
+var querySessionIDs = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)^(.*)(?:jsessionid=[0-9a-z]{32})(?:&(.*))?$`),
+	regexp.MustCompile(`(?i)^(.*)(?:phpsessid=[0-9a-z]{32})(?:&(.*))?$`),
+	regexp.MustCompile(`(?i)^(.*)(?:sid=[0-9a-z]{32})(?:&(.*))?$`),
+	regexp.MustCompile(`(?i)^(.*)(?:aspsessionid[a-z]{8}=[a-z]{24})(?:&(.*))?$`),
+	regexp.MustCompile(`(?i)^(.*)(?:cfid=[^&]+&cftoken=[^&]+)(?:&(.*))?$`),
+}
+
+// stripSessionIDs removes session ids from the path and the query of u.
+func stripSessionIDs(u *url.Url) {
+	if !u.OpaquePath() {
+		path := u.Pathname()
+		for _, re := range pathSessionIDs {
+			path = re.ReplaceAllString(path, "$1$3")
+		}
+		if path != u.Pathname() {
+			u.SetPathname(path)
+		}
+	}
+	if query := u.Query(); query != "" {
+		for _, re := range querySessionIDs {
+			query = re.ReplaceAllString(query, "$1$2")
+		}
+		if query != u.Query() {
+			u.SetSearch(strings.TrimSuffix(query, "&"))
+		}
+	}
+}
+
+// WarcUrlKey is a profile that follows the canonicalization used for the urlkey field in CDX and CDXJ files
+// by OpenWayback and pywb. Use UrlKey to get the urlkey, which is the SURT form of the canonicalized URL.
+var WarcUrlKey = New(
+	url.WithLaxHostParsing(),
+	url.WithQueryPercentEncodeSet(LaxQueryPercentEncodeSet),
+	url.WithCollapseConsecutiveSlashes(),
+	url.WithAcceptInvalidCodepoints(),
+	url.WithPercentEncodeSinglePercentSign(),
+	url.WithPreParseHostFunc(func(u *url.Url, host string) string {
+		return collapseDots(host)
+	}),
+	url.WithSkipEqualsForEmptySearchParamsValue(),
+	WithRepeatedPercentDecoding(),
+	WithStripWWW(),
+	WithRemoveUserInfo(),
+	WithRemoveFragment(),
+	WithLowercase(),
+	WithStripSessionIDs(),
+	WithStripTrailingSlash(),
+	WithSortQuery(SortParameter),
+	WithDefaultScheme("http"),
+)
+
+// UrlKey canonicalizes rawUrl with the WarcUrlKey profile and returns the urlkey used in CDX and CDXJ files,
+// e.g. "com,example)/path?a=1&b=2" for "http://www.example.com/path/?b=2&a=1#frag".
+// The scheme is not part of the urlkey, so http and https URLs get the same key.
+func UrlKey(rawUrl string) (string, error) {
+	return urlKey(WarcUrlKey, rawUrl)
+}
+
+// Pywb is a profile that follows the canonicalization done by pywb's canonicalize() when looking up URLs for replay.
+// It is the WarcUrlKey profile extended with removal of cache busting query parameters, like the "_=1712345678"
+// parameter added by jQuery, which pywb ignores when looking up URLs from CDNs and script loaders.
+// Use PywbKey to get the lookup key.
+//
+// Pywb's fuzzy matching, which is done at lookup time when there is no exact match, is not part of the profile.
+var Pywb = WarcUrlKey.With(WithStripCacheBusters()).(*Profile)
+
+// PywbKey canonicalizes rawUrl with the Pywb profile and returns the key used by pywb for replay lookups.
+func PywbKey(rawUrl string) (string, error) {
+	return urlKey(Pywb, rawUrl)
+}
+
+// StripCacheBusters removes query parameters used to defeat caching. These are parameters with a numeric value
+// named "_", "cb" or with a name containing "cache" (e.g. "nocache" or "cachebust").
+var StripCacheBusters Rule = NamedRule("stripCacheBusters", RuleFunc(func(u *url.Url) error {
+	if u.Search() == "" {
+		return nil
+	}
+	u.SearchParams().Filter(func(pair *url.NameValuePair) bool {
+		if pair.Value == "" || strings.Trim(pair.Value, "0123456789") != "" {
+			return true
+		}
+		name := strings.ToLower(pair.Name)
+		return name != "_" && name != "cb" && !strings.Contains(name, "cache")
+	})
+	return nil
+}))
+
+func urlKey(p *Profile, rawUrl string) (string, error) {
+	u, err := p.Parse(rawUrl)
+	if err != nil {
+		return "", err
+	}
+	return surt.String(u, surt.WithScheme(false), surt.WithTrailingComma(false), surt.WithOpenParenthesis(false),
+		surt.WithUserinfo(false)), nil
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canonicalizer
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// The primes are variables since the initial accumulators wrap around, which is not allowed for constants.
+var (
+	prime64v1 uint64 = 11400714785074694791
+	prime64v2 uint64 = 14029467366897019727
+	prime64v3 uint64 = 1609587929392839161
+	prime64v4 uint64 = 9650029242287828579
+	prime64v5 uint64 = 2870177450012600261
+)
+
+// xxhash64 implements the 64-bit xxHash algorithm (https://github.com/Cyan4973/xxHash) with seed 0.
+func xxhash64(b []byte) uint64 {
+	n := len(b)
+	var h uint64
+
+	if n >= 32 {
+		v1 := prime64v1 + prime64v2
+		v2 := prime64v2
+		v3 := uint64(0)
+		v4 := -prime64v1
+		for len(b) >= 32 {
+			v1 = xxhashRound(v1, binary.LittleEndian.Uint64(b[0:8]))
+			v2 = xxhashRound(v2, binary.LittleEndian.Uint64(b[8:16]))
+			v3 = xxhashRound(v3, binary.LittleEndian.Uint64(b[16:24]))
+			v4 = xxhashRound(v4, binary.LittleEndian.Uint64(b[24:32]))
+			b = b[32:]
+		}
+		h = bits.RotateLeft64(v1, 1) + bits.RotateLeft64(v2, 7) + bits.RotateLeft64(v3, 12) + bits.RotateLeft64(v4, 18)
+		h = xxhashMergeRound(h, v1)
+		h = xxhashMergeRound(h, v2)
+		h = xxhashMergeRound(h, v3)
+		h = xxhashMergeRound(h, v4)
+	} else {
+		h = prime64v5
+	}
+
+	h += uint64(n)
+
+	for ; len(b) >= 8; b = b[8:] {
+		k1 := xxhashRound(0, binary.LittleEndian.Uint64(b[:8]))
+		h ^= k1
+		h = bits.RotateLeft64(h, 27)*prime64v1 + prime64v4
+	}
+	if len(b) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(b[:4])) * prime64v1
+		h = bits.RotateLeft64(h, 23)*prime64v2 + prime64v3
+		b = b[4:]
+	}
+	for _, c := range b {
+		h ^= uint64(c) * prime64v5
+		h = bits.RotateLeft64(h, 11) * prime64v1
+	}
+
+	h ^= h >> 33
+	h *= prime64v2
+	h ^= h >> 29
+	h *= prime64v3
+	h ^= h >> 32
+	return h
+}
+
+func xxhashRound(acc, input uint64) uint64 {
+	acc += input * prime64v2
+	acc = bits.RotateLeft64(acc, 31)
+	acc *= prime64v1
+	return acc
+}
+
+func xxhashMergeRound(acc, val uint64) uint64 {
+	val = xxhashRound(0, val)
+	acc ^= val
+	acc = acc*prime64v1 + prime64v4
+	return acc
+}
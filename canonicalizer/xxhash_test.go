@@ -0,0 +1,39 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canonicalizer
+
+import "testing"
+
+func TestXxhash64(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  uint64
+	}{
+		{"1", "", 0xef46db3751d8e999},
+		{"2", "a", 0xd24ec4f1a98c6e5b},
+		{"3", "abc", 0x44bc2cf5ad770999},
+		{"4", "Nobody inspects the spammish repetition", 0xfbcea83c8a378bf1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := xxhash64([]byte(tt.input)); got != tt.want {
+				t.Errorf("xxhash64(%q) = %x, want %x", tt.input, got, tt.want)
+			}
+		})
+	}
+}
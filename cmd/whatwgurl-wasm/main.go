@@ -0,0 +1,183 @@
+//go:build js && wasm
+
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command whatwgurl-wasm exposes the parser and the canonicalization profiles to JavaScript, so that browser and
+// Node.js tooling canonicalizes urls exactly like Go programs using this module.
+//
+// Build it with:
+//
+//	GOOS=js GOARCH=wasm go build -o whatwgurl.wasm ./cmd/whatwgurl-wasm
+//
+// and load it with the wasm_exec.js support file shipped with Go. When started, the program sets a global object
+// whatwgurl with these functions:
+//
+//	parse(input, base?)          the components of the url as an object with the fields of the URL class
+//	canonicalize(input, profile?) the canonical form of the url
+//	surt(input, profile?)        the SURT form of the url canonicalized by profile
+//	key(input, profile?)         the deduplication key of the url canonicalized by profile
+//
+// profile is the name of a predefined profile (whatwg, whatwgSortQuery, googleSafeBrowsing, semantic, warcUrlKey,
+// heritrix or pywb) or a JSON profile configuration as read by canonicalizer.LoadConfig. The default is whatwg.
+//
+// The functions do not throw. If the url can not be parsed or the arguments are invalid, an Error object is returned
+// instead of the result.
+package main
+
+import (
+	"fmt"
+	"strings"
+	"syscall/js"
+
+	"github.com/nlnwa/whatwg-url/canonicalizer"
+	"github.com/nlnwa/whatwg-url/surt"
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+const defaultProfile = "whatwg"
+
+func main() {
+	register(js.Global())
+	select {}
+}
+
+// register sets the whatwgurl object on global.
+func register(global js.Value) {
+	global.Set("whatwgurl", js.ValueOf(map[string]interface{}{
+		"parse":        js.FuncOf(parse),
+		"canonicalize": js.FuncOf(withProfile(canonicalize)),
+		"surt":         js.FuncOf(withProfile(surtString)),
+		"key":          js.FuncOf(withProfile(key)),
+	}))
+}
+
+func parse(_ js.Value, args []js.Value) interface{} {
+	input, err := stringArg(args, 0, "input", false, "")
+	if err != nil {
+		return jsError(err)
+	}
+	base, err := stringArg(args, 1, "base", true, "")
+	if err != nil {
+		return jsError(err)
+	}
+
+	var u *url.Url
+	if base != "" {
+		u, err = url.ParseRef(base, input)
+	} else {
+		u, err = url.Parse(input)
+	}
+	if err != nil {
+		return jsError(err)
+	}
+	return map[string]interface{}{
+		"href":     u.Href(false),
+		"origin":   u.Origin(),
+		"protocol": u.Protocol(),
+		"username": u.Username(),
+		"password": u.Password(),
+		"host":     u.Host(),
+		"hostname": u.Hostname(),
+		"port":     u.Port(),
+		"pathname": u.Pathname(),
+		"search":   u.Search(),
+		"hash":     u.Hash(),
+	}
+}
+
+func canonicalize(p *canonicalizer.Profile, u *url.Url) (string, error) {
+	return p.String(u), nil
+}
+
+func surtString(_ *canonicalizer.Profile, u *url.Url) (string, error) {
+	return surt.String(u), nil
+}
+
+func key(p *canonicalizer.Profile, u *url.Url) (string, error) {
+	return p.Key(u)
+}
+
+// withProfile returns a function taking the input url and an optional profile, which parses the url with the
+// profile and returns the result of f as a string.
+func withProfile(f func(p *canonicalizer.Profile, u *url.Url) (string, error)) func(js.Value, []js.Value) interface{} {
+	return func(_ js.Value, args []js.Value) interface{} {
+		input, err := stringArg(args, 0, "input", false, "")
+		if err != nil {
+			return jsError(err)
+		}
+		profile, err := stringArg(args, 1, "profile", true, defaultProfile)
+		if err != nil {
+			return jsError(err)
+		}
+		p, err := lookupProfile(profile)
+		if err != nil {
+			return jsError(err)
+		}
+		u, err := p.Parse(input)
+		if err != nil {
+			return jsError(err)
+		}
+		s, err := f(p, u)
+		if err != nil {
+			return jsError(err)
+		}
+		return s
+	}
+}
+
+// profiles caches the profiles by name or JSON configuration. JavaScript calls Go functions from a single goroutine,
+// so no locking is needed.
+var profiles = map[string]*canonicalizer.Profile{}
+
+// lookupProfile returns the predefined profile with the given name, or the profile configured by the JSON object in
+// profile.
+func lookupProfile(profile string) (*canonicalizer.Profile, error) {
+	if p, ok := profiles[profile]; ok {
+		return p, nil
+	}
+	var p *canonicalizer.Profile
+	var err error
+	if strings.HasPrefix(strings.TrimSpace(profile), "{") {
+		p, err = canonicalizer.LoadConfig(strings.NewReader(profile))
+	} else {
+		p, err = canonicalizer.NewFromConfig(&canonicalizer.Config{Base: profile})
+	}
+	if err != nil {
+		return nil, err
+	}
+	profiles[profile] = p
+	return p, nil
+}
+
+// stringArg returns argument i as a string. If the argument is missing, undefined or null, def is returned if
+// optional is true and an error otherwise.
+func stringArg(args []js.Value, i int, name string, optional bool, def string) (string, error) {
+	if i >= len(args) || args[i].IsUndefined() || args[i].IsNull() {
+		if !optional {
+			return "", fmt.Errorf("whatwgurl: missing argument %s", name)
+		}
+		return def, nil
+	}
+	if args[i].Type() != js.TypeString {
+		return "", fmt.Errorf("whatwgurl: argument %s must be a string, got %s", name, args[i].Type())
+	}
+	return args[i].String(), nil
+}
+
+func jsError(err error) js.Value {
+	return js.Global().Get("Error").New(err.Error())
+}
@@ -0,0 +1,76 @@
+//go:build js && wasm
+
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"syscall/js"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	global := js.Global()
+	register(global)
+	w := global.Get("whatwgurl")
+	errorType := global.Get("Error")
+
+	tests := []struct {
+		name    string
+		fn      string
+		args    []interface{}
+		want    string
+		wantErr bool
+	}{
+		{"1", "canonicalize", []interface{}{"http://EXAMPLE.com/a b"}, "http://example.com/a%20b", false},
+		{"2", "canonicalize", []interface{}{"http://a.com/%2541#x", "googleSafeBrowsing"}, "http://a.com/A", false},
+		{"3", "canonicalize", []interface{}{"http://a.com/#x", `{"base": "whatwg", "options": [{"name": "removeFragment"}]}`}, "http://a.com/", false},
+		{"4", "canonicalize", []interface{}{"http://[x"}, "", true},
+		{"5", "canonicalize", []interface{}{"http://a.com/", "unknown"}, "", true},
+		{"6", "canonicalize", []interface{}{}, "", true},
+		{"7", "canonicalize", []interface{}{42}, "", true},
+		{"8", "surt", []interface{}{"http://www.example.com/x", "heritrix"}, "http://(com,example,)/x", false},
+		{"9", "key", []interface{}{"http://example.com/"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := w.Call(tt.fn, tt.args...)
+			if got.InstanceOf(errorType) != tt.wantErr {
+				t.Fatalf("%s(%v) = %v, wantErr %v", tt.fn, tt.args, got, tt.wantErr)
+			}
+			if !tt.wantErr && tt.want != "" && got.String() != tt.want {
+				t.Errorf("%s(%v) = %v, want %v", tt.fn, tt.args, got.String(), tt.want)
+			}
+		})
+	}
+
+	got := w.Call("parse", "b", "http://user@example.com:8080/a/")
+	if got.InstanceOf(errorType) {
+		t.Fatalf("parse() = %v", got)
+	}
+	for name, want := range map[string]string{
+		"href":     "http://user@example.com:8080/a/b",
+		"origin":   "http://example.com:8080",
+		"username": "user",
+		"port":     "8080",
+		"pathname": "/a/b",
+	} {
+		if v := got.Get(name).String(); v != want {
+			t.Errorf("parse().%s = %v, want %v", name, v, want)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command whatwgurl parses, canonicalizes and validates URLs from the command line.
+//
+// Usage:
+//
+//	whatwgurl <command> [flags] [url...]
+//
+// The commands are:
+//
+//	parse         print the components of each url as a JSON object
+//	canonicalize  print the canonical form of each url
+//	surt          print the SURT form of each url
+//	validate      print the validation errors of each url
+//
+// If no urls are given as arguments, they are read from standard input, one per line. The output has one line per
+// input line, so it can be pasted next to the input. The exit status is 0 if all urls were processed without
+// errors, 1 if any url failed and 2 if the command line was invalid or reading or writing failed.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nlnwa/whatwg-url/canonicalizer"
+	"github.com/nlnwa/whatwg-url/errors"
+	"github.com/nlnwa/whatwg-url/surt"
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+const (
+	exitOK     = 0
+	exitFailed = 1
+	exitUsage  = 2
+	// maxLineSize is the longest line read from standard input by the commands other than canonicalize, which uses
+	// the limit of canonicalizer.Profile.CanonicalizeStream.
+	maxLineSize = 1024 * 1024
+)
+
+const usage = `Usage: whatwgurl <command> [flags] [url...]
+
+Commands:
+  parse         print the components of each url as a JSON object
+  canonicalize  print the canonical form of each url
+  surt          print the SURT form of each url
+  validate      print the validation errors of each url
+
+If no urls are given, they are read from standard input, one per line.
+Run 'whatwgurl <command> -h' for the flags of a command.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command given by args and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	var cmd func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+	switch args[0] {
+	case "parse":
+		cmd = runParse
+	case "canonicalize":
+		cmd = runCanonicalize
+	case "surt":
+		cmd = runSurt
+	case "validate":
+		cmd = runValidate
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "whatwgurl: unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+	return cmd(args[1:], stdin, stdout, stderr)
+}
+
+// urlComponents is the JSON representation of an url written by the parse command. The field names follow the URL
+// class of the WHATWG URL Standard.
+type urlComponents struct {
+	Input    string `json:"input"`
+	Href     string `json:"href,omitempty"`
+	Origin   string `json:"origin,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Host     string `json:"host,omitempty"`
+	Hostname string `json:"hostname,omitempty"`
+	Port     string `json:"port,omitempty"`
+	Pathname string `json:"pathname,omitempty"`
+	Search   string `json:"search,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func runParse(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("parse", "Print the components of each url as a JSON object, one object per line.", stderr)
+	base := fs.String("base", "", "resolve the urls against this base url")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	enc := json.NewEncoder(stdout)
+	enc.SetEscapeHTML(false)
+	return forEachInput(fs.Args(), stdin, stderr, func(input string) (bool, error) {
+		var u *url.Url
+		var err error
+		if *base != "" {
+			u, err = url.ParseRef(*base, input)
+		} else {
+			u, err = url.Parse(input)
+		}
+		c := urlComponents{Input: input}
+		if err != nil {
+			c.Error = err.Error()
+		} else {
+			c.Href = u.Href(false)
+			c.Origin = u.Origin()
+			c.Protocol = u.Protocol()
+			c.Username = u.Username()
+			c.Password = u.Password()
+			c.Host = u.Host()
+			c.Hostname = u.Hostname()
+			c.Port = u.Port()
+			c.Pathname = u.Pathname()
+			c.Search = u.Search()
+			c.Hash = u.Hash()
+		}
+		return err == nil, enc.Encode(&c)
+	})
+}
+
+func runCanonicalize(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("canonicalize", "Print the canonical form of each url. An empty line is printed for urls which can not be parsed.", stderr)
+	profileName := fs.String("profile", "whatwg", "predefined profile (whatwg, whatwgSortQuery, googleSafeBrowsing, semantic, warcUrlKey, heritrix or pywb)")
+	configFile := fs.String("config", "", "load the profile from this JSON or YAML config file instead of using -profile")
+	tsv := fs.Bool("tsv", false, "print tab-separated lines with the input, the canonical url and the error message")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	profile, err := loadProfile(*profileName, *configFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "whatwgurl: %v\n", err)
+		return exitUsage
+	}
+
+	// Arguments are canonicalized like lines read from standard input, so the output format is the same
+	in := stdin
+	if fs.NArg() > 0 {
+		in = strings.NewReader(strings.Join(fs.Args(), "\n"))
+	}
+	stats, err := profile.CanonicalizeStreamStats(in, stdout, *tsv)
+	if err != nil {
+		fmt.Fprintf(stderr, "whatwgurl: %v\n", err)
+		return exitUsage
+	}
+	if stats.Failed > 0 {
+		return exitFailed
+	}
+	return exitOK
+}
+
+func runSurt(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("surt", "Print the SURT form of each url. An empty line is printed for urls which can not be parsed.", stderr)
+	profileName := fs.String("profile", "whatwg", "predefined profile used to canonicalize the urls before they are converted")
+	configFile := fs.String("config", "", "load the profile from this JSON or YAML config file instead of using -profile")
+	scheme := fs.Bool("scheme", true, "include the scheme")
+	trailingComma := fs.Bool("trailing-comma", true, "include a comma after the last host label")
+	userinfo := fs.Bool("userinfo", true, "include the userinfo")
+	openParenthesis := fs.Bool("open-parenthesis", true, "include the opening parenthesis before the host")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	profile, err := loadProfile(*profileName, *configFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "whatwgurl: %v\n", err)
+		return exitUsage
+	}
+	opts := []surt.Option{
+		surt.WithScheme(*scheme),
+		surt.WithTrailingComma(*trailingComma),
+		surt.WithUserinfo(*userinfo),
+		surt.WithOpenParenthesis(*openParenthesis),
+	}
+
+	w := bufio.NewWriter(stdout)
+	status := forEachInput(fs.Args(), stdin, stderr, func(input string) (bool, error) {
+		u, err := profile.Parse(input)
+		if err != nil {
+			fmt.Fprintf(stderr, "whatwgurl: %v\n", err)
+		} else {
+			w.WriteString(surt.String(u, opts...))
+		}
+		return err == nil, w.WriteByte('\n')
+	})
+	return flush(w, stderr, status)
+}
+
+func runValidate(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("validate", "Print the validation errors of each url as tab-separated lines with the input, the error code and the\n"+
+		"error type. Nothing is printed for valid urls. The exit status is 1 if any url has validation errors.", stderr)
+	quiet := fs.Bool("q", false, "do not print the errors, only set the exit status")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	parser := url.NewParser(url.WithReportValidationErrors(), url.WithJoinValidationErrors())
+	w := bufio.NewWriter(stdout)
+	status := forEachInput(fs.Args(), stdin, stderr, func(input string) (bool, error) {
+		var errs []error
+		u, err := parser.Parse(input)
+		if err != nil {
+			errs = unwrapJoined(err)
+		} else {
+			errs = u.ValidationErrors()
+		}
+		if *quiet {
+			return len(errs) == 0, nil
+		}
+		for _, e := range errs {
+			w.WriteString(tsvFieldReplacer.Replace(input))
+			w.WriteByte('\t')
+			w.WriteString(errors.Code(e))
+			w.WriteByte('\t')
+			w.WriteString(tsvFieldReplacer.Replace(string(errors.Type(e))))
+			if err := w.WriteByte('\n'); err != nil {
+				return false, err
+			}
+		}
+		return len(errs) == 0, nil
+	})
+	return flush(w, stderr, status)
+}
+
+var tsvFieldReplacer = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+
+func newFlagSet(name, description string, output io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.Usage = func() {
+		fmt.Fprintf(output, "Usage: whatwgurl %s [flags] [url...]\n\n%s\n\nFlags:\n", name, description)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// loadProfile returns the profile read from configFile if set, otherwise the predefined profile with the given name.
+func loadProfile(name, configFile string) (*canonicalizer.Profile, error) {
+	if configFile != "" {
+		return canonicalizer.LoadConfigFile(configFile)
+	}
+	return canonicalizer.NewFromConfig(&canonicalizer.Config{Base: name})
+}
+
+// forEachInput calls f for each url in args, or for each line read from stdin if args is empty. f returns whether the
+// url was processed without errors and any error writing the result. The returned exit status is exitFailed if f
+// reported a failed url and exitUsage if reading or writing failed.
+func forEachInput(args []string, stdin io.Reader, stderr io.Writer, f func(input string) (bool, error)) int {
+	status := exitOK
+	process := func(input string) bool {
+		ok, err := f(input)
+		if err != nil {
+			fmt.Fprintf(stderr, "whatwgurl: %v\n", err)
+			status = exitUsage
+			return false
+		}
+		if !ok {
+			status = exitFailed
+		}
+		return true
+	}
+
+	if len(args) > 0 {
+		for _, input := range args {
+			if !process(input) {
+				break
+			}
+		}
+		return status
+	}
+
+	scanner := bufio.NewScanner(stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		if !process(strings.TrimSuffix(scanner.Text(), "\r")) {
+			return status
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(stderr, "whatwgurl: reading standard input: %v\n", err)
+		return exitUsage
+	}
+	return status
+}
+
+// flush flushes w and returns status, or exitUsage if flushing failed.
+func flush(w *bufio.Writer, stderr io.Writer, status int) int {
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(stderr, "whatwgurl: %v\n", err)
+		return exitUsage
+	}
+	return status
+}
+
+// unwrapJoined returns the errors joined in err, or err itself if it is not a joined error.
+func unwrapJoined(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantOut    string
+		wantStatus int
+	}{
+		{"1", []string{"parse", "http://user@EXAMPLE.com:80/a/../b?c#d"}, "",
+			`{"input":"http://user@EXAMPLE.com:80/a/../b?c#d","href":"http://user@example.com/b?c#d","origin":"http://example.com","protocol":"http:","username":"user","host":"example.com","hostname":"example.com","pathname":"/b","search":"?c","hash":"#d"}` + "\n",
+			exitOK},
+		{"2", []string{"parse", "-base", "http://example.com/a/", "b"}, "",
+			`{"input":"b","href":"http://example.com/a/b","origin":"http://example.com","protocol":"http:","host":"example.com","hostname":"example.com","pathname":"/a/b"}` + "\n",
+			exitOK},
+		{"3", []string{"parse"}, "http://[x\n",
+			`{"input":"http://[x","error":"Error: An IPv6 address is missing the closing U+005D (]). Url: 'http://[x'"}` + "\n",
+			exitFailed},
+		{"4", []string{"canonicalize"}, "http://EXAMPLE.com/a b\r\nhttp://[x\nhttp://example.com/\n",
+			"http://example.com/a%20b\n\nhttp://example.com/\n", exitFailed},
+		{"5", []string{"canonicalize", "-profile", "googleSafeBrowsing", "-tsv", "http://a.com/%2541#x"}, "",
+			"http://a.com/%2541#x\thttp://a.com/A\t\n", exitOK},
+		{"6", []string{"canonicalize", "-profile", "unknown", "http://a.com/"}, "", "", exitUsage},
+		{"7", []string{"surt", "http://www.example.com/x"}, "", "http://(com,example,www,)/x\n", exitOK},
+		{"8", []string{"surt", "-profile", "heritrix", "-scheme=false", "http://www.example.com/x"}, "",
+			"(com,example,)/x\n", exitOK},
+		{"9", []string{"validate", "http://example.com/"}, "", "", exitOK},
+		{"10", []string{"validate"}, "http://example.com/\nhttp://a\\b\n",
+			"http://a\\b\tinvalid-reverse-solidus\tThe URL has a special scheme and it uses U+005C (\\) instead of U+002F (/)\n",
+			exitFailed},
+		{"11", []string{"validate", "-q", "http://[::1"}, "", "", exitFailed},
+		{"12", []string{"unknown"}, "", "", exitUsage},
+		{"13", nil, "", "", exitUsage},
+		{"14", []string{"canonicalize", "-tsv"}, "http://a.com/\tb\n", "http://a.com/ b\thttp://a.com/b\t\n", exitOK},
+		{"15", []string{"canonicalize", "http://a.com/", "http://[x"}, "", "http://a.com/\n\n", exitFailed},
+		{"16", []string{"validate", "http://a\\b\t"}, "",
+			"http://a\\b \tinvalid-URL-unit\tA code point is found that is not a URL unit\n" +
+				"http://a\\b \tinvalid-reverse-solidus\tThe URL has a special scheme and it uses U+005C (\\) instead of U+002F (/)\n",
+			exitFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if status != tt.wantStatus {
+				t.Errorf("run(%q) = %v, want %v; stderr: %s", tt.args, status, tt.wantStatus, stderr.String())
+			}
+			if got := stdout.String(); got != tt.wantOut {
+				t.Errorf("run(%q) output = %q, want %q", tt.args, got, tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package dataurl implements the data: URL processor from the [Fetch Standard].
+//
+// A data: URL has an opaque path holding a MIME type and a body separated by a comma, e.g.
+// "data:text/plain;charset=utf-8;base64,SGVsbG8=". Process splits the path, parses the MIME type and decodes the
+// body, including the base64 encoding if present.
+//
+// [Fetch Standard]: https://fetch.spec.whatwg.org/#data-urls
+package dataurl
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+var (
+	// ErrNotDataURL is returned when the scheme of the url is not "data".
+	ErrNotDataURL = errors.New("dataurl: scheme is not data")
+	// ErrMissingComma is returned when there is no comma separating the MIME type from the body.
+	ErrMissingComma = errors.New("dataurl: missing comma")
+	// ErrInvalidBase64 is returned when the body is marked as base64, but could not be decoded.
+	ErrInvalidBase64 = errors.New("dataurl: invalid base64 body")
+)
+
+// DataURL is the result of processing a data: URL.
+type DataURL struct {
+	// MIMEType is the MIME type of the body. It is "text/plain;charset=US-ASCII" if the url had no MIME type or if
+	// the MIME type could not be parsed.
+	MIMEType *MIMEType
+	// Body is the decoded body.
+	Body []byte
+}
+
+// Parse parses rawUrl with the default URL parser and processes the result as a data: URL.
+func Parse(rawUrl string) (*DataURL, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return nil, err
+	}
+	return Process(u)
+}
+
+// Process runs the data: URL processor on u.
+//
+// See: https://fetch.spec.whatwg.org/#data-url-processor
+func Process(u *url.Url) (*DataURL, error) {
+	if u.Scheme() != "data" {
+		return nil, ErrNotDataURL
+	}
+
+	input := strings.TrimPrefix(u.Href(true), "data:")
+	mimeType, encodedBody, found := strings.Cut(input, ",")
+	if !found {
+		return nil, ErrMissingComma
+	}
+	mimeType = strings.Trim(mimeType, asciiWhitespace)
+
+	var buf bytes.Buffer
+	buf.Grow(len(encodedBody))
+	_, _ = url.PercentDecodeTo(&buf, encodedBody)
+	body := buf.Bytes()
+
+	if m, ok := trimBase64(mimeType); ok {
+		var err error
+		if body, err = forgivingBase64Decode(body); err != nil {
+			return nil, err
+		}
+		mimeType = m
+	}
+
+	if strings.HasPrefix(mimeType, ";") {
+		mimeType = "text/plain" + mimeType
+	}
+
+	mt, err := ParseMIMEType(mimeType)
+	if err != nil {
+		mt = &MIMEType{
+			Type:       "text",
+			Subtype:    "plain",
+			Parameters: []Parameter{{Name: "charset", Value: "US-ASCII"}},
+		}
+	}
+	return &DataURL{MIMEType: mt, Body: body}, nil
+}
+
+// asciiWhitespace is the ASCII whitespace as defined by the [Infra Standard].
+//
+// [Infra Standard]: https://infra.spec.whatwg.org/#ascii-whitespace
+const asciiWhitespace = "\t\n\f\r "
+
+// trimBase64 removes a trailing ';', followed by zero or more spaces and "base64" (ASCII case-insensitive), from
+// mimeType. The returned bool is false if mimeType does not end like that.
+func trimBase64(mimeType string) (string, bool) {
+	if len(mimeType) < 6 || !strings.EqualFold(mimeType[len(mimeType)-6:], "base64") {
+		return mimeType, false
+	}
+	m := strings.TrimRight(mimeType[:len(mimeType)-6], " ")
+	if !strings.HasSuffix(m, ";") {
+		return mimeType, false
+	}
+	return m[:len(m)-1], true
+}
+
+// forgivingBase64Decode implements the [forgiving-base64 decode] algorithm.
+//
+// [forgiving-base64 decode]: https://infra.spec.whatwg.org/#forgiving-base64-decode
+func forgivingBase64Decode(data []byte) ([]byte, error) {
+	b := make([]byte, 0, len(data))
+	for _, c := range data {
+		if strings.IndexByte(asciiWhitespace, c) < 0 {
+			b = append(b, c)
+		}
+	}
+	if len(b)%4 == 0 {
+		if bytes.HasSuffix(b, []byte("==")) {
+			b = b[:len(b)-2]
+		} else if bytes.HasSuffix(b, []byte("=")) {
+			b = b[:len(b)-1]
+		}
+	}
+	if len(b)%4 == 1 {
+		return nil, ErrInvalidBase64
+	}
+	// RawStdEncoding rejects any byte outside the base64 alphabet, including '=', but ignores '\r' and '\n' which
+	// are already removed. Non-zero trailing bits are accepted as the algorithm requires.
+	n, err := base64.RawStdEncoding.Decode(b, b)
+	if err != nil {
+		return nil, ErrInvalidBase64
+	}
+	return b[:n], nil
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dataurl
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantType string
+		wantBody []byte
+		wantErr  error
+	}{
+		{"1", "data:,X", "text/plain;charset=US-ASCII", []byte("X"), nil},
+		{"2", "data://test/,X", "text/plain;charset=US-ASCII", []byte("X"), nil},
+		{"3", "data:", "", nil, ErrMissingComma},
+		{"4", "data:text/html", "", nil, ErrMissingComma},
+		{"5", "data:text/html    ;charset=x   ,", "text/html;charset=x", []byte{}, nil},
+		{"6", "data:;charset=x,X", "text/plain;charset=x", []byte("X"), nil},
+		{"7", "data:IMAGE/gif,hi", "image/gif", []byte("hi"), nil},
+		{"8", "data:text/plain,X%20X#frag", "text/plain", []byte("X X"), nil},
+		{"9", "data:;base64,WID", "text/plain;charset=US-ASCII", []byte{0x58, 0x80}, nil},
+		{"10", "data:;base64,W%0CI%20D", "text/plain;charset=US-ASCII", []byte{0x58, 0x80}, nil},
+		{"11", "data:;base64,=WIDE", "", nil, ErrInvalidBase64},
+		{"12", "data:;base64,WA==", "text/plain;charset=US-ASCII", []byte("X"), nil},
+		{"13", "data:;base64,WA=", "", nil, ErrInvalidBase64},
+		{"14", "data:x/x;base64;base64,WA", "x/x", []byte("X"), nil},
+		{"15", "data:text/plain;BASE64  ,SGVsbG8=", "text/plain", []byte("Hello"), nil},
+		{"16", "data:text/plain;base64x,WA", "text/plain", []byte("WA"), nil},
+		{"17", "data:text/plain;Charset=\"hi\",X", "text/plain;charset=hi", []byte("X"), nil},
+		{"18", "data:x,X", "text/plain;charset=US-ASCII", []byte("X"), nil},
+		{"19", "http://example.com/,X", "", nil, ErrNotDataURL},
+		{"20", "data:,%FF%e5", "text/plain;charset=US-ASCII", []byte{0xFF, 0xE5}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.MIMEType.String() != tt.wantType {
+				t.Errorf("Parse() MIMEType = %v, want %v", got.MIMEType, tt.wantType)
+			}
+			if !bytes.Equal(got.Body, tt.wantBody) {
+				t.Errorf("Parse() Body = %q, want %q", got.Body, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dataurl
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidMIMEType is returned by ParseMIMEType when the input is not a valid MIME type.
+var ErrInvalidMIMEType = errors.New("dataurl: invalid MIME type")
+
+// MIMEType is a MIME type as defined by the [MIME Sniffing Standard].
+//
+// [MIME Sniffing Standard]: https://mimesniff.spec.whatwg.org/#mime-type-representation
+type MIMEType struct {
+	// Type is the lowercased type, e.g. "text".
+	Type string
+	// Subtype is the lowercased subtype, e.g. "plain".
+	Subtype string
+	// Parameters are the parameters in the order they appeared. Names are lowercased and unique.
+	Parameters []Parameter
+}
+
+// Parameter is a name-value pair of a MIME type.
+type Parameter struct {
+	Name  string
+	Value string
+}
+
+// Essence returns type and subtype separated by '/', e.g. "text/plain".
+func (m *MIMEType) Essence() string {
+	return m.Type + "/" + m.Subtype
+}
+
+// Parameter returns the value of the parameter with the given name. The name must be lowercase.
+func (m *MIMEType) Parameter(name string) (string, bool) {
+	for _, p := range m.Parameters {
+		if p.Name == name {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+// String serializes the MIME type. Parameter values which are empty or contain code points other than HTTP token
+// code points are quoted.
+//
+// See: https://mimesniff.spec.whatwg.org/#serialize-a-mime-type
+func (m *MIMEType) String() string {
+	sb := strings.Builder{}
+	sb.WriteString(m.Type)
+	sb.WriteByte('/')
+	sb.WriteString(m.Subtype)
+	for _, p := range m.Parameters {
+		sb.WriteByte(';')
+		sb.WriteString(p.Name)
+		sb.WriteByte('=')
+		if p.Value != "" && isToken(p.Value) {
+			sb.WriteString(p.Value)
+			continue
+		}
+		sb.WriteByte('"')
+		for _, r := range p.Value {
+			if r == '"' || r == '\\' {
+				sb.WriteByte('\\')
+			}
+			sb.WriteRune(r)
+		}
+		sb.WriteByte('"')
+	}
+	return sb.String()
+}
+
+// ParseMIMEType parses s as a MIME type. Invalid parameters are skipped, while an invalid type or subtype returns
+// ErrInvalidMIMEType.
+//
+// See: https://mimesniff.spec.whatwg.org/#parse-a-mime-type
+func ParseMIMEType(s string) (*MIMEType, error) {
+	s = strings.Trim(s, httpWhitespace)
+
+	typ, rest, found := strings.Cut(s, "/")
+	if typ == "" || !isToken(typ) || !found {
+		return nil, ErrInvalidMIMEType
+	}
+	subtype, rest, _ := cutAny(rest, ";")
+	subtype = strings.TrimRight(subtype, httpWhitespace)
+	if subtype == "" || !isToken(subtype) {
+		return nil, ErrInvalidMIMEType
+	}
+
+	m := &MIMEType{
+		Type:    strings.ToLower(typ),
+		Subtype: strings.ToLower(subtype),
+	}
+
+	for rest != "" {
+		// rest starts with the ';' ending the subtype or the previous parameter.
+		rest = strings.TrimLeft(rest[1:], httpWhitespace)
+
+		var name string
+		var sep byte
+		name, rest, sep = cutAny(rest, ";=")
+		name = strings.ToLower(name)
+		if sep == ';' {
+			continue
+		}
+		if sep == 0 {
+			break
+		}
+		rest = rest[1:]
+		if rest == "" {
+			break
+		}
+
+		var value string
+		if rest[0] == '"' {
+			value, rest = collectQuotedString(rest)
+			_, rest, _ = cutAny(rest, ";")
+		} else {
+			value, rest, _ = cutAny(rest, ";")
+			value = strings.TrimRight(value, httpWhitespace)
+			if value == "" {
+				continue
+			}
+		}
+
+		if name != "" && isToken(name) && isQuotedStringToken(value) {
+			if _, exists := m.Parameter(name); !exists {
+				m.Parameters = append(m.Parameters, Parameter{Name: name, Value: value})
+			}
+		}
+	}
+	return m, nil
+}
+
+// httpWhitespace is the HTTP whitespace as defined by the [Fetch Standard].
+//
+// [Fetch Standard]: https://fetch.spec.whatwg.org/#http-whitespace
+const httpWhitespace = "\t\n\r "
+
+// cutAny slices s around the first byte in seps. The returned rest starts with the separator, which is also returned
+// as sep. If no separator is found, cutAny returns s, "", 0.
+func cutAny(s, seps string) (before, rest string, sep byte) {
+	if i := strings.IndexAny(s, seps); i >= 0 {
+		return s[:i], s[i:], s[i]
+	}
+	return s, "", 0
+}
+
+// collectQuotedString collects an HTTP quoted string from the start of s, which must be '"', and returns the
+// extracted value and the remainder of s after the closing quote.
+//
+// See: https://fetch.spec.whatwg.org/#collect-an-http-quoted-string
+func collectQuotedString(s string) (value, rest string) {
+	sb := strings.Builder{}
+	s = s[1:]
+	for {
+		var sep byte
+		var v string
+		v, s, sep = cutAny(s, "\"\\")
+		sb.WriteString(v)
+		if sep == 0 {
+			break
+		}
+		s = s[1:]
+		if sep == '"' {
+			break
+		}
+		if s == "" {
+			sb.WriteByte('\\')
+			break
+		}
+		// Copy the escaped code point, which may be more than one byte.
+		n := 1
+		for n < len(s) && s[n]&0xC0 == 0x80 {
+			n++
+		}
+		sb.WriteString(s[:n])
+		s = s[n:]
+	}
+	return sb.String(), s
+}
+
+// isToken reports whether s consists solely of HTTP token code points.
+func isToken(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+			strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// isQuotedStringToken reports whether s consists solely of HTTP quoted-string token code points, that is U+0009,
+// U+0020 to U+007E and U+0080 to U+00FF.
+func isQuotedStringToken(s string) bool {
+	for _, r := range s {
+		if !(r == '\t' || r >= 0x20 && r <= 0x7E || r >= 0x80 && r <= 0xFF) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dataurl
+
+import (
+	"testing"
+)
+
+func TestParseMIMEType(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"1", "text/html;charset=gbk", "text/html;charset=gbk", false},
+		{"2", " TEXT/HTML ; CHARSET=GBK ", "text/html;charset=GBK", false},
+		{"3", "text/html;charset=gbk;charset=windows-1255", "text/html;charset=gbk", false},
+		{"4", "text/html;charset=\"shift_jis\"iso-2022-jp", "text/html;charset=shift_jis", false},
+		{"5", "text/html;charset=\";charset=foo\"; charset=GBK", "text/html;charset=\";charset=foo\"", false},
+		{"6", "text/html;charset=\"\\\"", "text/html;charset=\"\\\"\"", false},
+		{"7", "text/html;charset=\"a\\\"b", "text/html;charset=\"a\\\"b\"", false},
+		{"8", "text/html;charset=", "text/html", false},
+		{"9", "text/html;charset=\"\"", "text/html;charset=\"\"", false},
+		{"10", "text/html;;;;charset=gbk", "text/html;charset=gbk", false},
+		{"11", "text/html;charset;charset=gbk", "text/html;charset=gbk", false},
+		{"12", "text/html;test=\"ÿ\";charset=gbk", "text/html;test=\"ÿ\";charset=gbk", false},
+		{"13", "text/html;test=Ā;charset=gbk", "text/html;charset=gbk", false},
+		{"14", "text/html;x=a b", "text/html;x=\"a b\"", false},
+		{"15", "text", "", true},
+		{"16", "/html", "", true},
+		{"17", "text/", "", true},
+		{"18", "te xt/html", "", true},
+		{"19", "text/html(;doesnot=matter", "", true},
+		{"20", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMIMEType(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMIMEType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("ParseMIMEType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMIMEType_Parameter(t *testing.T) {
+	m, err := ParseMIMEType("Text/Plain;Charset=UTF-8;format=flowed")
+	if err != nil {
+		t.Fatalf("ParseMIMEType() error = %v", err)
+	}
+	if got := m.Essence(); got != "text/plain" {
+		t.Errorf("Essence() = %v, want %v", got, "text/plain")
+	}
+	if got, ok := m.Parameter("charset"); !ok || got != "UTF-8" {
+		t.Errorf("Parameter() = %v, %v, want %v, %v", got, ok, "UTF-8", true)
+	}
+	if got, ok := m.Parameter("boundary"); ok {
+		t.Errorf("Parameter() = %v, %v, want %v, %v", got, ok, "", false)
+	}
+}
@@ -39,10 +39,16 @@ const (
 	IPv6MultipleCompression    ErrorType = "An IPv6 address contains multiple instances of '::'"
 	IPv6InvalidCodePoint       ErrorType = "An IPv6 address contains a code point that is neither an ASCII hex digit nor a U+003A (:). Or it unexpectedly ends"
 	IPv6TooFewPieces           ErrorType = "An uncompressed IPv6 address contains fewer than 8 pieces"
+	IPv6InvalidZoneID          ErrorType = "An IPv6 address contains an empty zone identifier or a zone identifier with invalid code points"
 	IPv4InIPv6TooManyPieces    ErrorType = "An IPv4 address is found in an IPv6 address, but the IPv6 address has more than 6 pieces"
 	IPv4InIPv6InvalidCodePoint ErrorType = "An IPv4 address is found in an IPv6 address and one of the following is true: 1. An IPv4 part is empty or contains a non-ASCII digit. 2. An IPv4 part contains a leading 0. 3. There are too many IPv4 parts"
 	IPv4InIPv6OutOfRangePart   ErrorType = "An IPv4 address is found in an IPv6 address and one of the IPv4 parts is greater than 255"
 	IPv4InIPv6TooFewParts      ErrorType = "An IPv4 address is found in an IPv6 address and there are too few IPv4 parts"
+	HostResolution             ErrorType = "The host was rejected by the host resolution function"
+	HostNotDotted              ErrorType = "The host of a URL with a special scheme is a domain consisting of a single label"
+	DomainTooLong              ErrorType = "The domain or one of its labels is outside the length limits of DNS"
+	HostForbiddenAddress       ErrorType = "The host is an IP address which is forbidden by the parser configuration"
+	IPv4Ambiguous              ErrorType = "The host is an IPv4 address which is not written in dotted decimal notation"
 )
 
 // URL parsing errors
@@ -58,3 +64,80 @@ const (
 	FileInvalidWindowsDriveLetter        ErrorType = "The input is a relative-URL string that starts with a Windows drive letter and the base URL’s scheme is 'file'"
 	FileInvalidWindowsDriveLetterHost    ErrorType = "A file: URL’s host is a Windows drive letter"
 )
+
+// ErrorCategory is the group of error types an ErrorType belongs to.
+type ErrorCategory string
+
+const (
+	// CategoryIDNA contains the errors from domain to ASCII and domain to Unicode.
+	CategoryIDNA ErrorCategory = "IDNA"
+	// CategoryHost contains the errors from host parsing.
+	CategoryHost ErrorCategory = "Host"
+	// CategoryURL contains the errors from URL parsing.
+	CategoryURL ErrorCategory = "URL"
+)
+
+// errorCode is the stable identification of an ErrorType.
+type errorCode struct {
+	number   int
+	name     string
+	category ErrorCategory
+}
+
+// errorCodes maps each ErrorType to its stable code. The names are the validation error names used in the
+// WHATWG URL Standard. Error types not defined by the standard use names in the same style.
+// Numbers are never reused or changed, new error types get the next free number.
+var errorCodes = map[ErrorType]errorCode{
+	DomainToASCII:                        {1, "domain-to-ASCII", CategoryIDNA},
+	DomainToUnicode:                      {2, "domain-to-Unicode", CategoryIDNA},
+	DomainInvalidCodePoint:               {3, "domain-invalid-code-point", CategoryHost},
+	HostInvalidCodePoint:                 {4, "host-invalid-code-point", CategoryHost},
+	IPv4EmptyPart:                        {5, "IPv4-empty-part", CategoryHost},
+	IPv4TooManyParts:                     {6, "IPv4-too-many-parts", CategoryHost},
+	IPv4NonNumericPart:                   {7, "IPv4-non-numeric-part", CategoryHost},
+	IPv4NonDecimalPart:                   {8, "IPv4-non-decimal-part", CategoryHost},
+	IPv4OutOfRangePart:                   {9, "IPv4-out-of-range-part", CategoryHost},
+	IPv6Unclosed:                         {10, "IPv6-unclosed", CategoryHost},
+	IPv6InvalidCompression:               {11, "IPv6-invalid-compression", CategoryHost},
+	IPv6TooManyPieces:                    {12, "IPv6-too-many-pieces", CategoryHost},
+	IPv6MultipleCompression:              {13, "IPv6-multiple-compression", CategoryHost},
+	IPv6InvalidCodePoint:                 {14, "IPv6-invalid-code-point", CategoryHost},
+	IPv6TooFewPieces:                     {15, "IPv6-too-few-pieces", CategoryHost},
+	IPv4InIPv6TooManyPieces:              {16, "IPv4-in-IPv6-too-many-pieces", CategoryHost},
+	IPv4InIPv6InvalidCodePoint:           {17, "IPv4-in-IPv6-invalid-code-point", CategoryHost},
+	IPv4InIPv6OutOfRangePart:             {18, "IPv4-in-IPv6-out-of-range-part", CategoryHost},
+	IPv4InIPv6TooFewParts:                {19, "IPv4-in-IPv6-too-few-parts", CategoryHost},
+	InvalidURLUnit:                       {20, "invalid-URL-unit", CategoryURL},
+	SpecialSchemeMissingFollowingSolidus: {21, "special-scheme-missing-following-solidus", CategoryURL},
+	MissingSchemeNonRelativeURL:          {22, "missing-scheme-non-relative-URL", CategoryURL},
+	InvalidReverseSolidus:                {23, "invalid-reverse-solidus", CategoryURL},
+	InvalidCredentials:                   {24, "invalid-credentials", CategoryURL},
+	HostMissing:                          {25, "host-missing", CategoryURL},
+	PortOutOfRange:                       {26, "port-out-of-range", CategoryURL},
+	PortInvalid:                          {27, "port-invalid", CategoryURL},
+	FileInvalidWindowsDriveLetter:        {28, "file-invalid-Windows-drive-letter", CategoryURL},
+	FileInvalidWindowsDriveLetterHost:    {29, "file-invalid-Windows-drive-letter-host", CategoryURL},
+	IPv6InvalidZoneID:                    {30, "IPv6-invalid-zone-id", CategoryHost},
+	HostResolution:                       {31, "host-resolution", CategoryHost},
+	HostNotDotted:                        {32, "host-not-dotted", CategoryHost},
+	DomainTooLong:                        {33, "domain-too-long", CategoryHost},
+	HostForbiddenAddress:                 {34, "host-forbidden-address", CategoryHost},
+	IPv4Ambiguous:                        {35, "IPv4-ambiguous", CategoryHost},
+}
+
+// Code returns the stable name of the error type, e.g. "host-missing" for HostMissing.
+// Unlike the error type itself, which is a human readable description, the code does not change between releases.
+// The empty string is returned for unknown error types.
+func (t ErrorType) Code() string {
+	return errorCodes[t].name
+}
+
+// Number returns the stable number of the error type, or 0 for unknown error types.
+func (t ErrorType) Number() int {
+	return errorCodes[t].number
+}
+
+// Category returns the category of the error type, or the empty string for unknown error types.
+func (t ErrorType) Category() ErrorCategory {
+	return errorCodes[t].category
+}
@@ -17,6 +17,7 @@
 package errors
 
 import (
+	goerrors "errors"
 	"fmt"
 )
 
@@ -27,6 +28,18 @@ type ValidationError struct {
 	descr     string // description of the error
 	failure   bool   // true if the error is a failure, false if it is a warning
 	url       string
+	position  *Position
+}
+
+// Position tells where in the url a validation error was found.
+type Position struct {
+	// Offset is the offset, counted in runes, into the url returned by ValidationError.Url().
+	Offset int
+	// Component is the url component which was parsed: scheme, authority, host, port, path, query or fragment.
+	Component string
+	// State is the name of the state the basic URL parser was in (e.g. "special authority slashes state").
+	// See https://url.spec.whatwg.org/#concept-basic-url-parser for the states.
+	State string
 }
 
 func (e *ValidationError) Error() string {
@@ -69,27 +82,81 @@ func (e *ValidationError) Description() string {
 	return e.descr
 }
 
-// Type returns the error type
+// Position returns where in the url the error was found. The second return value is false if the position is unknown,
+// e.g. for errors found before parsing started or by host parsing functions called directly.
+func (e *ValidationError) Position() (Position, bool) {
+	if e.position == nil {
+		return Position{}, false
+	}
+	return *e.position, true
+}
+
+// State returns the name of the state the basic URL parser was in when the error was found, or the empty string if
+// the position is unknown. This tells which component was parsed, also for errors found by the setters of a url.
+func (e *ValidationError) State() string {
+	if e.position == nil {
+		return ""
+	}
+	return e.position.State
+}
+
+// WithPosition returns a copy of the error with the position set
+func (e *ValidationError) WithPosition(pos Position) *ValidationError {
+	c := *e
+	c.position = &pos
+	return &c
+}
+
+// Code returns the stable code of the error type (see ErrorType.Code)
+func (e *ValidationError) Code() string {
+	return e.errorType.Code()
+}
+
+// Category returns the category of the error type (see ErrorType.Category)
+func (e *ValidationError) Category() ErrorCategory {
+	return e.errorType.Category()
+}
+
+// Is returns true if target is a *ValidationError with the same error type. This allows matching errors by type
+// with errors.Is from the standard library, e.g. errors.Is(err, errors.ErrHostMissing).
+func (e *ValidationError) Is(target error) bool {
+	t, ok := target.(*ValidationError)
+	return ok && t.errorType == e.errorType
+}
+
+// Type returns the error type. Wrapped errors are unwrapped until an error with a type is found.
 func Type(err error) ErrorType {
 	type typer interface {
 		Type() ErrorType
 	}
 
-	cd, ok := err.(typer)
-	if !ok {
+	var cd typer
+	if !goerrors.As(err, &cd) {
 		return ""
 	}
 	return cd.Type()
 }
 
+// Code returns the stable code of the error type (see ErrorType.Code).
+// The empty string is returned if the error has no error type.
+func Code(err error) string {
+	return Type(err).Code()
+}
+
+// Category returns the category of the error type (see ErrorType.Category).
+// The empty string is returned if the error has no error type.
+func Category(err error) ErrorCategory {
+	return Type(err).Category()
+}
+
 // Description returns the error description
 func Description(err error) string {
 	type descr interface {
 		Description() string
 	}
 
-	m, ok := err.(descr)
-	if !ok {
+	var m descr
+	if !goerrors.As(err, &m) {
 		return ""
 	}
 	return m.Description()
@@ -101,8 +168,8 @@ func Url(err error) string {
 		Url() string
 	}
 
-	m, ok := err.(url)
-	if !ok {
+	var m url
+	if !goerrors.As(err, &m) {
 		return ""
 	}
 	return m.Url()
@@ -115,8 +182,8 @@ func Failure(err error) bool {
 		Failure() bool
 	}
 
-	m, ok := err.(failure)
-	if !ok {
+	var m failure
+	if !goerrors.As(err, &m) {
 		return true
 	}
 	return m.Failure()
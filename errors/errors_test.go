@@ -17,6 +17,7 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 	"testing"
 )
@@ -42,3 +43,98 @@ func TestError(t *testing.T) {
 		})
 	}
 }
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   string
+		wantNumber int
+	}{
+		{"1", Error(HostMissing, "http://", true), "host-missing", 25},
+		{"2", Wrap(fmt.Errorf("cause"), DomainToASCII, "http://xn--a", true), "domain-to-ASCII", 1},
+		{"3", fmt.Errorf("not a validation error"), "", 0},
+		{"4", fmt.Errorf("wrapped: %w", Error(HostMissing, "http://", true)), "host-missing", 25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Code(tt.err); got != tt.wantCode {
+				t.Errorf("Code() = %v, want %v", got, tt.wantCode)
+			}
+			if got := Type(tt.err).Number(); got != tt.wantNumber {
+				t.Errorf("Number() = %v, want %v", got, tt.wantNumber)
+			}
+		})
+	}
+}
+
+func TestCategory(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCategory
+	}{
+		{"1", Error(DomainToASCII, "http://xn--a", true), CategoryIDNA},
+		{"2", Error(IPv6Unclosed, "http://[::1", true), CategoryHost},
+		{"3", Error(HostForbiddenAddress, "http://127.0.0.1", true), CategoryHost},
+		{"4", Error(PortInvalid, "http://example.com:a", true), CategoryURL},
+		{"5", fmt.Errorf("not a validation error"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Category(tt.err); got != tt.want {
+				t.Errorf("Category() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorCodesAreUnique(t *testing.T) {
+	names := make(map[string]bool)
+	numbers := make(map[int]bool)
+	for errorType, code := range errorCodes {
+		if code.name == "" || names[code.name] {
+			t.Errorf("error type %q has empty or duplicate code %q", errorType, code.name)
+		}
+		if code.category == "" {
+			t.Errorf("error type %q has no category", errorType)
+		}
+		if code.number == 0 || numbers[code.number] {
+			t.Errorf("error type %q has zero or duplicate number %d", errorType, code.number)
+		}
+		names[code.name] = true
+		numbers[code.number] = true
+	}
+}
+
+func TestValidationError_Is(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", ErrorWithDescr(PortInvalid, "abc", "http://example.com:abc", true))
+	if !stderrors.Is(err, Error(PortInvalid, "", true)) {
+		t.Errorf("errors.Is() = false, want true for same error type")
+	}
+	if stderrors.Is(err, Error(PortOutOfRange, "", true)) {
+		t.Errorf("errors.Is() = true, want false for different error type")
+	}
+}
+
+func TestSentinels(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		sentinel error
+		want     bool
+	}{
+		{"1", Error(HostMissing, "http://", true), ErrHostMissing, true},
+		{"2", fmt.Errorf("wrapped: %w", ErrorWithDescr(PortOutOfRange, "99999", "http://a:99999", true)), ErrPortOutOfRange, true},
+		{"3", stderrors.Join(Error(InvalidCredentials, "http://u@a", false), Error(PortInvalid, "http://u@a:b", true)), ErrPortInvalid, true},
+		{"4", Error(PortInvalid, "http://a:b", true), ErrPortOutOfRange, false},
+		{"5", Wrap(fmt.Errorf("cause"), DomainToASCII, "http://xn--a", true), ErrDomainToASCII, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stderrors.Is(tt.err, tt.sentinel); got != tt.want {
+				t.Errorf("errors.Is(%v, %v) = %v, want %v", tt.err, tt.sentinel, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package errors
+
+// Sentinel errors for the error types. A *ValidationError matches the sentinel for its error type with errors.Is
+// from the standard library, e.g. errors.Is(err, errors.ErrHostMissing).
+var (
+	ErrDomainToASCII                        = Error(DomainToASCII, "", true)
+	ErrDomainToUnicode                      = Error(DomainToUnicode, "", true)
+	ErrDomainInvalidCodePoint               = Error(DomainInvalidCodePoint, "", true)
+	ErrHostInvalidCodePoint                 = Error(HostInvalidCodePoint, "", true)
+	ErrIPv4EmptyPart                        = Error(IPv4EmptyPart, "", true)
+	ErrIPv4TooManyParts                     = Error(IPv4TooManyParts, "", true)
+	ErrIPv4NonNumericPart                   = Error(IPv4NonNumericPart, "", true)
+	ErrIPv4NonDecimalPart                   = Error(IPv4NonDecimalPart, "", true)
+	ErrIPv4OutOfRangePart                   = Error(IPv4OutOfRangePart, "", true)
+	ErrIPv6Unclosed                         = Error(IPv6Unclosed, "", true)
+	ErrIPv6InvalidCompression               = Error(IPv6InvalidCompression, "", true)
+	ErrIPv6TooManyPieces                    = Error(IPv6TooManyPieces, "", true)
+	ErrIPv6MultipleCompression              = Error(IPv6MultipleCompression, "", true)
+	ErrIPv6InvalidCodePoint                 = Error(IPv6InvalidCodePoint, "", true)
+	ErrIPv6TooFewPieces                     = Error(IPv6TooFewPieces, "", true)
+	ErrIPv4InIPv6TooManyPieces              = Error(IPv4InIPv6TooManyPieces, "", true)
+	ErrIPv4InIPv6InvalidCodePoint           = Error(IPv4InIPv6InvalidCodePoint, "", true)
+	ErrIPv4InIPv6OutOfRangePart             = Error(IPv4InIPv6OutOfRangePart, "", true)
+	ErrIPv4InIPv6TooFewParts                = Error(IPv4InIPv6TooFewParts, "", true)
+	ErrInvalidURLUnit                       = Error(InvalidURLUnit, "", true)
+	ErrSpecialSchemeMissingFollowingSolidus = Error(SpecialSchemeMissingFollowingSolidus, "", true)
+	ErrMissingSchemeNonRelativeURL          = Error(MissingSchemeNonRelativeURL, "", true)
+	ErrInvalidReverseSolidus                = Error(InvalidReverseSolidus, "", true)
+	ErrInvalidCredentials                   = Error(InvalidCredentials, "", true)
+	ErrHostMissing                          = Error(HostMissing, "", true)
+	ErrPortOutOfRange                       = Error(PortOutOfRange, "", true)
+	ErrPortInvalid                          = Error(PortInvalid, "", true)
+	ErrFileInvalidWindowsDriveLetter        = Error(FileInvalidWindowsDriveLetter, "", true)
+	ErrFileInvalidWindowsDriveLetterHost    = Error(FileInvalidWindowsDriveLetterHost, "", true)
+	ErrIPv6InvalidZoneID                    = Error(IPv6InvalidZoneID, "", true)
+	ErrHostResolution                       = Error(HostResolution, "", true)
+	ErrHostNotDotted                        = Error(HostNotDotted, "", true)
+	ErrDomainTooLong                        = Error(DomainTooLong, "", true)
+	ErrHostForbiddenAddress                 = Error(HostForbiddenAddress, "", true)
+	ErrIPv4Ambiguous                        = Error(IPv4Ambiguous, "", true)
+)
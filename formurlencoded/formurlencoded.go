@@ -0,0 +1,179 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package formurlencoded implements the application/x-www-form-urlencoded parser and serializer from the
+// [URL Standard].
+//
+// The same algorithm is used for the query of a URL by url.SearchParams and for the body of HTML form submissions,
+// e.g. when replaying POST requests.
+//
+// [URL Standard]: https://url.spec.whatwg.org/#application/x-www-form-urlencoded
+package formurlencoded
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+
+	"github.com/nlnwa/whatwg-url/percent"
+)
+
+// Pair is a name-value pair.
+type Pair struct {
+	Name, Value string
+}
+
+// EncodeSet decides which bytes are percent-encoded by a Serializer. A *url.PercentEncodeSet satisfies this
+// interface.
+type EncodeSet interface {
+	ByteShouldBeEncoded(b byte) bool
+}
+
+// FormEncodeSet is the application/x-www-form-urlencoded percent-encode set. All bytes except ASCII alphanumerics,
+// '*', '-', '.' and '_' are encoded.
+var FormEncodeSet EncodeSet = percent.FormSet
+
+// Parse parses input with the application/x-www-form-urlencoded parser. Names and values are decoded as UTF-8,
+// replacing invalid sequences with U+FFFD.
+//
+// See: https://url.spec.whatwg.org/#concept-urlencoded-parser
+func Parse(input []byte) []Pair {
+	return ParseFunc(string(input), decodeUTF8)
+}
+
+// ParseString is like Parse, but takes a string.
+func ParseString(input string) []Pair {
+	return ParseFunc(input, decodeUTF8)
+}
+
+// ParseFunc splits input into pairs like Parse, but leaves the decoding of names and values to decode. decode is
+// called after '+' is replaced by a space and is responsible for percent-decoding its argument.
+func ParseFunc(input string, decode func(s string) string) []Pair {
+	var pairs []Pair
+	for input != "" {
+		var sequence string
+		sequence, input, _ = strings.Cut(input, "&")
+		if sequence == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(sequence, "=")
+		pairs = append(pairs, Pair{
+			Name:  decode(strings.ReplaceAll(name, "+", " ")),
+			Value: decode(strings.ReplaceAll(value, "+", " ")),
+		})
+	}
+	return pairs
+}
+
+// decodeUTF8 percent-decodes s and decodes the result as UTF-8 without BOM.
+func decodeUTF8(s string) string {
+	s = percent.Decode(s)
+	if utf8.ValidString(s) {
+		return s
+	}
+	s, _ = unicode.UTF8.NewDecoder().String(s)
+	return s
+}
+
+// Serialize serializes pairs with the application/x-www-form-urlencoded serializer. If enc is nil, UTF-8 is used.
+//
+// See: https://url.spec.whatwg.org/#concept-urlencoded-serializer
+func Serialize(pairs []Pair, enc encoding.Encoding) string {
+	s := Serializer{Encoding: enc}
+	return s.Serialize(pairs)
+}
+
+// Serializer is an application/x-www-form-urlencoded serializer with a configurable percent-encode set. The zero
+// value uses FormEncodeSet and UTF-8.
+type Serializer struct {
+	// EncodeSet is the set of bytes to percent-encode. It must include all bytes above 0x7E. Default is FormEncodeSet.
+	EncodeSet EncodeSet
+	// Encoding is used to encode names and values before percent-encoding. Default is UTF-8.
+	Encoding encoding.Encoding
+}
+
+// Serialize serializes pairs, separating them with '&'.
+func (s *Serializer) Serialize(pairs []Pair) string {
+	var b []byte
+	for i, p := range pairs {
+		if i > 0 {
+			b = append(b, '&')
+		}
+		b = s.AppendEncoded(b, p.Name)
+		b = append(b, '=')
+		b = s.AppendEncoded(b, p.Value)
+	}
+	return string(b)
+}
+
+// AppendEncoded appends str to dst with the percent-encode after encoding algorithm, encoding spaces as '+'.
+// Code points which cannot be represented in the encoding are written as a percent-encoded HTML numeric character
+// reference, e.g. "%26%2328450%3B".
+//
+// See: https://url.spec.whatwg.org/#string-percent-encode-after-encoding
+func (s *Serializer) AppendEncoded(dst []byte, str string) []byte {
+	set := s.EncodeSet
+	if set == nil {
+		set = FormEncodeSet
+	}
+	if s.Encoding == nil {
+		for i := 0; i < len(str); i++ {
+			dst = appendByte(dst, str[i], set)
+		}
+		return dst
+	}
+
+	cm, _ := s.Encoding.(*charmap.Charmap)
+	var enc *encoding.Encoder
+	for _, r := range str {
+		if cm != nil {
+			if b, ok := cm.EncodeRune(r); ok {
+				dst = appendByte(dst, b, set)
+				continue
+			}
+		} else {
+			if enc == nil {
+				enc = s.Encoding.NewEncoder()
+			}
+			if b, err := enc.String(string(r)); err == nil {
+				for i := 0; i < len(b); i++ {
+					dst = appendByte(dst, b[i], set)
+				}
+				continue
+			}
+		}
+		dst = append(dst, "%26%23"...)
+		dst = strconv.AppendInt(dst, int64(r), 10)
+		dst = append(dst, "%3B"...)
+	}
+	return dst
+}
+
+const upperhex = "0123456789ABCDEF"
+
+func appendByte(dst []byte, b byte, set EncodeSet) []byte {
+	switch {
+	case b == ' ':
+		return append(dst, '+')
+	case set.ByteShouldBeEncoded(b):
+		return append(dst, '%', upperhex[b>>4], upperhex[b&15])
+	}
+	return append(dst, b)
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package formurlencoded
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Pair
+	}{
+		{"1", "", nil},
+		{"2", "a=b&c=d", []Pair{{"a", "b"}, {"c", "d"}}},
+		{"3", "&&a&=b&", []Pair{{"a", ""}, {"", "b"}}},
+		{"4", "a=b=c", []Pair{{"a", "b=c"}}},
+		{"5", "a+b=c%2Bd+e", []Pair{{"a b", "c+d e"}}},
+		{"6", "%zz=%4", []Pair{{"%zz", "%4"}}},
+		{"7", "bl%C3%A5b%C3%A6r=%E2%82", []Pair{{"blåbær", "\uFFFD"}}},
+		{"8", "%EF%BB%BFa=%FFb", []Pair{{"\uFEFFa", "\uFFFDb"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse([]byte(tt.input)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+			if got := ParseString(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSerialize(t *testing.T) {
+	tests := []struct {
+		name  string
+		pairs []Pair
+		enc   encoding.Encoding
+		want  string
+	}{
+		{"1", nil, nil, ""},
+		{"2", []Pair{{"a", "b"}, {"c", ""}}, nil, "a=b&c="},
+		{"3", []Pair{{"a b", "c+d&e=f"}}, nil, "a+b=c%2Bd%26e%3Df"},
+		{"4", []Pair{{"*-._~", "!'()"}}, nil, "*-._%7E=%21%27%28%29"},
+		{"5", []Pair{{"blåbær", "€"}}, nil, "bl%C3%A5b%C3%A6r=%E2%82%AC"},
+		{"6", []Pair{{"blåbær", "€"}}, charmap.ISO8859_1, "bl%E5b%E6r=%26%238364%3B"},
+		{"7", []Pair{{"漢", "€"}}, japanese.ShiftJIS, "%8A%BF=%26%238364%3B"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Serialize(tt.pairs, tt.enc); got != tt.want {
+				t.Errorf("Serialize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSerializer_EncodeSet(t *testing.T) {
+	s := Serializer{EncodeSet: encodeSetFunc(func(b byte) bool { return b > 0x7E || b == '&' || b == '=' })}
+	if got, want := s.Serialize([]Pair{{"a/b", "c d&e"}}), "a/b=c+d%26e"; got != want {
+		t.Errorf("Serialize() = %v, want %v", got, want)
+	}
+}
+
+type encodeSetFunc func(b byte) bool
+
+func (f encodeSetFunc) ByteShouldBeEncoded(b byte) bool {
+	return f(b)
+}
@@ -1,9 +1,10 @@
 module github.com/nlnwa/whatwg-url
 
-go 1.16
+go 1.20
 
 require (
 	github.com/bits-and-blooms/bitset v1.13.0
 	golang.org/x/net v0.25.0
 	golang.org/x/text v0.15.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,226 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package idn implements the IDNA processing used by the WHATWG URL parser.
+//
+// The functions in this package use the same IDNA profile and the same fallback heuristics as the host parser
+// in the url package, making it possible to get spec-identical domain conversion outside of URL parsing.
+package idn
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// Profile is the IDNA profile used for domain to ASCII and domain to Unicode.
+var Profile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.VerifyDNSLength(false),
+	idna.StrictDomainName(true),
+	idna.ValidateLabels(true),
+	idna.CheckHyphens(false),
+	idna.CheckJoiners(true),
+	idna.Transitional(false),
+)
+
+// ToASCII converts a domain to ASCII (https://url.spec.whatwg.org/#concept-domain-to-ascii).
+//
+// If the conversion fails, the partially converted domain is returned together with the error.
+// Unless beStrict is true, a domain which contains only ASCII (or a few allowed symbols) and no punycode labels
+// is accepted even if it is not a valid domain name.
+func ToASCII(domain string, beStrict bool) (string, error) {
+	if domain == "" {
+		return "", nil
+	}
+	if isLowerASCIIDomain(domain) {
+		// The UTS #46 mapping is the identity and all validity criteria are met
+		return domain, nil
+	}
+
+	// Convert to punycode
+	a, err := Profile.ToASCII(domain)
+	if err != nil {
+		if !beStrict && ContainsOnlyASCIIOrMiscAndNoPunycode(domain) {
+			return a, nil
+		}
+		return a, findLabelError(domain, err)
+	}
+	if a == "" {
+		return "", fmt.Errorf("idna toAscii returned empty string")
+	}
+	return a, nil
+}
+
+// isLowerASCIIDomain returns true if domain consists of non-empty labels of lowercase ASCII letters, digits and
+// hyphens, optionally followed by a trailing dot, and has no punycode labels ("xn--").
+func isLowerASCIIDomain(domain string) bool {
+	labelStart := 0
+	for i := 0; i < len(domain); i++ {
+		c := domain[i]
+		switch {
+		case 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-':
+		case c == '.':
+			if i == labelStart || strings.HasPrefix(domain[labelStart:i], "xn--") {
+				return false
+			}
+			labelStart = i + 1
+		default:
+			return false
+		}
+	}
+	if labelStart == len(domain) {
+		// Empty domain or trailing dot
+		return labelStart > 0
+	}
+	return !strings.HasPrefix(domain[labelStart:], "xn--")
+}
+
+// Names of the UTS #46 rules reported in LabelError.
+const (
+	RulePunycode         = "Punycode"
+	RuleDisallowed       = "Disallowed"
+	RuleUseSTD3ASCII     = "UseSTD3ASCIIRules"
+	RuleValidityCriteria = "ValidityCriteria"
+	RuleCheckJoiners     = "CheckJoiners"
+	RuleCheckBidi        = "CheckBidi"
+)
+
+// LabelError describes which label of a domain failed conversion to ASCII and why.
+type LabelError struct {
+	Label string // the label as it appears in the input
+	Index int    // zero based index of the label
+	Rule  string // the UTS #46 rule violated by the label
+	Err   error  // the error returned by the IDNA implementation
+}
+
+func (e *LabelError) Error() string {
+	return fmt.Sprintf("idn: label %q (index %d) violates %s: %v", e.Label, e.Index, e.Rule, e.Err)
+}
+
+// Unwrap returns the error returned by the IDNA implementation
+func (e *LabelError) Unwrap() error {
+	return e.Err
+}
+
+// labelSeparators are the code points mapped to '.' by UTS #46.
+const labelSeparators = ".\u3002\uff0e\uff61"
+
+// ruleChecks are profiles where one check is disabled. A label which is accepted by one of them violates the rule
+// of the disabled check.
+var ruleChecks = []struct {
+	rule    string
+	profile *idna.Profile
+}{
+	{RuleUseSTD3ASCII, newProfile(false, true, true, true)},
+	{RuleCheckJoiners, newProfile(true, false, true, true)},
+	{RuleCheckBidi, newProfile(true, true, false, true)},
+	{RuleValidityCriteria, newProfile(true, true, true, false)},
+}
+
+// findLabelError returns a LabelError for the first label in domain which fails conversion to ASCII.
+// If no single label can be blamed, err is returned unchanged.
+func findLabelError(domain string, err error) error {
+	index := 0
+	start := 0
+	for i, r := range domain + "." {
+		if !strings.ContainsRune(labelSeparators, r) {
+			continue
+		}
+		label := domain[start:i]
+		if _, labelErr := Profile.ToASCII(label); label != "" && labelErr != nil {
+			return &LabelError{Label: label, Index: index, Rule: violatedRule(label), Err: labelErr}
+		}
+		index++
+		start = i + utf8.RuneLen(r)
+	}
+	return err
+}
+
+// violatedRule determines which rule a label violates.
+func violatedRule(label string) string {
+	if strings.HasPrefix(strings.ToLower(label), "xn--") {
+		if _, err := idna.Punycode.ToUnicode(label); err != nil {
+			return RulePunycode
+		}
+	}
+	for _, c := range ruleChecks {
+		if _, err := c.profile.ToASCII(label); err == nil {
+			return c.rule
+		}
+	}
+	return RuleDisallowed
+}
+
+// newProfile returns a profile equal to Profile except for the checks given as arguments.
+func newProfile(std3, joiners, bidi, validate bool) *idna.Profile {
+	opts := []idna.Option{
+		idna.MapForLookup(),
+		idna.VerifyDNSLength(false),
+		idna.StrictDomainName(std3),
+		idna.ValidateLabels(validate),
+		idna.CheckHyphens(false),
+		idna.CheckJoiners(joiners),
+		idna.Transitional(false),
+	}
+	if bidi {
+		opts = append(opts, idna.BidiRule())
+	}
+	return idna.New(opts...)
+}
+
+// ToUnicode converts a domain to Unicode (https://url.spec.whatwg.org/#concept-domain-to-unicode).
+//
+// The converted domain is always returned. A non nil error signifies a validation error.
+// Unless beStrict is true, validation errors for domains which contain only ASCII (or a few allowed symbols)
+// and no punycode labels are ignored.
+func ToUnicode(domain string, beStrict bool) (string, error) {
+	u, err := Profile.ToUnicode(domain)
+	if err != nil && !beStrict && ContainsOnlyASCIIOrMiscAndNoPunycode(domain) {
+		return u, nil
+	}
+	return u, err
+}
+
+// ContainsOnlyASCIIOrMiscAndNoPunycode returns true if the string contains only ASCII characters or characters from Section 4.1.1 in UTS #46
+// and does not contain any labels starting with acePrefix (xn--)
+func ContainsOnlyASCIIOrMiscAndNoPunycode(s string) bool {
+	s = strings.ToLower(s)
+	p := 0
+	for _, r := range s {
+		if r >= utf8.RuneSelf && r != '\u2260' && r != '\u226e' && r != '\u226f' {
+			return false
+		}
+		switch {
+		case r == '.':
+			p = 0
+		case p == 0 && r == 'x':
+			p = 1
+		case p == 1 && r == 'n':
+			p = 2
+		case p == 2 && r == '-':
+			p = 3
+		case p == 3 && r == '-':
+			return false
+		default:
+			p = -1
+		}
+	}
+	return true
+}
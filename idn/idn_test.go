@@ -0,0 +1,182 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package idn
+
+import "testing"
+
+func TestToASCII(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		beStrict bool
+		want     string
+		wantErr  bool
+	}{
+		{"1", "BÜCHER.example", false, "xn--bcher-kva.example", false},
+		{"2", "faß.ExAmPlE", false, "xn--fa-hia.example", false},
+		{"3", "a_b.example", false, "a_b.example", false},
+		{"4", "a_b.example", true, "a_b.example", true},
+		{"5", "xn--a.example", false, "", true},
+		{"6", "", false, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToASCII(tt.input, tt.beStrict)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ToASCII(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				return
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("ToASCII(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToUnicode(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"1", "xn--bcher-kva.example", "bücher.example", false},
+		{"2", "example.com", "example.com", false},
+		{"3", "a_b.example", "a_b.example", false},
+		{"4", "xn--a.example", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToUnicode(tt.input, false)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ToUnicode(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("ToUnicode(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainsOnlyASCIIOrMiscAndNoPunycode(t *testing.T) {
+	tests := []struct {
+		Input  string
+		Output bool
+	}{
+		{"abc", true},
+		{"xn--abc", false},
+		{"abcxn--", true},
+		{"abc.xn--", false},
+		{"xnabc--", true},
+		{"xn.--", true},
+		{"ab\u2260c", true},
+		{"ab\u2261c", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Input, func(t *testing.T) {
+			got := ContainsOnlyASCIIOrMiscAndNoPunycode(tt.Input)
+
+			if got != tt.Output {
+				t.Errorf("ContainsOnlyASCIIOrMiscAndNoPunycode(%v) = '%v', want '%v'", tt.Input, got, tt.Output)
+			}
+		})
+	}
+}
+
+func TestToASCII_LabelError(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantLabel string
+		wantIndex int
+		wantRule  string
+	}{
+		{"1", "a_b.example", "a_b", 0, RuleUseSTD3ASCII},
+		{"2", "www.xn--zz-zz.example", "xn--zz-zz", 1, RulePunycode},
+		{"3", "www.xn--a.example", "xn--a", 1, RuleValidityCriteria},
+		{"4", "ok.a\u200db", "a\u200db", 1, RuleCheckJoiners},
+		{"5", "ok.ok.اb", "اb", 2, RuleCheckBidi},
+		{"6", "a\uffffb.example", "a\uffffb", 0, RuleDisallowed},
+		{"7", "a..a_b", "a_b", 2, RuleUseSTD3ASCII},
+		{"8", "a。xn--a", "xn--a", 1, RuleValidityCriteria},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ToASCII(tt.input, true)
+			labelErr, ok := err.(*LabelError)
+			if !ok {
+				t.Fatalf("ToASCII(%v) error = %v, want *LabelError", tt.input, err)
+			}
+			if labelErr.Label != tt.wantLabel || labelErr.Index != tt.wantIndex || labelErr.Rule != tt.wantRule {
+				t.Errorf("ToASCII(%v) error = {%q, %d, %v}, want {%q, %d, %v}", tt.input,
+					labelErr.Label, labelErr.Index, labelErr.Rule, tt.wantLabel, tt.wantIndex, tt.wantRule)
+			}
+			if labelErr.Unwrap() == nil {
+				t.Errorf("ToASCII(%v) error does not wrap the IDNA error", tt.input)
+			}
+		})
+	}
+}
+
+func TestToASCII_LowerASCIIDomain(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		fastPath bool
+	}{
+		{"1", "example.com", true},
+		{"2", "www-1.example.com.", true},
+		{"3", "a--b.example", true},
+		{"4", "-a.example-", true},
+		{"5", "Example.com", false},
+		{"6", "xn--bcher-kva.example", false},
+		{"7", "a.xn--bcher-kva", false},
+		{"8", "a..b", false},
+		{"9", ".a", false},
+		{"10", "a_b.example", false},
+		{"11", "bücher.example", false},
+		{"12", "a.", true},
+		{"13", ".", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isLowerASCIIDomain(tt.input); got != tt.fastPath {
+				t.Errorf("isLowerASCIIDomain(%v) = %v, want %v", tt.input, got, tt.fastPath)
+			}
+			if !tt.fastPath {
+				return
+			}
+			// The fast path must give the same result as the IDNA profile
+			want, err := Profile.ToASCII(tt.input)
+			if err != nil {
+				t.Fatalf("Profile.ToASCII(%v) error = %v", tt.input, err)
+			}
+			for _, beStrict := range []bool{false, true} {
+				if got, err := ToASCII(tt.input, beStrict); err != nil || got != want {
+					t.Errorf("ToASCII(%v, %v) = %v, %v, want %v", tt.input, beStrict, got, err, want)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkToASCII(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = ToASCII("www.example.com", false)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package percent implements percent-encoding and percent-decoding as defined by the [WHATWG URL Standard].
+//
+// The functions work on bytes, so code points outside ASCII are encoded as their UTF-8 bytes. Decoding never fails:
+// a '%' which is not followed by two hex digits is kept as is.
+//
+// [WHATWG URL Standard]: https://url.spec.whatwg.org/#percent-encoded-bytes
+package percent
+
+import (
+	"strings"
+)
+
+const upperhex = "0123456789ABCDEF"
+
+// Encode returns s with the bytes in set percent-encoded. If set is nil, all bytes are encoded.
+//
+// See: https://url.spec.whatwg.org/#string-utf-8-percent-encode
+func Encode(s string, set *Set) string {
+	if set != nil && !shouldEncode(s, set) {
+		return s
+	}
+	return string(AppendEncode(make([]byte, 0, len(s)+16), s, set))
+}
+
+// EncodeBytes is like Encode, but takes and returns a byte slice.
+func EncodeBytes(b []byte, set *Set) []byte {
+	return AppendEncode(make([]byte, 0, len(b)+16), string(b), set)
+}
+
+// AppendEncode appends s to dst with the bytes in set percent-encoded and returns the extended buffer.
+// If set is nil, all bytes are encoded.
+func AppendEncode(dst []byte, s string, set *Set) []byte {
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if set != nil && !set.ByteShouldBeEncoded(b) {
+			dst = append(dst, b)
+		} else {
+			dst = append(dst, '%', upperhex[b>>4], upperhex[b&15])
+		}
+	}
+	return dst
+}
+
+// Decode returns s with percent-encoded bytes decoded. The result may be invalid UTF-8.
+//
+// See: https://url.spec.whatwg.org/#string-percent-decode
+func Decode(s string) string {
+	if strings.IndexByte(s, '%') < 0 {
+		return s
+	}
+	return string(AppendDecode(make([]byte, 0, len(s)), s))
+}
+
+// DecodeBytes is like Decode, but takes and returns a byte slice.
+func DecodeBytes(b []byte) []byte {
+	return AppendDecode(make([]byte, 0, len(b)), string(b))
+}
+
+// AppendDecode appends s to dst with percent-encoded bytes decoded and returns the extended buffer.
+func AppendDecode(dst []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if b == '%' && i+2 < len(s) && IsHex(s[i+1]) && IsHex(s[i+2]) {
+			b = Unhex(s[i+1])<<4 | Unhex(s[i+2])
+			i += 2
+		}
+		dst = append(dst, b)
+	}
+	return dst
+}
+
+// IsHex returns true if c is an ASCII hex digit.
+func IsHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// Unhex returns the value of the ASCII hex digit c, or 0 if c is not a hex digit.
+func Unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10
+	}
+	return 0
+}
+
+func shouldEncode(s string, set *Set) bool {
+	for i := 0; i < len(s); i++ {
+		if set.ByteShouldBeEncoded(s[i]) {
+			return true
+		}
+	}
+	return false
+}
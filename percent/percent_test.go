@@ -0,0 +1,98 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package percent
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		set  *Set
+		want string
+	}{
+		{"1", "", PathSet, ""},
+		{"2", "abc", PathSet, "abc"},
+		{"3", "a b?c#d", PathSet, "a%20b%3Fc%23d"},
+		{"4", "a b?c#d", QuerySet, "a%20b?c%23d"},
+		{"5", "blåbær", C0ControlSet, "bl%C3%A5b%C3%A6r"},
+		{"6", "a:b@c/d", UserinfoSet, "a%3Ab%40c%2Fd"},
+		{"7", "a+b&c", ComponentSet, "a%2Bb%26c"},
+		{"8", "a!b~c*", FormSet, "a%21b%7Ec*"},
+		{"9", "100%", FragmentSet, "100%"},
+		{"10", "ab", nil, "%61%62"},
+		{"11", "\xff", C0ControlSet, "%FF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Encode(tt.s, tt.set); got != tt.want {
+				t.Errorf("Encode() = %v, want %v", got, tt.want)
+			}
+			if got := EncodeBytes([]byte(tt.s), tt.set); !bytes.Equal(got, []byte(tt.want)) {
+				t.Errorf("EncodeBytes() = %s, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"1", "", ""},
+		{"2", "abc", "abc"},
+		{"3", "a%20b%3fc", "a b?c"},
+		{"4", "bl%C3%A5b%C3%A6r", "blåbær"},
+		{"5", "%", "%"},
+		{"6", "%4", "%4"},
+		{"7", "%zz%41", "%zzA"},
+		{"8", "%%41", "%A"},
+		{"9", "%ff", "\xff"},
+		{"10", "%2541", "%41"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Decode(tt.s); got != tt.want {
+				t.Errorf("Decode() = %v, want %v", got, tt.want)
+			}
+			if got := DecodeBytes([]byte(tt.s)); !bytes.Equal(got, []byte(tt.want)) {
+				t.Errorf("DecodeBytes() = %s, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	s := NewSet(0x20, '%')
+	if !s.ByteShouldBeEncoded('%') || s.ByteShouldBeEncoded('a') || !s.ByteShouldBeEncoded(0x1f) || !s.ByteShouldBeEncoded(0x80) {
+		t.Errorf("NewSet() = %v", s)
+	}
+	if c := s.Clear('%'); c.ByteShouldBeEncoded('%') || !s.ByteShouldBeEncoded('%') {
+		t.Errorf("Clear() modified the original set or did not clear")
+	}
+	if c := s.Set('a'); !c.ByteShouldBeEncoded('a') || s.ByteShouldBeEncoded('a') {
+		t.Errorf("Set() modified the original set or did not set")
+	}
+	if !s.RuneShouldBeEncoded('å') || !s.RuneNotInSet('å') || s.RuneNotInSet('%') {
+		t.Errorf("RuneShouldBeEncoded() or RuneNotInSet() wrong for non-ASCII")
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package percent
+
+import (
+	"github.com/bits-and-blooms/bitset"
+)
+
+// Set is a percent-encode set. Code points below a given value, code points above U+007E and the ASCII code points
+// added to the set are percent-encoded.
+type Set struct {
+	bs       *bitset.BitSet
+	allBelow int32
+}
+
+// NewSet returns a set with all code points below allBelow and the given bytes.
+func NewSet(allBelow int32, bytes ...uint) *Set {
+	p := &Set{allBelow: allBelow, bs: bitset.New(0x7f)}
+	for _, b := range bytes {
+		p.bs.Set(b)
+	}
+	return p
+}
+
+// Set returns a copy of the set with the given bytes added.
+func (p *Set) Set(bytes ...uint) *Set {
+	r := &Set{
+		allBelow: p.allBelow,
+		bs:       p.bs.Clone(),
+	}
+	for _, b := range bytes {
+		r.bs.Set(b)
+	}
+	return r
+}
+
+// Clear returns a copy of the set with the given bytes removed.
+func (p *Set) Clear(bytes ...uint) *Set {
+	r := &Set{
+		allBelow: p.allBelow,
+		bs:       p.bs.Clone(),
+	}
+	for _, b := range bytes {
+		r.bs.Clear(b)
+	}
+	return r
+}
+
+// RuneShouldBeEncoded returns true if r is in the set.
+func (p *Set) RuneShouldBeEncoded(r rune) bool {
+	if r < p.allBelow || r > 0x007E || p.bs.Test(uint(r)) {
+		return true
+	}
+	return false
+}
+
+// ByteShouldBeEncoded returns true if b is in the set.
+func (p *Set) ByteShouldBeEncoded(b byte) bool {
+	if int32(b) < p.allBelow || b > 0x007E || p.bs.Test(uint(b)) {
+		return true
+	}
+	return false
+}
+
+// RuneNotInSet returns true if r is neither below the limit of the set nor one of the bytes added to it.
+// Unlike RuneShouldBeEncoded, code points above U+007E are not considered part of the set.
+func (p *Set) RuneNotInSet(r rune) bool {
+	if r < p.allBelow || p.bs.Test(uint(r)) {
+		return false
+	}
+	return true
+}
+
+// The percent-encode sets defined by the WHATWG URL Standard (https://url.spec.whatwg.org/#percent-encoded-bytes).
+var (
+	// C0ControlSet contains the C0 controls and all code points greater than U+007E.
+	C0ControlSet = NewSet(0x20)
+	// FragmentSet is the C0ControlSet and space, '"', '<', '>' and '`'.
+	FragmentSet = NewSet(0x21, 0x22, 0x3C, 0x3E, 0x60)
+	// QuerySet is the C0ControlSet and space, '"', '#', '<' and '>'.
+	QuerySet = NewSet(0x21, 0x22, 0x23, 0x3C, 0x3E)
+	// SpecialQuerySet is the QuerySet and '\''.
+	SpecialQuerySet = QuerySet.Set(0x27)
+	// PathSet is the QuerySet and '?', '`', '{' and '}'.
+	PathSet = QuerySet.Set(0x3F, 0x60, 0x7B, 0x7D)
+	// UserinfoSet is the PathSet and '/', ':', ';', '=', '@', '[' to '^' and '|'.
+	UserinfoSet = PathSet.Set(0x2F, 0x3A, 0x3B, 0x3D, 0x40, 0x5B, 0x5C, 0x5D, 0x5E, 0x7C)
+	// ComponentSet is the UserinfoSet and '$' to '&', '+' and ','. It is used by encodeURIComponent.
+	ComponentSet = UserinfoSet.Set(0x24, 0x25, 0x26, 0x2B, 0x2C)
+	// FormSet is the ComponentSet and '!', '\'' to ')' and '~'. It is the application/x-www-form-urlencoded
+	// percent-encode set.
+	FormSet = ComponentSet.Set(0x21, 0x27, 0x28, 0x29, 0x7E)
+)
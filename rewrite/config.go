@@ -0,0 +1,115 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rewrite
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Config describes a Rewriter in a form which can be stored in a configuration file.
+//
+// Example, moving a site to https and a new path:
+//
+//	{
+//	  "rules": [
+//	    {
+//	      "name": "moveBlog",
+//	      "match": {"schemes": ["http"], "hosts": ["example.com", "*.example.com"], "path": "/blog/*"},
+//	      "actions": [
+//	        {"component": "protocol", "value": "https"},
+//	        {"component": "host", "value": "blog.example.org"},
+//	        {"component": "pathname", "pattern": "^/blog/", "replacement": "/"}
+//	      ],
+//	      "stop": true
+//	    }
+//	  ]
+//	}
+type Config struct {
+	// Rules are applied in the order given.
+	Rules []RuleConfig `json:"rules"`
+}
+
+// RuleConfig is a rule which applies its actions to urls matching all the conditions in Match.
+type RuleConfig struct {
+	// Name identifies the rule in the result of Rewriter.Rewrite.
+	Name    string         `json:"name,omitempty"`
+	Match   MatchConfig    `json:"match"`
+	Actions []ActionConfig `json:"actions"`
+	// Stop makes the rewriter skip the remaining rules when this rule matches.
+	Stop bool `json:"stop,omitempty"`
+}
+
+// MatchConfig contains the conditions of a rule. Empty conditions match all urls.
+type MatchConfig struct {
+	// Schemes matches urls with one of the schemes, e.g. "http".
+	Schemes []string `json:"schemes,omitempty"`
+	// Hosts matches urls with a host matching one of the patterns. See url.MatchHost for the syntax.
+	Hosts []string `json:"hosts,omitempty"`
+	// Path matches the whole pathname, where '*' matches any sequence of characters, e.g. "/blog/*".
+	Path string `json:"path,omitempty"`
+	// PathRegexp matches urls where the regular expression matches the pathname. It can not be combined with Path.
+	PathRegexp string `json:"pathRegexp,omitempty"`
+}
+
+// ActionConfig changes a component of the url. The component is one of protocol, username, password, host,
+// hostname, port, pathname, search and hash, with the values of the WHATWG URL API (e.g. search starts with '?').
+//
+// If Pattern is empty, the component is set to Value. Otherwise all matches of the regular expression Pattern in the
+// component are replaced by Replacement, which can refer to submatches as in regexp.Regexp.Expand (e.g. "$1").
+type ActionConfig struct {
+	Component   string `json:"component"`
+	Value       string `json:"value,omitempty"`
+	Pattern     string `json:"pattern,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// New creates a Rewriter from a Config.
+func New(c *Config) (*Rewriter, error) {
+	rw := &Rewriter{}
+	for i, rc := range c.Rules {
+		r, err := newRule(i+1, rc)
+		if err != nil {
+			return nil, fmt.Errorf("rewrite: rule %d: %w", i+1, err)
+		}
+		rw.rules = append(rw.rules, r)
+	}
+	return rw, nil
+}
+
+// LoadConfigFile reads a JSON encoded Config from a file and creates a Rewriter from it.
+func LoadConfigFile(path string) (*Rewriter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadConfig(f)
+}
+
+// LoadConfig reads a JSON encoded Config and creates a Rewriter from it. Unknown fields are rejected.
+func LoadConfig(r io.Reader) (*Rewriter, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	c := &Config{}
+	if err := dec.Decode(c); err != nil {
+		return nil, fmt.Errorf("rewrite: could not decode config: %w", err)
+	}
+	return New(c)
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rewrite
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	config := `{
+	  "rules": [
+	    {
+	      "name": "moveBlog",
+	      "match": {"schemes": ["http:"], "hosts": ["*.example.com"], "path": "/blog/*"},
+	      "actions": [
+	        {"component": "protocol", "value": "https"},
+	        {"component": "pathname", "pattern": "^/blog/(.*)$", "replacement": "/archive/$1"}
+	      ]
+	    }
+	  ]
+	}`
+	rw, err := LoadConfig(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	got, err := rw.RewriteString("http://www.example.com/blog/a/b")
+	if err != nil {
+		t.Fatalf("RewriteString() error = %v", err)
+	}
+	if want := "https://www.example.com/archive/a/b"; got != want {
+		t.Errorf("RewriteString() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadConfig_Error(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{"1", `{"rules": [{"match": {}, "actions": [{"component": "href", "value": "x"}]}]}`},
+		{"2", `{"rules": [{"match": {"hosts": ["exa mple.com"]}, "actions": []}]}`},
+		{"3", `{"rules": [{"match": {"pathRegexp": "("}, "actions": []}]}`},
+		{"4", `{"rules": [{"match": {"path": "/a", "pathRegexp": "/a"}, "actions": []}]}`},
+		{"5", `{"rules": [{"match": {}, "actions": [{"component": "search", "pattern": "["}]}]}`},
+		{"6", `{"rules": [{"unknown": true}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadConfig(strings.NewReader(tt.config)); err == nil {
+				t.Errorf("LoadConfig() error = nil, want error")
+			}
+		})
+	}
+}
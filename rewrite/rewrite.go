@@ -0,0 +1,204 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package rewrite implements rule based rewriting of URLs, e.g. for mapping URLs to a new site when replaying web
+// archives or migrating content.
+//
+// A Rewriter applies an ordered list of rules to a parsed url. A rule matches on scheme, host and path, and rewrites
+// the matching url by setting components or by regular expression replacement within a component. Components are
+// changed with the setters of url.Url, so the result is normalized exactly like a parsed url.
+package rewrite
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+// component gets and sets a component of a url. The names and semantics are those of the WHATWG URL API.
+type component struct {
+	get func(u *url.Url) string
+	set func(u *url.Url, value string)
+}
+
+var components = map[string]component{
+	"protocol": {(*url.Url).Protocol, (*url.Url).SetProtocol},
+	"username": {(*url.Url).Username, (*url.Url).SetUsername},
+	"password": {(*url.Url).Password, (*url.Url).SetPassword},
+	"host":     {(*url.Url).Host, (*url.Url).SetHost},
+	"hostname": {(*url.Url).Hostname, (*url.Url).SetHostname},
+	"port":     {(*url.Url).Port, (*url.Url).SetPort},
+	"pathname": {(*url.Url).Pathname, (*url.Url).SetPathname},
+	"search":   {(*url.Url).Search, (*url.Url).SetSearch},
+	"hash":     {(*url.Url).Hash, (*url.Url).SetHash},
+}
+
+// Rewriter applies rules to urls. A Rewriter is safe for concurrent use.
+type Rewriter struct {
+	rules []*rule
+}
+
+type rule struct {
+	name    string
+	schemes map[string]bool
+	hosts   *url.HostMatcher
+	path    *regexp.Regexp
+	actions []action
+	stop    bool
+}
+
+type action struct {
+	component   component
+	value       string
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// Rewrite applies the matching rules to u in order and returns the names of the rules which matched. Unnamed rules
+// are reported by their position in the configuration, e.g. "#2" for the second rule.
+//
+// Each rule sees the url as rewritten by the rules before it. Values which the setters reject, e.g. an invalid port,
+// leave the component unchanged.
+func (rw *Rewriter) Rewrite(u *url.Url) []string {
+	var matched []string
+	for _, r := range rw.rules {
+		if !r.match(u) {
+			continue
+		}
+		for i := range r.actions {
+			r.actions[i].apply(u)
+		}
+		matched = append(matched, r.name)
+		if r.stop {
+			break
+		}
+	}
+	return matched
+}
+
+// RewriteString parses rawUrl with the default url parser, rewrites it and returns the serialized result.
+func (rw *Rewriter) RewriteString(rawUrl string) (string, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", err
+	}
+	rw.Rewrite(u)
+	return u.Href(false), nil
+}
+
+// Apply rewrites u. It makes a Rewriter usable as a canonicalizer.Rule.
+func (rw *Rewriter) Apply(u *url.Url) error {
+	rw.Rewrite(u)
+	return nil
+}
+
+func (r *rule) match(u *url.Url) bool {
+	if r.schemes != nil && !r.schemes[u.Scheme()] {
+		return false
+	}
+	if r.hosts != nil {
+		h := u.ParsedHost()
+		if h == nil || !r.hosts.MatchHost(h) {
+			return false
+		}
+	}
+	if r.path != nil && !r.path.MatchString(u.Pathname()) {
+		return false
+	}
+	return true
+}
+
+func (a *action) apply(u *url.Url) {
+	if a.pattern == nil {
+		a.component.set(u, a.value)
+		return
+	}
+	old := a.component.get(u)
+	if v := a.pattern.ReplaceAllString(old, a.replacement); v != old {
+		a.component.set(u, v)
+	}
+}
+
+// globToRegexp converts a path pattern where '*' matches any sequence of characters, including '/', to an anchored
+// regular expression.
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteByte('^')
+	for i, part := range strings.Split(glob, "*") {
+		if i > 0 {
+			sb.WriteString(".*")
+		}
+		sb.WriteString(regexp.QuoteMeta(part))
+	}
+	sb.WriteByte('$')
+	return regexp.Compile(sb.String())
+}
+
+func newRule(index int, c RuleConfig) (*rule, error) {
+	r := &rule{name: c.Name, stop: c.Stop}
+	if r.name == "" {
+		r.name = fmt.Sprintf("#%d", index)
+	}
+	if len(c.Match.Schemes) > 0 {
+		r.schemes = make(map[string]bool, len(c.Match.Schemes))
+		for _, s := range c.Match.Schemes {
+			r.schemes[strings.ToLower(strings.TrimSuffix(s, ":"))] = true
+		}
+	}
+	if len(c.Match.Hosts) > 0 {
+		m, err := url.NewHostMatcher(c.Match.Hosts...)
+		if err != nil {
+			return nil, err
+		}
+		r.hosts = m
+	}
+	if c.Match.Path != "" {
+		re, err := globToRegexp(c.Match.Path)
+		if err != nil {
+			return nil, err
+		}
+		r.path = re
+	}
+	if c.Match.PathRegexp != "" {
+		if r.path != nil {
+			return nil, fmt.Errorf("path and pathRegexp can not both be set")
+		}
+		re, err := regexp.Compile(c.Match.PathRegexp)
+		if err != nil {
+			return nil, err
+		}
+		r.path = re
+	}
+
+	for _, ac := range c.Actions {
+		comp, ok := components[ac.Component]
+		if !ok {
+			return nil, fmt.Errorf("unknown component %q", ac.Component)
+		}
+		a := action{component: comp, value: ac.Value, replacement: ac.Replacement}
+		if ac.Pattern != "" {
+			re, err := regexp.Compile(ac.Pattern)
+			if err != nil {
+				return nil, err
+			}
+			a.pattern = re
+		}
+		r.actions = append(r.actions, a)
+	}
+	return r, nil
+}
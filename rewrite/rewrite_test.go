@@ -0,0 +1,102 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rewrite
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nlnwa/whatwg-url/canonicalizer"
+)
+
+func TestRewriter_Rewrite(t *testing.T) {
+	rw, err := New(&Config{Rules: []RuleConfig{
+		{
+			Name:  "moveBlog",
+			Match: MatchConfig{Schemes: []string{"http"}, Hosts: []string{"example.com", "*.example.com"}, Path: "/blog/*"},
+			Actions: []ActionConfig{
+				{Component: "protocol", Value: "https"},
+				{Component: "host", Value: "blog.example.org"},
+				{Component: "pathname", Pattern: "^/blog/", Replacement: "/"},
+			},
+			Stop: true,
+		},
+		{
+			Match:   MatchConfig{PathRegexp: `\.php$`},
+			Actions: []ActionConfig{{Component: "pathname", Pattern: `\.php$`, Replacement: ".html"}},
+		},
+		{
+			Match:   MatchConfig{Hosts: []string{"*.example.net"}},
+			Actions: []ActionConfig{{Component: "search", Pattern: `(^\?|&)sid=[^&]*`, Replacement: ""}},
+		},
+		{
+			Name:    "invalidPort",
+			Match:   MatchConfig{Hosts: []string{"port.example"}},
+			Actions: []ActionConfig{{Component: "port", Value: "x"}, {Component: "hash", Value: "moved"}},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		url         string
+		want        string
+		wantMatched []string
+	}{
+		{"1", "http://www.example.com/blog/2020/post.php?a=b", "https://blog.example.org/2020/post.php?a=b", []string{"moveBlog"}},
+		{"2", "https://www.example.com/blog/post.php", "https://www.example.com/blog/post.html", []string{"#2"}},
+		{"3", "http://example.com/news/", "http://example.com/news/", nil},
+		{"4", "http://www.example.net/a.php?sid=1", "http://www.example.net/a.html", []string{"#2", "#3"}},
+		{"5", "http://www.example.net/?a=1&sid=2&b=3", "http://www.example.net/?a=1&b=3", []string{"#3"}},
+		{"6", "http://port.example:8080/", "http://port.example:8080/#moved", []string{"invalidPort"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rw.RewriteString(tt.url)
+			if err != nil {
+				t.Fatalf("RewriteString() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RewriteString() = %v, want %v", got, tt.want)
+			}
+			u, _ := canonicalizer.WhatWg.Parse(tt.url)
+			if matched := rw.Rewrite(u); !reflect.DeepEqual(matched, tt.wantMatched) {
+				t.Errorf("Rewrite() = %v, want %v", matched, tt.wantMatched)
+			}
+		})
+	}
+}
+
+func TestRewriter_Rule(t *testing.T) {
+	rw, err := New(&Config{Rules: []RuleConfig{{
+		Match:   MatchConfig{Hosts: []string{"old.example"}},
+		Actions: []ActionConfig{{Component: "hostname", Value: "new.example"}},
+	}}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	p := canonicalizer.New(canonicalizer.WithRule(rw), canonicalizer.WithRemoveFragment())
+	u, err := p.Parse("http://OLD.example/a#b")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got, want := u.String(), "http://new.example/a"; got != want {
+		t.Errorf("Parse() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,171 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package surt
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+// PrefixTrie is a set of SURT prefixes supporting fast matching of SURT strings against all prefixes at once,
+// e.g. for deciding whether a url is in the scope of a crawl seeded with many prefixes.
+//
+// The prefixes are stored in a radix tree, so matching is proportional to the length of the SURT string and not to
+// the number of prefixes. A PrefixTrie is safe for concurrent matching, but Add must not be called concurrently with
+// other methods.
+type PrefixTrie struct {
+	root trieNode
+	len  int
+}
+
+type trieNode struct {
+	label string
+	// children are sorted by the first byte of their label, which is unique among siblings.
+	children []*trieNode
+	terminal bool
+}
+
+// NewPrefixTrie returns a PrefixTrie containing prefixes.
+func NewPrefixTrie(prefixes ...string) *PrefixTrie {
+	t := &PrefixTrie{}
+	for _, p := range prefixes {
+		t.Add(p)
+	}
+	return t
+}
+
+// Prefix returns the SURT prefix for u as used for seeds in Heritrix: the SURT form is truncated after the last '/'
+// in the path. If that leaves only the root path, the closing parenthesis and the slash are removed as well, so the
+// prefix also matches subdomains, e.g. "http://example.com/" gives "http://(com,example," and
+// "http://example.com/a/b.html" gives "http://(com,example,)/a/".
+func Prefix(u *url.Url, opts ...Option) string {
+	s := String(u, opts...)
+	if i := strings.IndexAny(s, "?#"); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.LastIndexByte(s, '/'); i >= 0 && i > strings.IndexByte(s, ')') {
+		s = s[:i+1]
+	}
+	return strings.TrimSuffix(s, ")/")
+}
+
+// Add adds prefix to the trie. It returns false if prefix was already in the trie.
+func (t *PrefixTrie) Add(prefix string) bool {
+	n := &t.root
+	key := prefix
+	for {
+		if key == "" {
+			if n.terminal {
+				return false
+			}
+			n.terminal = true
+			t.len++
+			return true
+		}
+		i, c := n.child(key[0])
+		if c == nil {
+			n.insertChild(i, &trieNode{label: key, terminal: true})
+			t.len++
+			return true
+		}
+		l := commonPrefixLength(c.label, key)
+		if l < len(c.label) {
+			// Split the edge at the end of the common prefix
+			mid := &trieNode{label: c.label[:l], children: []*trieNode{c}}
+			c.label = c.label[l:]
+			n.children[i] = mid
+			c = mid
+		}
+		n = c
+		key = key[l:]
+	}
+}
+
+// Len returns the number of prefixes in the trie.
+func (t *PrefixTrie) Len() int {
+	return t.len
+}
+
+// HasPrefixOf returns true if one of the prefixes in the trie is a prefix of s.
+func (t *PrefixTrie) HasPrefixOf(s string) bool {
+	return t.match(s, true) >= 0
+}
+
+// LongestPrefixOf returns the longest prefix in the trie which is a prefix of s. The returned bool is false if no
+// prefix matches.
+func (t *PrefixTrie) LongestPrefixOf(s string) (string, bool) {
+	if n := t.match(s, false); n >= 0 {
+		return s[:n], true
+	}
+	return "", false
+}
+
+// MatchUrl returns true if one of the prefixes in the trie is a prefix of the SURT form of u. The SURT form is
+// created with opts, which should be the options used to create the prefixes.
+func (t *PrefixTrie) MatchUrl(u *url.Url, opts ...Option) bool {
+	return t.HasPrefixOf(String(u, opts...))
+}
+
+// match returns the length of the longest prefix of s in the trie, or -1 if there is none. If first is true, the
+// length of the first prefix found is returned.
+func (t *PrefixTrie) match(s string, first bool) int {
+	best := -1
+	n := &t.root
+	pos := 0
+	for {
+		if n.terminal {
+			best = pos
+			if first {
+				return best
+			}
+		}
+		if pos == len(s) {
+			return best
+		}
+		_, c := n.child(s[pos])
+		if c == nil || !strings.HasPrefix(s[pos:], c.label) {
+			return best
+		}
+		pos += len(c.label)
+		n = c
+	}
+}
+
+// child returns the child whose label starts with b, or the position to insert such a child and nil.
+func (n *trieNode) child(b byte) (int, *trieNode) {
+	i := sort.Search(len(n.children), func(i int) bool { return n.children[i].label[0] >= b })
+	if i < len(n.children) && n.children[i].label[0] == b {
+		return i, n.children[i]
+	}
+	return i, nil
+}
+
+func (n *trieNode) insertChild(i int, c *trieNode) {
+	n.children = append(n.children, nil)
+	copy(n.children[i+1:], n.children[i:])
+	n.children[i] = c
+}
+
+func commonPrefixLength(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package surt
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+func TestPrefixTrie(t *testing.T) {
+	trie := NewPrefixTrie(
+		"http://(com,example,",
+		"http://(com,example,www,)/a/",
+		"http://(org,example,)/",
+		"http://(org,example,)/b/c",
+		"https://(no,",
+	)
+	if got := trie.Len(); got != 5 {
+		t.Errorf("Len() = %v, want %v", got, 5)
+	}
+	if trie.Add("http://(org,example,)/") {
+		t.Errorf("Add() = true for existing prefix")
+	}
+
+	tests := []struct {
+		name        string
+		s           string
+		wantLongest string
+		wantOk      bool
+	}{
+		{"1", "http://(com,example,)/", "http://(com,example,", true},
+		{"2", "http://(com,example,www,)/a/b", "http://(com,example,www,)/a/", true},
+		{"3", "http://(com,example,www,)/b", "http://(com,example,", true},
+		{"4", "http://(com,examples,)/", "", false},
+		{"5", "http://(org,example,)/b/c/d", "http://(org,example,)/b/c", true},
+		{"6", "http://(org,example,)/b/", "http://(org,example,)/", true},
+		{"7", "http://(org,example,www,)/", "", false},
+		{"8", "https://(no,nb,)/", "https://(no,", true},
+		{"9", "http://(no,nb,)/", "", false},
+		{"10", "", "", false},
+		{"11", "http://(com,", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := trie.LongestPrefixOf(tt.s)
+			if got != tt.wantLongest || ok != tt.wantOk {
+				t.Errorf("LongestPrefixOf() = %v, %v, want %v, %v", got, ok, tt.wantLongest, tt.wantOk)
+			}
+			if got := trie.HasPrefixOf(tt.s); got != tt.wantOk {
+				t.Errorf("HasPrefixOf() = %v, want %v", got, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestPrefixTrie_Empty(t *testing.T) {
+	trie := NewPrefixTrie()
+	if trie.HasPrefixOf("http://(com,example,)/") {
+		t.Errorf("HasPrefixOf() = true for empty trie")
+	}
+	trie.Add("")
+	if !trie.HasPrefixOf("http://(com,example,)/") {
+		t.Errorf("HasPrefixOf() = false with empty prefix")
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"1", "http://example.com/", "http://(com,example,"},
+		{"2", "http://example.com", "http://(com,example,"},
+		{"3", "http://www.example.com/a/b.html?q#f", "http://(com,example,www,)/a/"},
+		{"4", "http://example.com/a/", "http://(com,example,)/a/"},
+		{"5", "http://example.com:8080/", "http://(com,example,:8080"},
+		{"6", "http://example.com/a?b/c", "http://(com,example,"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := Prefix(u); got != tt.want {
+				t.Errorf("Prefix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrefixTrie_MatchUrl(t *testing.T) {
+	var seeds []string
+	for _, s := range []string{"http://example.com/", "http://example.org/a/b.html"} {
+		u, _ := url.Parse(s)
+		seeds = append(seeds, Prefix(u))
+	}
+	trie := NewPrefixTrie(seeds...)
+	tests := []struct {
+		name string
+		url  string
+		want bool
+	}{
+		{"1", "http://www.example.com/x", true},
+		{"2", "http://EXAMPLE.com:80/", true},
+		{"3", "https://example.com/", false},
+		{"4", "http://example.org/a/c", true},
+		{"5", "http://example.org/b", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := url.Parse(tt.url)
+			if got := trie.MatchUrl(u); got != tt.want {
+				t.Errorf("MatchUrl() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkPrefixTrie_HasPrefixOf(b *testing.B) {
+	trie := NewPrefixTrie()
+	for i := 0; i < 100000; i++ {
+		trie.Add(fmt.Sprintf("http://(com,example%d,", i))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		trie.HasPrefixOf("http://(com,example99999,www,)/a/b/c.html")
+	}
+}
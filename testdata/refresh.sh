@@ -4,3 +4,5 @@ curl -O https://raw.githubusercontent.com/web-platform-tests/wpt/master/url/reso
 curl -O https://raw.githubusercontent.com/web-platform-tests/wpt/master/url/resources/urltestdata.json
 curl -O https://raw.githubusercontent.com/web-platform-tests/wpt/master/url/resources/toascii.json
 curl -O https://raw.githubusercontent.com/web-platform-tests/wpt/master/url/resources/IdnaTestV2.json
+curl -O https://raw.githubusercontent.com/web-platform-tests/wpt/master/url/resources/urltestdata-javascript-only.json
+curl -O https://raw.githubusercontent.com/web-platform-tests/wpt/master/url/resources/percent-encoding.json
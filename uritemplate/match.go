@@ -0,0 +1,200 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package uritemplate
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/nlnwa/whatwg-url/percent"
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+// Character classes of the text an expression can expand to. Values are percent-encoded, so the separators of the
+// operators only occur between values.
+const (
+	unreservedClass = `A-Za-z0-9\-._~%`
+	reservedClass   = unreservedClass + `:/?#\[\]@!$&'()*+,;=`
+)
+
+var expressionPatterns = map[byte]string{
+	0:   `[` + unreservedClass + `,=]*`,
+	'+': `[` + reservedClass + `]*?`,
+	'#': `(?:#[` + reservedClass + `]*?)?`,
+	'.': `(?:\.[A-Za-z0-9\-_~%,=]*)*`,
+	'/': `(?:/[` + unreservedClass + `,=]*)*`,
+	';': `(?:;[` + unreservedClass + `,=]*)*`,
+	'?': `(?:\?[` + unreservedClass + `,=&]*)?`,
+	'&': `(?:&[` + unreservedClass + `,=&]*)*`,
+}
+
+// regexp returns the regular expression matching the expansions of t. The expressions are captured by groups.
+func (t *Template) regexp() *regexp.Regexp {
+	t.reOnce.Do(func() {
+		var sb strings.Builder
+		sb.WriteString("^")
+		for _, p := range t.parts {
+			if p.expr == nil {
+				sb.WriteString(regexp.QuoteMeta(p.literal))
+			} else {
+				sb.WriteString("(" + expressionPatterns[p.expr.op.char] + ")")
+			}
+		}
+		sb.WriteString("$")
+		t.re = regexp.MustCompile(sb.String())
+	})
+	return t.re
+}
+
+// Match matches s against the template and returns the values of the variables. This is the reverse of Expand:
+// false is returned unless expanding the template with the returned values gives s.
+//
+// Strings are returned as string, lists as []string and associative arrays as []url.NameValuePair. Undefined
+// variables are not included. Matching is not always possible since an expansion can be ambiguous, e.g. when a
+// value expanded by the "." operator contains a dot.
+func (t *Template) Match(s string) (Values, bool) {
+	groups := t.regexp().FindStringSubmatch(s)
+	if groups == nil {
+		return nil, false
+	}
+	vars := Values{}
+	i := 1
+	for _, p := range t.parts {
+		if p.expr == nil {
+			continue
+		}
+		if !p.expr.match(groups[i], vars) {
+			return nil, false
+		}
+		i++
+	}
+	if expanded, err := t.Expand(vars); err != nil || expanded != s {
+		return nil, false
+	}
+	return vars, true
+}
+
+// MatchUrl matches the serialization of u against the template. See Match.
+func (t *Template) MatchUrl(u *url.Url) (Values, bool) {
+	return t.Match(u.Href(false))
+}
+
+// match extracts the values of the variables of e from text, the expansion of e.
+func (e *expression) match(text string, vars Values) bool {
+	op := e.op
+	if text == "" {
+		return true
+	}
+	text = strings.TrimPrefix(text, op.first)
+	items := strings.Split(text, op.sep)
+	if op.named {
+		return e.matchNamed(items, vars)
+	}
+	for i, vs := range e.varspecs {
+		if i >= len(items) {
+			break
+		}
+		switch {
+		case vs.explode:
+			setComposite(vars, vs.name, items[i:])
+			return true
+		case i == len(e.varspecs)-1 && len(items) > i+1:
+			// The last variable is an unexploded list, which is separated by commas like the variables
+			setComposite(vars, vs.name, items[i:])
+			return true
+		case op.char != '+' && op.char != '#' && strings.Contains(items[i], ","):
+			setComposite(vars, vs.name, strings.Split(items[i], ","))
+		default:
+			setString(vars, vs, items[i])
+		}
+	}
+	return true
+}
+
+// matchNamed extracts the values of the variables of e from the items of an expression with a named operator.
+func (e *expression) matchNamed(items []string, vars Values) bool {
+	var exploded *varspec
+	for i := range e.varspecs {
+		if e.varspecs[i].explode {
+			exploded = &e.varspecs[i]
+			break
+		}
+	}
+	var extra []string
+	for _, item := range items {
+		name, val, _ := strings.Cut(item, "=")
+		vs := e.varspec(name)
+		switch {
+		case vs == nil && exploded == nil:
+			return false
+		case vs == nil:
+			extra = append(extra, item)
+		case vs.explode:
+			l, _ := vars[name].([]string)
+			vars[name] = append(l, percent.Decode(val))
+		case strings.Contains(val, ","):
+			setComposite(vars, name, strings.Split(val, ","))
+		default:
+			setString(vars, *vs, val)
+		}
+	}
+	if extra != nil {
+		setComposite(vars, exploded.name, extra)
+	}
+	return true
+}
+
+// varspec returns the first varspec of e with the given name, or nil if there is none.
+func (e *expression) varspec(name string) *varspec {
+	for i := range e.varspecs {
+		if e.varspecs[i].name == name {
+			return &e.varspecs[i]
+		}
+	}
+	return nil
+}
+
+// setString sets the value of vs to the decoded value. A value matched by a varspec with a prefix modifier does
+// not replace a value set by another varspec, since it might be truncated.
+func setString(vars Values, vs varspec, encoded string) {
+	if _, ok := vars[vs.name]; ok && vs.prefix > 0 {
+		return
+	}
+	vars[vs.name] = percent.Decode(encoded)
+}
+
+// setComposite sets name to a list, or to an associative array if all items are pairs separated by '='.
+func setComposite(vars Values, name string, items []string) {
+	pairs := make([]url.NameValuePair, 0, len(items))
+	for _, item := range items {
+		n, v, found := strings.Cut(item, "=")
+		if !found {
+			pairs = nil
+			break
+		}
+		pairs = append(pairs, url.NameValuePair{Name: percent.Decode(n), Value: percent.Decode(v)})
+	}
+	if pairs != nil {
+		vars[name] = pairs
+		return
+	}
+	list := make([]string, len(items))
+	for i, item := range items {
+		list[i] = percent.Decode(item)
+	}
+	vars[name] = list
+}
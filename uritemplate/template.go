@@ -0,0 +1,465 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package uritemplate implements URI Templates as defined by [RFC 6570], up to and including level 4, and matching
+// of urls against templates.
+//
+// Variables are expanded with the percent-encoding of the RFC, which only leaves unreserved characters (and reserved
+// characters for the "+" and "#" operators) unencoded. Since these characters are never changed by the WHATWG URL
+// parser, an expanded template parsed with the url package serializes back to the same string, which is not the case
+// for all template libraries.
+//
+//	t, _ := uritemplate.Parse("https://example.com/search{?q,lang}")
+//	u, _ := t.ExpandUrl(nil, uritemplate.Values{"q": "cat & dog", "lang": "en"})
+//	fmt.Println(u) // https://example.com/search?q=cat%20%26%20dog&lang=en
+//
+// [RFC 6570]: https://www.rfc-editor.org/rfc/rfc6570
+package uritemplate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/nlnwa/whatwg-url/percent"
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+// Values holds the values of the variables of a template. A value is one of:
+//   - a string, or a bool, integer, float or fmt.Stringer which is formatted with fmt.Sprint
+//   - a []string, which is a list
+//   - a []url.NameValuePair or map[string]string, which is an associative array. The pairs of a map are expanded in
+//     the order of their keys.
+//
+// Variables which are missing or nil, and lists and associative arrays which are empty, are undefined.
+type Values map[string]interface{}
+
+// Error is returned by Parse when the template is malformed.
+type Error struct {
+	// Template is the template which was parsed.
+	Template string
+	// Offset is the byte offset into Template where the error was found.
+	Offset int
+	// Reason describes the error.
+	Reason string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("uritemplate: %s at offset %d in %q", e.Reason, e.Offset, e.Template)
+}
+
+// Template is a parsed URI Template.
+//
+// A Template is safe for concurrent use by multiple goroutines.
+type Template struct {
+	raw   string
+	parts []part
+	// re matches expansions of the template. It is compiled by the first call to Match.
+	reOnce sync.Once
+	re     *regexp.Regexp
+}
+
+// part is a literal or an expression of a template.
+type part struct {
+	literal string // encoded literal, if expr is nil
+	expr    *expression
+}
+
+type expression struct {
+	op       *operator
+	varspecs []varspec
+}
+
+type varspec struct {
+	name    string
+	prefix  int // the maximum length of the value, or 0 if there is no prefix modifier
+	explode bool
+}
+
+// operator holds the expansion rules of an operator, as listed in appendix A of the RFC.
+type operator struct {
+	char     byte
+	first    string
+	sep      string
+	named    bool
+	ifemp    string
+	reserved bool
+}
+
+var operators = map[byte]*operator{
+	0:   {first: "", sep: ","},
+	'+': {char: '+', first: "", sep: ",", reserved: true},
+	'#': {char: '#', first: "#", sep: ",", reserved: true},
+	'.': {char: '.', first: ".", sep: "."},
+	'/': {char: '/', first: "/", sep: "/"},
+	';': {char: ';', first: ";", sep: ";", named: true},
+	'?': {char: '?', first: "?", sep: "&", named: true, ifemp: "="},
+	'&': {char: '&', first: "&", sep: "&", named: true, ifemp: "="},
+}
+
+// maxPrefix is the largest prefix modifier allowed by the RFC.
+const maxPrefix = 9999
+
+// Parse parses a URI Template.
+func Parse(template string) (*Template, error) {
+	t := &Template{raw: template}
+	var literal strings.Builder
+	for i := 0; i < len(template); i++ {
+		switch c := template[i]; c {
+		case '{':
+			end := strings.IndexAny(template[i+1:], "{}")
+			if end < 0 || template[i+1+end] == '{' {
+				return nil, &Error{Template: template, Offset: i, Reason: "unclosed expression"}
+			}
+			expr, err := parseExpression(template, i+1, template[i+1:i+1+end])
+			if err != nil {
+				return nil, err
+			}
+			if literal.Len() > 0 {
+				t.parts = append(t.parts, part{literal: literal.String()})
+				literal.Reset()
+			}
+			t.parts = append(t.parts, part{expr: expr})
+			i += end + 1
+		case '}':
+			return nil, &Error{Template: template, Offset: i, Reason: "unmatched '}'"}
+		default:
+			// Literal characters not allowed in a url are percent-encoded, keeping percent-encoded triplets
+			if c == '%' && isPercentEncoded(template, i) {
+				literal.WriteString(template[i : i+3])
+				i += 2
+			} else if c < utf8.RuneSelf && (isUnreserved(c) || isReserved(c)) {
+				literal.WriteByte(c)
+			} else {
+				literal.WriteString(percent.Encode(template[i:i+1], nil))
+			}
+		}
+	}
+	if literal.Len() > 0 {
+		t.parts = append(t.parts, part{literal: literal.String()})
+	}
+	return t, nil
+}
+
+// MustParse is like Parse, but panics if the template is malformed.
+func MustParse(template string) *Template {
+	t, err := Parse(template)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// parseExpression parses the expression body found at offset in template.
+func parseExpression(template string, offset int, body string) (*expression, error) {
+	if body == "" {
+		return nil, &Error{Template: template, Offset: offset, Reason: "empty expression"}
+	}
+	op := operators[0]
+	if o, ok := operators[body[0]]; ok {
+		op = o
+		body = body[1:]
+		offset++
+	} else if strings.IndexByte("=,!@|", body[0]) >= 0 {
+		return nil, &Error{Template: template, Offset: offset, Reason: fmt.Sprintf("reserved operator '%c'", body[0])}
+	}
+	expr := &expression{op: op}
+	for _, spec := range strings.Split(body, ",") {
+		vs := varspec{name: spec}
+		if strings.HasSuffix(spec, "*") {
+			vs.name = spec[:len(spec)-1]
+			vs.explode = true
+		} else if name, prefix, found := strings.Cut(spec, ":"); found {
+			vs.name = name
+			if !isPrefix(prefix) {
+				return nil, &Error{Template: template, Offset: offset + len(name) + 1, Reason: "invalid prefix modifier"}
+			}
+			for _, c := range prefix {
+				vs.prefix = vs.prefix*10 + int(c-'0')
+			}
+		}
+		if !isVarname(vs.name) {
+			return nil, &Error{Template: template, Offset: offset, Reason: fmt.Sprintf("invalid variable name %q", vs.name)}
+		}
+		expr.varspecs = append(expr.varspecs, vs)
+		offset += len(spec) + 1
+	}
+	return expr, nil
+}
+
+// isPrefix returns true if s is a number from 1 to maxPrefix without leading zeros.
+func isPrefix(s string) bool {
+	if s == "" || len(s) > 4 || s[0] == '0' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isVarname returns true if s is a variable name: letters, digits, '_' and percent-encoded triplets, with single
+// dots between them.
+func isVarname(s string) bool {
+	if s == "" || s[0] == '.' || s[len(s)-1] == '.' || strings.Contains(s, "..") {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '_', c == '.':
+		case c == '%' && isPercentEncoded(s, i):
+			i += 2
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the template as it was given to Parse.
+func (t *Template) String() string {
+	return t.raw
+}
+
+// Varnames returns the names of the variables of the template in the order they first appear.
+func (t *Template) Varnames() []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, p := range t.parts {
+		if p.expr == nil {
+			continue
+		}
+		for _, vs := range p.expr.varspecs {
+			if !seen[vs.name] {
+				seen[vs.name] = true
+				names = append(names, vs.name)
+			}
+		}
+	}
+	return names
+}
+
+// Expand expands the template with the values in vars.
+//
+// An error is returned if a value has an unsupported type or if a prefix modifier is used with a list or an
+// associative array.
+func (t *Template) Expand(vars Values) (string, error) {
+	var sb strings.Builder
+	for _, p := range t.parts {
+		if p.expr == nil {
+			sb.WriteString(p.literal)
+			continue
+		}
+		if err := p.expr.expand(&sb, vars); err != nil {
+			return "", err
+		}
+	}
+	return sb.String(), nil
+}
+
+// ExpandUrl expands the template with the values in vars and parses the result with p. If p is nil, url.Parse is used.
+func (t *Template) ExpandUrl(p url.Parser, vars Values) (*url.Url, error) {
+	s, err := t.Expand(vars)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return url.Parse(s)
+	}
+	return p.Parse(s)
+}
+
+// expand writes the expansion of e to sb, following the algorithm in appendix A of the RFC.
+func (e *expression) expand(sb *strings.Builder, vars Values) error {
+	op := e.op
+	first := true
+	for _, vs := range e.varspecs {
+		v, err := valueOf(vars[vs.name])
+		if err != nil {
+			return fmt.Errorf("uritemplate: variable %q: %w", vs.name, err)
+		}
+		if !v.defined() {
+			continue
+		}
+		if vs.prefix > 0 && (v.list != nil || v.pairs != nil) {
+			return fmt.Errorf("uritemplate: variable %q: prefix modifier used with a composite value", vs.name)
+		}
+		if first {
+			sb.WriteString(op.first)
+			first = false
+		} else {
+			sb.WriteString(op.sep)
+		}
+
+		switch {
+		case v.list == nil && v.pairs == nil:
+			s := *v.str
+			if op.named {
+				sb.WriteString(vs.name)
+				if s == "" {
+					sb.WriteString(op.ifemp)
+					continue
+				}
+				sb.WriteByte('=')
+			}
+			if vs.prefix > 0 {
+				s = truncate(s, vs.prefix)
+			}
+			writeEncoded(sb, s, op.reserved)
+		case !vs.explode:
+			if op.named {
+				sb.WriteString(vs.name)
+				sb.WriteByte('=')
+			}
+			for i, s := range v.items() {
+				if i > 0 {
+					sb.WriteByte(',')
+				}
+				writeEncoded(sb, s, op.reserved)
+			}
+		case v.list != nil:
+			for i, s := range v.list {
+				if i > 0 {
+					sb.WriteString(op.sep)
+				}
+				if op.named {
+					sb.WriteString(vs.name)
+					if s == "" {
+						sb.WriteString(op.ifemp)
+						continue
+					}
+					sb.WriteByte('=')
+				}
+				writeEncoded(sb, s, op.reserved)
+			}
+		default:
+			for i, p := range v.pairs {
+				if i > 0 {
+					sb.WriteString(op.sep)
+				}
+				writeEncoded(sb, p.Name, op.reserved)
+				if op.named && p.Value == "" {
+					sb.WriteString(op.ifemp)
+					continue
+				}
+				sb.WriteByte('=')
+				writeEncoded(sb, p.Value, op.reserved)
+			}
+		}
+	}
+	return nil
+}
+
+// value is the value of a variable: a string, a list or an associative array.
+type value struct {
+	str   *string
+	list  []string
+	pairs []url.NameValuePair
+}
+
+func (v value) defined() bool {
+	return v.str != nil || len(v.list) > 0 || len(v.pairs) > 0
+}
+
+// items returns the strings of a list, or the names and values of an associative array.
+func (v value) items() []string {
+	if v.list != nil {
+		return v.list
+	}
+	items := make([]string, 0, 2*len(v.pairs))
+	for _, p := range v.pairs {
+		items = append(items, p.Name, p.Value)
+	}
+	return items
+}
+
+func valueOf(i interface{}) (value, error) {
+	var s string
+	switch v := i.(type) {
+	case nil:
+		return value{}, nil
+	case string:
+		s = v
+	case []string:
+		return value{list: v}, nil
+	case []url.NameValuePair:
+		return value{pairs: v}, nil
+	case map[string]string:
+		pairs := make([]url.NameValuePair, 0, len(v))
+		for name, val := range v {
+			pairs = append(pairs, url.NameValuePair{Name: name, Value: val})
+		}
+		sort.Slice(pairs, func(i, j int) bool { return pairs[i].Name < pairs[j].Name })
+		return value{pairs: pairs}, nil
+	case fmt.Stringer:
+		s = v.String()
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		s = fmt.Sprint(v)
+	default:
+		return value{}, fmt.Errorf("unsupported type %T", i)
+	}
+	return value{str: &s}, nil
+}
+
+// truncate returns the first n code points of s.
+func truncate(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
+
+// writeEncoded writes s to sb, percent-encoding all bytes except unreserved characters. If reserved is true, reserved
+// characters and percent-encoded triplets are not encoded either.
+func writeEncoded(sb *strings.Builder, s string, reserved bool) {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c < utf8.RuneSelf && isUnreserved(c):
+			sb.WriteByte(c)
+		case reserved && c < utf8.RuneSelf && isReserved(c):
+			sb.WriteByte(c)
+		case reserved && c == '%' && isPercentEncoded(s, i):
+			sb.WriteString(s[i : i+3])
+			i += 2
+		default:
+			sb.WriteString(percent.Encode(s[i:i+1], nil))
+		}
+	}
+}
+
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isReserved(c byte) bool {
+	return strings.IndexByte(":/?#[]@!$&'()*+,;=", c) >= 0
+}
+
+// isPercentEncoded returns true if s has a percent-encoded triplet at index i.
+func isPercentEncoded(s string, i int) bool {
+	return i+2 < len(s) && s[i] == '%' && percent.IsHex(s[i+1]) && percent.IsHex(s[i+2])
+}
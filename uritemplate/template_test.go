@@ -0,0 +1,214 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package uritemplate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+// rfcValues are the variables used by the examples in section 3.2 of RFC 6570.
+var rfcValues = Values{
+	"count":      []string{"one", "two", "three"},
+	"dom":        []string{"example", "com"},
+	"dub":        "me/too",
+	"hello":      "Hello World!",
+	"half":       "50%",
+	"var":        "value",
+	"who":        "fred",
+	"base":       "http://example.com/home/",
+	"path":       "/foo/bar",
+	"list":       []string{"red", "green", "blue"},
+	"keys":       []url.NameValuePair{{Name: "semi", Value: ";"}, {Name: "dot", Value: "."}, {Name: "comma", Value: ","}},
+	"v":          6,
+	"x":          "1024",
+	"y":          "768",
+	"empty":      "",
+	"empty_keys": map[string]string{},
+	"undef":      nil,
+}
+
+func TestTemplate_Expand(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"1", "{count}", "one,two,three"},
+		{"2", "{count*}", "one,two,three"},
+		{"3", "{/count}", "/one,two,three"},
+		{"4", "{/count*}", "/one/two/three"},
+		{"5", "{;count}", ";count=one,two,three"},
+		{"6", "{;count*}", ";count=one;count=two;count=three"},
+		{"7", "{?count}", "?count=one,two,three"},
+		{"8", "{?count*}", "?count=one&count=two&count=three"},
+		{"9", "{&count*}", "&count=one&count=two&count=three"},
+		{"10", "{hello}", "Hello%20World%21"},
+		{"11", "{half}", "50%25"},
+		{"12", "O{empty}X", "OX"},
+		{"13", "O{undef}X", "OX"},
+		{"14", "{x,hello,y}", "1024,Hello%20World%21,768"},
+		{"15", "?{x,empty}", "?1024,"},
+		{"16", "?{undef,y}", "?768"},
+		{"17", "{var:3}", "val"},
+		{"18", "{var:30}", "value"},
+		{"19", "{keys}", "semi,%3B,dot,.,comma,%2C"},
+		{"20", "{keys*}", "semi=%3B,dot=.,comma=%2C"},
+		{"21", "{+hello}", "Hello%20World!"},
+		{"22", "{+half}", "50%25"},
+		{"23", "{base}index", "http%3A%2F%2Fexample.com%2Fhome%2Findex"},
+		{"24", "{+base}index", "http://example.com/home/index"},
+		{"25", "up{+path}{var}/here", "up/foo/barvalue/here"},
+		{"26", "{+path:6}/here", "/foo/b/here"},
+		{"27", "{+keys*}", "semi=;,dot=.,comma=,"},
+		{"28", "foo{#empty}", "foo#"},
+		{"29", "foo{#undef}", "foo"},
+		{"30", "{#path,x}/here", "#/foo/bar,1024/here"},
+		{"31", "{#keys}", "#semi,;,dot,.,comma,,"},
+		{"32", "www{.dom*}", "www.example.com"},
+		{"33", "X{.empty}", "X."},
+		{"34", "X{.list*}", "X.red.green.blue"},
+		{"35", "X{.keys*}", "X.semi=%3B.dot=..comma=%2C"},
+		{"36", "X{.empty_keys*}", "X"},
+		{"37", "{/who,dub}", "/fred/me%2Ftoo"},
+		{"38", "{/var,empty}", "/value/"},
+		{"39", "{/var:1,var}", "/v/value"},
+		{"40", "{/list*,path:4}", "/red/green/blue/%2Ffoo"},
+		{"41", "{;v,empty,who}", ";v=6;empty;who=fred"},
+		{"42", "{;hello:5}", ";hello=Hello"},
+		{"43", "{;keys*}", ";semi=%3B;dot=.;comma=%2C"},
+		{"44", "{?x,y,empty}", "?x=1024&y=768&empty="},
+		{"45", "{?keys}", "?keys=semi,%3B,dot,.,comma,%2C"},
+		{"46", "?fixed=yes{&x}", "?fixed=yes&x=1024"},
+		{"47", "{&keys*}", "&semi=%3B&dot=.&comma=%2C"},
+		{"48", "http://example.com/a b/{var}|%41", "http://example.com/a%20b/value%7C%41"},
+		{"49", "{who}/{hello:3}", "fred/Hel"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := Parse(tt.template)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.template, err)
+			}
+			got, err := tmpl.Expand(rfcValues)
+			if err != nil {
+				t.Fatalf("Expand() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		wantOffset int
+	}{
+		{"1", "{var", 0},
+		{"2", "a{b{c}}", 1},
+		{"3", "a}", 1},
+		{"4", "{}", 1},
+		{"5", "{=var}", 1},
+		{"6", "{var:0}", 5},
+		{"7", "{var:10000}", 5},
+		{"8", "{a,b c}", 3},
+		{"9", "{.var.}", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.template)
+			e, ok := err.(*Error)
+			if !ok {
+				t.Fatalf("Parse(%q) error = %v, want *Error", tt.template, err)
+			}
+			if e.Offset != tt.wantOffset {
+				t.Errorf("Parse(%q) error offset = %v, want %v (%v)", tt.template, e.Offset, tt.wantOffset, e)
+			}
+		})
+	}
+
+	if got, want := MustParse("{a}{b.c}{%41}{a:2}").Varnames(), []string{"a", "b.c", "%41"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Varnames() = %v, want %v", got, want)
+	}
+	if _, err := MustParse("{list:2}").Expand(rfcValues); err == nil {
+		t.Errorf("Expand() error = nil, want error for prefix modifier on list")
+	}
+	if _, err := MustParse("{x}").Expand(Values{"x": struct{}{}}); err == nil {
+		t.Errorf("Expand() error = nil, want error for unsupported type")
+	}
+}
+
+func TestTemplate_ExpandUrl(t *testing.T) {
+	tmpl := MustParse("https://example.com/search{/path*}{?q,lang}{#frag}")
+	u, err := tmpl.ExpandUrl(nil, Values{"path": []string{"a b", "ü"}, "q": "cat & dog", "lang": "en", "frag": "top"})
+	if err != nil {
+		t.Fatalf("ExpandUrl() error = %v", err)
+	}
+	want := "https://example.com/search/a%20b/%C3%BC?q=cat%20%26%20dog&lang=en#top"
+	if got := u.Href(false); got != want {
+		t.Errorf("ExpandUrl() = %v, want %v", got, want)
+	}
+
+	got, ok := tmpl.MatchUrl(u)
+	if !ok {
+		t.Fatalf("MatchUrl(%v) ok = false, want true", u)
+	}
+	wantVars := Values{"path": []string{"a b", "ü"}, "q": "cat & dog", "lang": "en", "frag": "top"}
+	if !reflect.DeepEqual(got, wantVars) {
+		t.Errorf("MatchUrl(%v) = %v, want %v", u, got, wantVars)
+	}
+}
+
+func TestTemplate_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		input    string
+		want     Values
+	}{
+		{"1", "http://example.com/{who}/{var}", "http://example.com/fred/value", Values{"who": "fred", "var": "value"}},
+		{"2", "{x,hello,y}", "1024,Hello%20World%21,768", Values{"x": "1024", "hello": "Hello World!", "y": "768"}},
+		{"3", "{/list*}{?q}", "/red/green/blue?q=x", Values{"list": []string{"red", "green", "blue"}, "q": "x"}},
+		{"4", "{?x,y,empty}", "?x=1024&y=768&empty=", Values{"x": "1024", "y": "768", "empty": ""}},
+		{"5", "{?keys*}", "?semi=%3B&dot=.&comma=%2C", Values{"keys": []url.NameValuePair{{Name: "semi", Value: ";"},
+			{Name: "dot", Value: "."}, {Name: "comma", Value: ","}}}},
+		{"6", "{;list}", ";list=red,green,blue", Values{"list": []string{"red", "green", "blue"}}},
+		{"7", "{+base}index", "http://example.com/home/index", Values{"base": "http://example.com/home/"}},
+		{"8", "{/var:1,var}", "/v/value", Values{"var": "value"}},
+		{"9", "X{.var}{#frag}", "X.value#a/b", Values{"var": "value", "frag": "a/b"}},
+		{"10", "/a{?q}", "/a", Values{}},
+		{"11", "/a/{id}", "/b/1", nil},
+		{"12", "{?q}", "?q=1&r=2", nil},
+		{"13", "{/var:1,var}", "/x/value", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := MustParse(tt.template).Match(tt.input)
+			if ok != (tt.want != nil) {
+				t.Fatalf("Match(%q) ok = %v, want %v", tt.input, ok, tt.want != nil)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Match(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"strings"
+)
+
+// BlobEntry is the origin and identifier of a blob: url as created by URL.createObjectURL in the [File API], e.g.
+// "blob:https://example.com/550e8400-e29b-41d4-a716-446655440000".
+//
+// Unlike a browser, this package has no blob URL store, so the entry only describes the url. It does not mean
+// that the blob exists.
+//
+// [File API]: https://w3c.github.io/FileAPI/#unicodeBlobURL
+type BlobEntry struct {
+	// Origin is the origin of the document which created the blob url.
+	Origin *Origin
+	// ID is the identifier of the blob, normally a UUID.
+	ID string
+}
+
+// IsBlob returns true if the url has the blob scheme.
+func (u *Url) IsBlob() bool {
+	return u.scheme == "blob"
+}
+
+// BlobOrigin returns the origin of a blob: url, which is the origin of the url in its path if that url is http or
+// https, and a new opaque origin otherwise. Nil is returned if the url is not a blob: url.
+//
+// See: https://url.spec.whatwg.org/#concept-url-origin
+func (u *Url) BlobOrigin() *Origin {
+	if !u.IsBlob() {
+		return nil
+	}
+	return u.blobOrigin()
+}
+
+func (u *Url) blobOrigin() *Origin {
+	pathURL, err := u.parser.Parse(u.Pathname())
+	if err != nil {
+		return NewOpaqueOrigin()
+	}
+	if pathURL.scheme == "http" || pathURL.scheme == "https" {
+		return pathURL.ParsedOrigin()
+	}
+	return NewOpaqueOrigin()
+}
+
+// BlobEntry splits the path of a blob: url into the serialized origin and the identifier. The returned bool is false
+// if the url is not a blob: url or if its path is not a serialized origin followed by '/' and a non-empty identifier.
+// A path starting with "null/" gives an opaque origin.
+func (u *Url) BlobEntry() (*BlobEntry, bool) {
+	if !u.IsBlob() {
+		return nil, false
+	}
+	path := u.Pathname()
+	i := strings.LastIndexByte(path, '/')
+	if i < 0 || i == len(path)-1 {
+		return nil, false
+	}
+	origin := u.blobOrigin()
+	if path[:i] != origin.Serialize() {
+		return nil, false
+	}
+	return &BlobEntry{Origin: origin, ID: path[i+1:]}, true
+}
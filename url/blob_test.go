@@ -0,0 +1,62 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"testing"
+)
+
+func TestUrl_BlobEntry(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		wantOrigin string
+		wantID     string
+		wantOk     bool
+	}{
+		{"1", "blob:https://example.com/550e8400-e29b-41d4-a716-446655440000", "https://example.com", "550e8400-e29b-41d4-a716-446655440000", true},
+		{"2", "blob:http://example.org:88/id#frag", "http://example.org:88", "id", true},
+		{"3", "blob:null/id", "null", "id", true},
+		{"4", "blob:https://example.com:443/id", "https://example.com", "", false},
+		{"5", "blob:https://example.com/a/id", "https://example.com", "", false},
+		{"6", "blob:https://example.com/", "https://example.com", "", false},
+		{"7", "blob:file:///tmp/id", "null", "", false},
+		{"8", "blob:id", "null", "", false},
+		{"9", "https://example.com/id", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := Parse(tt.url)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if o := u.BlobOrigin(); (o == nil) != (tt.wantOrigin == "") || o != nil && o.Serialize() != tt.wantOrigin {
+				t.Errorf("BlobOrigin() = %v, want %v", o, tt.wantOrigin)
+			}
+			got, ok := u.BlobEntry()
+			if ok != tt.wantOk {
+				t.Fatalf("BlobEntry() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+			if got.Origin.Serialize() != tt.wantOrigin || got.ID != tt.wantID {
+				t.Errorf("BlobEntry() = %v, %v, want %v, %v", got.Origin, got.ID, tt.wantOrigin, tt.wantID)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// Result is the result of parsing one input with ParseAll. Url is nil if Err is set.
+type Result struct {
+	Url *Url
+	Err error
+}
+
+// BulkOption configures ParseAll.
+type BulkOption interface {
+	apply(*bulkOptions)
+}
+
+type bulkOptions struct {
+	workers int
+	parser  Parser
+}
+
+type funcBulkOption struct {
+	f func(*bulkOptions)
+}
+
+func (fbo *funcBulkOption) apply(o *bulkOptions) {
+	fbo.f(o)
+}
+
+func newFuncBulkOption(f func(*bulkOptions)) *funcBulkOption {
+	return &funcBulkOption{
+		f: f,
+	}
+}
+
+// WithBulkWorkers sets the number of goroutines used by ParseAll. If workers is less than one, which is the default,
+// runtime.GOMAXPROCS(0) workers are used.
+//
+// This API is EXPERIMENTAL.
+func WithBulkWorkers(workers int) BulkOption {
+	return newFuncBulkOption(func(o *bulkOptions) {
+		o.workers = workers
+	})
+}
+
+// WithBulkParser sets the parser used by ParseAll. The default is the parser used by Parse.
+//
+// This API is EXPERIMENTAL.
+func WithBulkParser(p Parser) BulkOption {
+	return newFuncBulkOption(func(o *bulkOptions) {
+		o.parser = p
+	})
+}
+
+// ParseAll parses inputs concurrently. The result for inputs[i] is found at index i of the returned slice. Errors for
+// individual inputs are reported in the results and do not stop the processing. The returned error is only set if
+// ctx is done before all inputs are parsed, in which case the results for the unparsed inputs have Err set to the
+// context's error. The context is also passed on to the function set by WithResolveHostFunc.
+//
+// When the parser is created by NewParser, the Urls are allocated together in one slice instead of one by one, so
+// a Url kept from the results keeps the memory of all of them alive. Copy the urls which are kept for long if the
+// rest are dropped.
+//
+// This API is EXPERIMENTAL.
+func ParseAll(ctx context.Context, inputs []string, opts ...BulkOption) ([]Result, error) {
+	o := bulkOptions{parser: defaultParser}
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+	workers := o.workers
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(inputs) {
+		workers = len(inputs)
+	}
+
+	parse := func(i int) Result {
+		u, err := o.parser.ParseContext(ctx, inputs[i])
+		if err != nil {
+			return Result{Err: err}
+		}
+		return Result{Url: u}
+	}
+	if p, ok := o.parser.(*parser); ok {
+		urls := make([]Url, len(inputs))
+		parse = func(i int) Result {
+			u := &urls[i]
+			if err := p.parseInto(ctx, inputs[i], u); err != nil {
+				return Result{Err: err}
+			}
+			return Result{Url: u}
+		}
+	}
+
+	results := make([]Result, len(inputs))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = parse(i)
+			}
+		}()
+	}
+
+	var err error
+	i := 0
+	for ; i < len(inputs); i++ {
+		select {
+		case indexes <- i:
+			continue
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		break
+	}
+	close(indexes)
+	wg.Wait()
+
+	for ; i < len(inputs); i++ {
+		results[i] = Result{Err: err}
+	}
+	return results, err
+}
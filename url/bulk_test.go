@@ -0,0 +1,94 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestParseAll(t *testing.T) {
+	var inputs []string
+	for i := 0; i < 100; i++ {
+		inputs = append(inputs, fmt.Sprintf("HTTP://example.com/a/../%d", i))
+	}
+	inputs = append(inputs, "http://[::1")
+
+	tests := []struct {
+		name string
+		opts []BulkOption
+	}{
+		{"1", nil},
+		{"2", []BulkOption{WithBulkWorkers(4)}},
+		{"3", []BulkOption{WithBulkParser(NewParser(WithCollapseConsecutiveSlashes()))}},
+		{"4", []BulkOption{WithBulkParser(wrappedParser{NewParser()}), WithBulkWorkers(1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := ParseAll(context.Background(), inputs, tt.opts...)
+			if err != nil {
+				t.Fatalf("ParseAll() error = %v", err)
+			}
+			if len(results) != len(inputs) {
+				t.Fatalf("ParseAll() returned %d results, want %d", len(results), len(inputs))
+			}
+			for i := 0; i < 100; i++ {
+				want := fmt.Sprintf("http://example.com/%d", i)
+				if results[i].Err != nil || results[i].Url.String() != want {
+					t.Errorf("ParseAll() result %d = %v, %v, want %v", i, results[i].Url, results[i].Err, want)
+				}
+			}
+			if results[100].Err == nil || results[100].Url != nil {
+				t.Errorf("ParseAll() result 100 = %v, %v, want error", results[100].Url, results[100].Err)
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := ParseAll(ctx, inputs)
+	if err != context.Canceled {
+		t.Errorf("ParseAll() error = %v, want %v", err, context.Canceled)
+	}
+	if len(results) != len(inputs) {
+		t.Errorf("ParseAll() returned %d results, want %d", len(results), len(inputs))
+	}
+
+	if results, err := ParseAll(context.Background(), nil); err != nil || len(results) != 0 {
+		t.Errorf("ParseAll(nil) = %v, %v, want no results", results, err)
+	}
+}
+
+// wrappedParser hides the concrete parser type to test ParseAll with other Parser implementations.
+type wrappedParser struct {
+	Parser
+}
+
+func BenchmarkParseAll(b *testing.B) {
+	inputs := make([]string, 10000)
+	for i := range inputs {
+		inputs[i] = fmt.Sprintf("http://www.example.com/path/%d?q=%d", i, i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseAll(context.Background(), inputs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -20,62 +20,16 @@ import (
 	"unicode"
 
 	"github.com/bits-and-blooms/bitset"
+
+	"github.com/nlnwa/whatwg-url/percent"
 )
 
-type PercentEncodeSet struct {
-	bs       *bitset.BitSet
-	allBelow int32
-}
+// PercentEncodeSet is a set of code points to percent-encode. See percent.Set.
+type PercentEncodeSet = percent.Set
 
+// NewPercentEncodeSet returns a set with all code points below allBelow and the given bytes.
 func NewPercentEncodeSet(allBelow int32, bytes ...uint) *PercentEncodeSet {
-	p := &PercentEncodeSet{allBelow: allBelow, bs: bitset.New(0x7f)}
-	for _, b := range bytes {
-		p.bs.Set(b)
-	}
-	return p
-}
-
-func (p *PercentEncodeSet) Set(bytes ...uint) *PercentEncodeSet {
-	r := &PercentEncodeSet{
-		allBelow: p.allBelow,
-		bs:       p.bs.Clone(),
-	}
-	for _, b := range bytes {
-		r.bs.Set(b)
-	}
-	return r
-}
-
-func (p *PercentEncodeSet) Clear(bytes ...uint) *PercentEncodeSet {
-	r := &PercentEncodeSet{
-		allBelow: p.allBelow,
-		bs:       p.bs.Clone(),
-	}
-	for _, b := range bytes {
-		r.bs.Clear(b)
-	}
-	return r
-}
-
-func (p *PercentEncodeSet) RuneShouldBeEncoded(r rune) bool {
-	if r < p.allBelow || r > 0x007E || p.bs.Test(uint(r)) {
-		return true
-	}
-	return false
-}
-
-func (p *PercentEncodeSet) ByteShouldBeEncoded(b byte) bool {
-	if int32(b) < p.allBelow || b > 0x007E || p.bs.Test(uint(b)) {
-		return true
-	}
-	return false
-}
-
-func (p *PercentEncodeSet) RuneNotInSet(r rune) bool {
-	if r < p.allBelow || p.bs.Test(uint(r)) {
-		return false
-	}
-	return true
+	return percent.NewSet(allBelow, bytes...)
 }
 
 func isURLCodePoint(r rune) bool {
@@ -108,19 +62,31 @@ var ForbiddenHostCodePoint = bitset.New(0x7c).Set(0x00).Set(0x09).Set(0x0a).Set(
 	Set(0x23).Set(0x2f).Set(0x3a).Set(0x3c).Set(0x3e).Set(0x3f).Set(0x40).Set(0x5b).
 	Set(0x5c).Set(0x5d).Set(0x5e).Set(0x7c)
 var ForbiddenDomainCodePoint = ForbiddenHostCodePoint.Clone().Set(0x25).Set(0x7f)
+
+// schemeCodePoint contains the code points allowed in a scheme after the first one: ASCII alphanumeric, '+', '-' and '.'
+var schemeCodePoint = bitset.New(0x7a)
+
 var someURLCodePoints = bitset.New(0x7e).Set(0x24).Set(0x26).Set(0x27).Set(0x28).Set(0x29).
 	Set(0x2a).Set(0x2b).Set(0x2c).Set(0x2d).Set(0x2e).Set(0x2f).Set(0x3a).Set(0x3b).Set(0x3d).
 	Set(0x3f).Set(0x40).Set(0x5f).Set(0x7e)
 
-var C0PercentEncodeSet = NewPercentEncodeSet(0x20)
+var C0PercentEncodeSet = percent.C0ControlSet
 var C0OrSpacePercentEncodeSet = NewPercentEncodeSet(0x21)
-var FragmentPercentEncodeSet = C0OrSpacePercentEncodeSet.Set(0x22, 0x3c, 0x3e, 0x60)
-var QueryPercentEncodeSet = C0OrSpacePercentEncodeSet.Set(0x22, 0x23, 0x3C, 0x3E)
-var SpecialQueryPercentEncodeSet = QueryPercentEncodeSet.Set(0x27)
-var PathPercentEncodeSet = QueryPercentEncodeSet.Set(0x3f, 0x60, 0x7b, 0x7d)
-var UserInfoPercentEncodeSet = PathPercentEncodeSet.Set(0x2f, 0x3a, 0x3b, 0x3d, 0x40, 0x5b, 0x5c, 0x5d, 0x5e, 0x7c)
+var FragmentPercentEncodeSet = percent.FragmentSet
+var QueryPercentEncodeSet = percent.QuerySet
+var SpecialQueryPercentEncodeSet = percent.SpecialQuerySet
+var PathPercentEncodeSet = percent.PathSet
+var UserInfoPercentEncodeSet = percent.UserinfoSet
 var HostPercentEncodeSet = C0OrSpacePercentEncodeSet.Set(0x23)
 
+// laxHostPercentEncodeSet is used for domains with forbidden code points accepted by lax host parsing. It also
+// encodes the code points which would end the host if the serialized url is parsed again.
+var laxHostPercentEncodeSet = HostPercentEncodeSet.Set('/', ':', '?', '@', '[', '\\', ']')
+
+// zoneIDPercentEncodeSet encodes everything except the unreserved characters of RFC 3986
+var zoneIDPercentEncodeSet = C0OrSpacePercentEncodeSet.Set(0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28, 0x29, 0x2a,
+	0x2b, 0x2c, 0x2f, 0x3a, 0x3b, 0x3c, 0x3d, 0x3e, 0x3f, 0x40, 0x5b, 0x5c, 0x5d, 0x5e, 0x60, 0x7b, 0x7c, 0x7d)
+
 func init() {
 	for i := 'a'; i <= 'z'; i++ {
 		ASCIIAlpha.Set(uint(i))
@@ -136,6 +102,9 @@ func init() {
 	ASCIIAlphanumeric.InPlaceUnion(ASCIIAlpha)
 	ASCIIAlphanumeric.InPlaceUnion(ASCIIDigit)
 
+	schemeCodePoint.InPlaceUnion(ASCIIAlphanumeric)
+	schemeCodePoint.Set(0x2b).Set(0x2d).Set(0x2e)
+
 	ASCIIHexDigit.InPlaceUnion(ASCIIDigit)
 	for i := 'A'; i <= 'F'; i++ {
 		ASCIIHexDigit.Set(uint(i))
@@ -0,0 +1,139 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Difference is a component which differs between two urls, as returned by Diff.
+//
+// This API is EXPERIMENTAL.
+type Difference struct {
+	Component Component
+	// Index is the index of the path segment for ComponentPathSegment, and 0 for other components.
+	Index int
+	// Name is the name of the search parameter for a difference in a single parameter of the query. It is empty for
+	// other components and for differences in the query as a whole.
+	Name string
+	// A and B are the values in the first and second url. HasA and HasB are false if the url does not have the
+	// component.
+	A, B       string
+	HasA, HasB bool
+}
+
+// String returns a description of the difference meant for error messages, e.g. `host: "a.example" != "b.example"`.
+func (d Difference) String() string {
+	var sb strings.Builder
+	sb.WriteString(d.Component.String())
+	if d.Component == ComponentPathSegment {
+		fmt.Fprintf(&sb, " %d", d.Index)
+	}
+	if d.Name != "" {
+		fmt.Fprintf(&sb, " %q", d.Name)
+	}
+	sb.WriteString(": ")
+	writeDiffValue(&sb, d.A, d.HasA)
+	sb.WriteString(" != ")
+	writeDiffValue(&sb, d.B, d.HasB)
+	return sb.String()
+}
+
+func writeDiffValue(sb *strings.Builder, v string, ok bool) {
+	if !ok {
+		sb.WriteString("<none>")
+		return
+	}
+	fmt.Fprintf(sb, "%q", v)
+}
+
+// Diff compares the components of a and b and returns the ones which differ, in the order they appear in a
+// serialized url. Nil is returned if the urls are equal.
+//
+// Paths are compared segment by segment. Queries are compared parameter by parameter like in SearchParams.Diff. If
+// the queries only differ in the order or encoding of the parameters, the whole query is reported as different.
+//
+// This API is EXPERIMENTAL.
+func Diff(a, b *Url) []Difference {
+	var diffs []Difference
+	add := func(c Component, av string, aok bool, bv string, bok bool) {
+		if av != bv || aok != bok {
+			diffs = append(diffs, Difference{Component: c, A: av, B: bv, HasA: aok, HasB: bok})
+		}
+	}
+
+	add(ComponentScheme, a.scheme, true, b.scheme, true)
+	add(ComponentUsername, a.username, a.username != "", b.username, b.username != "")
+	add(ComponentPassword, a.password, a.password != "", b.password, b.password != "")
+	add(ComponentHost, a.Hostname(), a.host != nil, b.Hostname(), b.host != nil)
+	add(ComponentPort, a.Port(), a.port != nil, b.Port(), b.port != nil)
+
+	if a.path.isOpaque() || b.path.isOpaque() {
+		// Opaque paths have no segments, so the paths are compared as a whole
+		if a.path.String() != b.path.String() || a.path.isOpaque() != b.path.isOpaque() {
+			diffs = append(diffs, Difference{Component: ComponentOpaquePath, A: a.path.String(), B: b.path.String(),
+				HasA: true, HasB: true})
+		}
+	} else {
+		for i := 0; i < len(a.path.p) || i < len(b.path.p); i++ {
+			var av, bv string
+			aok, bok := i < len(a.path.p), i < len(b.path.p)
+			if aok {
+				av = a.path.p[i]
+			}
+			if bok {
+				bv = b.path.p[i]
+			}
+			if av != bv || aok != bok {
+				diffs = append(diffs, Difference{Component: ComponentPathSegment, Index: i, A: av, B: bv, HasA: aok, HasB: bok})
+			}
+		}
+	}
+
+	diffs = appendQueryDifferences(diffs, a, b)
+
+	add(ComponentFragment, a.Fragment(), a.fragment != nil, b.Fragment(), b.fragment != nil)
+	return diffs
+}
+
+// appendQueryDifferences appends the differences between the queries of a and b to diffs.
+func appendQueryDifferences(diffs []Difference, a, b *Url) []Difference {
+	aok, bok := a.query != nil, b.query != nil
+	if !aok && !bok {
+		return diffs
+	}
+	if aok != bok {
+		return append(diffs, Difference{Component: ComponentQuery, A: a.Query(), B: b.Query(), HasA: aok, HasB: bok})
+	}
+
+	qd := a.DiffQuery(b)
+	for _, c := range qd.Changed {
+		diffs = append(diffs, Difference{Component: ComponentQuery, Name: c.Name, A: c.OldValue, B: c.NewValue,
+			HasA: true, HasB: true})
+	}
+	for _, p := range qd.Removed {
+		diffs = append(diffs, Difference{Component: ComponentQuery, Name: p.Name, A: p.Value, HasA: true})
+	}
+	for _, p := range qd.Added {
+		diffs = append(diffs, Difference{Component: ComponentQuery, Name: p.Name, B: p.Value, HasB: true})
+	}
+	if qd.Empty() && a.Query() != b.Query() {
+		diffs = append(diffs, Difference{Component: ComponentQuery, A: a.Query(), B: b.Query(), HasA: true, HasB: true})
+	}
+	return diffs
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []string
+	}{
+		{"1", "http://example.com/a?b=c#d", "HTTP://EXAMPLE.com:80/a?b=c#d", nil},
+		{"2", "http://example.com/", "https://example.org:8080/", []string{`scheme: "http" != "https"`,
+			`host: "example.com" != "example.org"`, `port: <none> != "8080"`}},
+		{"3", "http://user:pw@example.com/", "http://user@example.com/", []string{`password: "pw" != <none>`}},
+		{"4", "http://example.com/a/b/c", "http://example.com/a/x", []string{`path segment 1: "b" != "x"`,
+			`path segment 2: "c" != <none>`}},
+		{"5", "http://example.com/?a=1&b=2&c=3", "http://example.com/?a=1&b=3&d=4", []string{`query "b": "2" != "3"`,
+			`query "c": "3" != <none>`, `query "d": <none> != "4"`}},
+		{"6", "http://example.com/?a=1&b=2", "http://example.com/?b=2&a=1", []string{`query: "a=1&b=2" != "b=2&a=1"`}},
+		{"7", "http://example.com/?", "http://example.com/#", []string{`query: "" != <none>`, `fragment: <none> != ""`}},
+		{"8", "mailto:a@example.com", "mailto:b@example.com", []string{`opaque path: "a@example.com" != "b@example.com"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := Parse(tt.a)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.a, err)
+			}
+			b, err := Parse(tt.b)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.b, err)
+			}
+			var got []string
+			for _, d := range Diff(a, b) {
+				got = append(got, d.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"strings"
+)
+
+// RemoveDotSegments resolves the "." and ".." segments of the absolute path p the way the parser does for urls
+// with a special scheme, e.g. "/a/./b/../c" becomes "/a/c". The percent-encoded forms "%2e" and "%2E" are dot
+// segments too, and a ".." never goes above the root. If the last segment is a dot segment, the result ends with
+// '/', e.g. "/a/b/.." becomes "/a/".
+//
+// The path is split on '/' only. Callers handling urls with a special scheme, where the parser treats '\' as '/',
+// must replace backslashes first. The Windows drive letter quirk of file: urls is not applied.
+func RemoveDotSegments(p string) string {
+	segments := RemoveDotPathSegments(strings.Split(strings.TrimPrefix(p, "/"), "/"))
+	var sb strings.Builder
+	sb.Grow(len(p))
+	for _, s := range segments {
+		sb.WriteByte('/')
+		sb.WriteString(s)
+	}
+	return sb.String()
+}
+
+// RemoveDotPathSegments is like RemoveDotSegments, but works on path segments as returned by Url.PathSegments,
+// e.g. "a", ".", "b", "..", "c" becomes "a", "c". The segments slice is not modified.
+func RemoveDotPathSegments(segments []string) []string {
+	result := path{p: make([]string, 0, len(segments))}
+	for i, s := range segments {
+		if !result.handleDotSegment(s, i == len(segments)-1, "") {
+			result.addSegment(s)
+		}
+	}
+	return result.p
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRemoveDotSegments(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"1", "/a/./b/../c", "/a/c"},
+		{"2", "/a/b/..", "/a/"},
+		{"3", "/a/b/.", "/a/b/"},
+		{"4", "/../../a", "/a"},
+		{"5", "/a/%2e%2E/b/%2e/c", "/b/c"},
+		{"6", "/a/.%2e/.../b", "/.../b"},
+		{"7", "/", "/"},
+		{"8", "", "/"},
+		{"9", "/a//../b/", "/a/b/"},
+		{"10", "a/../b", "/b"},
+		{"11", "/a/..%2fb", "/a/..%2fb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RemoveDotSegments(tt.path); got != tt.want {
+				t.Errorf("RemoveDotSegments(%v) = %v, want %v", tt.path, got, tt.want)
+			}
+			if tt.path == "" || tt.path[0] != '/' {
+				return
+			}
+			// The result must be the same as the path of a parsed url
+			u, err := Parse("http://example.com" + tt.path)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := u.Pathname(); got != tt.want {
+				t.Errorf("Pathname() = %v, RemoveDotSegments(%v) = %v", got, tt.path, tt.want)
+			}
+		})
+	}
+
+	segments := []string{"a", ".", "b", "..", "c"}
+	if got, want := RemoveDotPathSegments(segments), []string{"a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RemoveDotPathSegments() = %v, want %v", got, want)
+	}
+	if segments[1] != "." {
+		t.Errorf("RemoveDotPathSegments() modified its argument")
+	}
+}
@@ -21,37 +21,105 @@ import (
 )
 
 // handleError handles an error according to the options set for the parser
-func (p *parser) handleError(u *Url, errorType errors.ErrorType, failure bool) error {
-	e := errors.Error(errorType, u.inputUrl, failure)
-	if p.opts.reportValidationErrors {
-		u.validationErrors = append(u.validationErrors, e)
-	}
-	if failure || p.opts.failOnValidationError {
-		return e
-	}
-	return nil
+func (p *parser) handleError(s errorSink, errorType errors.ErrorType, failure bool) error {
+	return p.handleValidationError(s, errors.Error(errorType, s.input(), failure), failure)
 }
 
 // handleErrorWithDescription handles an error according to the options set for the parser
-func (p *parser) handleErrorWithDescription(u *Url, errorType errors.ErrorType, failure bool, descr string) error {
-	e := errors.ErrorWithDescr(errorType, descr, u.inputUrl, failure)
-	if p.opts.reportValidationErrors {
-		u.validationErrors = append(u.validationErrors, e)
+func (p *parser) handleErrorWithDescription(s errorSink, errorType errors.ErrorType, failure bool, descr string) error {
+	return p.handleValidationError(s, errors.ErrorWithDescr(errorType, descr, s.input(), failure), failure)
+}
+
+// handleWrappedError handles an error according to the options set for the parser
+func (p *parser) handleWrappedError(s errorSink, errorType errors.ErrorType, failure bool, cause error) error {
+	return p.handleValidationError(s, errors.Wrap(cause, errorType, s.input(), failure), failure)
+}
+
+// handleWrappedErrorWithDescription handles an error according to the options set for the parser
+func (p *parser) handleWrappedErrorWithDescription(s errorSink, errorType errors.ErrorType, failure bool, descr string, cause error) error {
+	return p.handleValidationError(s, errors.WrapWithDescr(cause, errorType, descr, s.input(), failure), failure)
+}
+
+// handleValidationError records the error and decides if parsing should be aborted.
+// Failures always abort. Non-fatal errors abort if the parser is configured to fail on validation errors
+// or if the validation error handler returns false.
+func (p *parser) handleValidationError(s errorSink, e error, failure bool) error {
+	if ve, ok := e.(*errors.ValidationError); ok {
+		if pos, ok := s.position(); ok {
+			e = ve.WithPosition(pos)
+		}
 	}
-	if failure || p.opts.failOnValidationError {
-		return e
+	if p.opts.reportValidationErrors || p.opts.joinValidationErrors {
+		s.addValidationError(e)
+	}
+	if failure && p.opts.recoverFailures && recoverableFailures[errors.Type(e)] {
+		return nil
+	}
+	if failure || p.opts.failOnValidationError && !p.opts.collectValidationErrors {
+		return p.joinedError(s, e)
+	}
+	if p.opts.validationErrorHandler != nil {
+		if ve, ok := e.(*errors.ValidationError); ok && !p.opts.validationErrorHandler(ve) {
+			return p.joinedError(s, e)
+		}
 	}
 	return nil
 }
 
-// handleWrappedError handles an error according to the options set for the parser
-func (p *parser) handleWrappedError(u *Url, errorType errors.ErrorType, failure bool, cause error) error {
-	e := errors.Wrap(cause, errorType, u.inputUrl, failure)
-	if p.opts.reportValidationErrors {
-		u.validationErrors = append(u.validationErrors, e)
+// joinedError returns the error to return from a failed parse. If the parser joins validation errors, this is all
+// the validation errors recorded for the Url joined together. Otherwise e is returned.
+func (p *parser) joinedError(s errorSink, e error) error {
+	if !p.opts.joinValidationErrors {
+		return e
 	}
-	if failure || p.opts.failOnValidationError {
+	u, ok := s.(*Url)
+	if !ok || len(u.validationErrors) < 2 {
 		return e
 	}
-	return nil
+	errs := make([]error, len(u.validationErrors))
+	copy(errs, u.validationErrors)
+	return &joinedValidationErrors{errs: errs, last: e}
+}
+
+// joinedValidationErrors is the error returned by a failed parse when validation errors are joined. Like the result of
+// errors.Join it unwraps to all the errors, but Type, Url, Failure and Description report the error which made
+// parsing fail, so the helpers in the errors package work as if only that error was returned.
+type joinedValidationErrors struct {
+	errs []error
+	last error
+}
+
+// Error returns the messages of the errors separated by newlines, like the result of errors.Join.
+func (e *joinedValidationErrors) Error() string {
+	b := []byte(e.errs[0].Error())
+	for _, err := range e.errs[1:] {
+		b = append(b, '\n')
+		b = append(b, err.Error()...)
+	}
+	return string(b)
+}
+
+// Unwrap returns all the joined errors.
+func (e *joinedValidationErrors) Unwrap() []error {
+	return e.errs
+}
+
+// Type returns the error type of the error which made parsing fail.
+func (e *joinedValidationErrors) Type() errors.ErrorType {
+	return errors.Type(e.last)
+}
+
+// Url returns the url causing the error.
+func (e *joinedValidationErrors) Url() string {
+	return errors.Url(e.last)
+}
+
+// Failure returns true if the error which made parsing fail is a failure.
+func (e *joinedValidationErrors) Failure() bool {
+	return errors.Failure(e.last)
+}
+
+// Description returns the description of the error which made parsing fail.
+func (e *joinedValidationErrors) Description() string {
+	return errors.Description(e.last)
 }
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"testing"
+
+	"github.com/nlnwa/whatwg-url/errors"
+)
+
+func TestValidationError_Position(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantType  errors.ErrorType
+		wantPos   errors.Position
+		wantKnown bool
+	}{
+		{"1", "data:text/plain,abc def%zz", errors.InvalidURLUnit, errors.Position{Offset: 19, Component: "path", State: "opaque path state"}, true},
+		{"2", "http://example.com/?a=%zz", errors.InvalidURLUnit, errors.Position{Offset: 22, Component: "query", State: "query state"}, true},
+		{"3", "http://example.com:99999/", errors.PortOutOfRange, errors.Position{Offset: 24, Component: "port", State: "port state"}, true},
+		{"4", "http:\\\\example.com/", errors.SpecialSchemeMissingFollowingSolidus, errors.Position{Offset: 5, Component: "authority", State: "special authority slashes state"}, true},
+		{"5", "http://exa mple.com/", errors.DomainInvalidCodePoint, errors.Position{Offset: 18, Component: "host", State: "host state"}, true},
+		{"6", " http://example.com/", errors.InvalidURLUnit, errors.Position{}, false},
+	}
+	p := NewParser(WithReportValidationErrors())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := p.Parse(tt.input)
+			var errs []error
+			if err != nil {
+				errs = append(errs, err)
+			} else {
+				errs = u.ValidationErrors()
+			}
+			if len(errs) == 0 {
+				t.Fatalf("Parse(%q) gave no validation errors", tt.input)
+			}
+			ve, ok := errs[0].(*errors.ValidationError)
+			if !ok || ve.Type() != tt.wantType {
+				t.Fatalf("Parse(%q) error = %v, want %v", tt.input, errs[0], tt.wantType)
+			}
+			got, known := ve.Position()
+			if known != tt.wantKnown || got != tt.wantPos {
+				t.Errorf("Position() = %+v, %v, want %+v, %v", got, known, tt.wantPos, tt.wantKnown)
+			}
+		})
+	}
+}
+
+func TestValidationError_State(t *testing.T) {
+	u, err := NewParser(WithReportValidationErrors()).Parse("http://example.com/")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	u.SetPort("99999")
+	if len(u.ValidationErrors()) != 1 {
+		t.Fatalf("ValidationErrors() = %v, want 1 error", u.ValidationErrors())
+	}
+	ve := u.ValidationErrors()[0].(*errors.ValidationError)
+	if ve.Type() != errors.PortOutOfRange || ve.State() != "port state" {
+		t.Errorf("SetPort() error = %v in %q, want %v in %q", ve.Type(), ve.State(), errors.PortOutOfRange, "port state")
+	}
+}
@@ -0,0 +1,300 @@
+/*
+ * Copyright 2020 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"strconv"
+	"strings"
+)
+
+// canonicalComponents holds the components a url parsed by the fast path points to.
+type canonicalComponents struct {
+	path     path
+	host     Host
+	port     string
+	query    string
+	fragment string
+	segments [8]string
+}
+
+// canonicalUrl holds a Url and the components it points to, allowing a url parsed by the fast path to be
+// allocated in one go.
+type canonicalUrl struct {
+	url Url
+	canonicalComponents
+}
+
+// fastPathAllowed returns true if the options of the parser allow canonical urls to be parsed by parseCanonical.
+// Options with hooks or host policies need the full parser.
+func (p *parser) fastPathAllowed() bool {
+	o := &p.opts
+	return o.preParseHostFunc == nil && o.postParseHostFunc == nil && o.resolveHostFunc == nil &&
+		!o.requireDottedHost && !o.forbidLoopback && !o.forbidPrivateAddresses && !o.verifyDNSLength &&
+		o.trace == nil && !o.recordSpans
+}
+
+// canonicalLayout holds the offsets of the components of a canonical url found by scanCanonical.
+type canonicalLayout struct {
+	schemeEnd    int // the host starts after "://" following the scheme
+	hostEnd      int
+	portEnd      int // equal to hostEnd if there is no port
+	pathEnd      int
+	queryEnd     int // equal to pathEnd if there is no query
+	decodedPort  int
+	segmentCount int
+}
+
+// parseCanonical parses input if it is a url with a special scheme (except file) which is already in its serialized
+// form, e.g. "https://example.com:8080/a/b?c#d". This is the case for most urls coming from our own serializer.
+// The components are slices of input, so no per code point processing is needed. The host must be an ASCII domain
+// without punycode labels, and the url must not have credentials, dot segments or anything needing percent-encoding.
+// A canonical url has no validation errors.
+//
+// Nil is returned if input is not canonical, in which case it must be parsed by the basic parser.
+func (p *parser) parseCanonical(input string) *Url {
+	if l, ok := p.scanCanonical(input); ok {
+		return l.build(p, input, nil)
+	}
+	return nil
+}
+
+// scanCanonical returns the layout of input and true if input is a canonical url as described for parseCanonical.
+func (p *parser) scanCanonical(input string) (l canonicalLayout, ok bool) {
+	if !p.fastPathAllowed() {
+		return l, false
+	}
+
+	// Scheme
+	i := 0
+	for i < len(input) && 'a' <= input[i] && input[i] <= 'z' {
+		i++
+	}
+	if i == 0 || !strings.HasPrefix(input[i:], "://") {
+		return l, false
+	}
+	scheme := input[:i]
+	defaultPort, special := p.opts.specialScheme(scheme)
+	if !special || scheme == "file" {
+		return l, false
+	}
+	l.schemeEnd = i
+
+	// Host
+	i += 3
+	labelStart := i
+	for ; i < len(input); i++ {
+		c := input[i]
+		if c == '.' {
+			if !isCanonicalLabel(input[labelStart:i]) {
+				return l, false
+			}
+			labelStart = i + 1
+		} else if !('a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-') {
+			break
+		}
+		if p.opts.forbiddenDomainCodePoints.Test(uint(c)) {
+			return l, false
+		}
+	}
+	// A last label starting with a digit might be a number, making the host an IPv4 address
+	if lastLabel := input[labelStart:i]; !isCanonicalLabel(lastLabel) || lastLabel[0] < 'a' {
+		return l, false
+	}
+	l.hostEnd = i
+
+	// Port
+	if i < len(input) && input[i] == ':' {
+		i++
+		portStart := i
+		for i < len(input) && '0' <= input[i] && input[i] <= '9' {
+			i++
+		}
+		port := input[portStart:i]
+		if port == "" || len(port) > 5 || (len(port) > 1 && port[0] == '0') || port == defaultPort {
+			return l, false
+		}
+		l.decodedPort, _ = strconv.Atoi(port)
+		if l.decodedPort > 65535 {
+			return l, false
+		}
+	}
+	l.portEnd = i
+
+	// Path
+	if i == len(input) || input[i] != '/' {
+		return l, false
+	}
+	for ; i < len(input) && input[i] != '?' && input[i] != '#'; i++ {
+		if input[i] == '/' {
+			l.segmentCount++
+		} else if !isCanonicalCodePoint(input, i, p.opts.pathPercentEncodeSet) {
+			return l, false
+		}
+	}
+	l.pathEnd = i
+	pathString := input[l.portEnd+1 : i]
+	if p.opts.collapseConsecutiveSlashes && strings.Contains(pathString, "//") {
+		return l, false
+	}
+	for rest := pathString; ; {
+		segment, next, found := strings.Cut(rest, "/")
+		if isSingleDotPathSegment(segment) || isDoubleDotPathSegment(segment) {
+			return l, false
+		}
+		if !found {
+			break
+		}
+		rest = next
+	}
+
+	// Query
+	if i < len(input) && input[i] == '?' {
+		for i++; i < len(input) && input[i] != '#'; i++ {
+			if !isCanonicalCodePoint(input, i, p.opts.specialQueryPercentEncodeSet) {
+				return l, false
+			}
+		}
+	}
+	l.queryEnd = i
+
+	// Fragment
+	if i < len(input) {
+		for i++; i < len(input); i++ {
+			if !isCanonicalCodePoint(input, i, p.opts.specialFragmentPercentEncodeSet) {
+				return l, false
+			}
+		}
+	}
+	return l, true
+}
+
+// build returns the url with the layout l. The components of the url are slices of input.
+// If u is not nil, the url is stored in u instead of in a newly allocated Url, and the components are stored in
+// u.own, which is allocated the first time and reused after that, so parsing into u does not allocate.
+func (l canonicalLayout) build(p *parser, input string, u *Url) *Url {
+	var c *canonicalComponents
+	var own *canonicalComponents
+	if u == nil {
+		cu := &canonicalUrl{}
+		u, c = &cu.url, &cu.canonicalComponents
+	} else {
+		if u.own == nil {
+			u.own = &canonicalComponents{}
+		}
+		c, own = u.own, u.own
+	}
+	if l.segmentCount <= len(c.segments) {
+		c.path.p = c.segments[:0]
+	} else {
+		c.path.p = make([]string, 0, l.segmentCount)
+	}
+	for rest := input[l.portEnd+1 : l.pathEnd]; ; {
+		segment, next, found := strings.Cut(rest, "/")
+		c.path.p = append(c.path.p, segment)
+		if !found {
+			break
+		}
+		rest = next
+	}
+
+	host := input[l.schemeEnd+3 : l.hostEnd]
+	c.host = Host{Kind: DomainHost, Domain: host, ASCII: host, Unicode: host}
+	*u = Url{
+		inputUrl:    input,
+		scheme:      input[:l.schemeEnd],
+		host:        &c.host,
+		decodedPort: l.decodedPort,
+		path:        &c.path,
+		parser:      p,
+		own:         own,
+	}
+	if l.portEnd > l.hostEnd {
+		c.port = input[l.hostEnd+1 : l.portEnd]
+		u.port = &c.port
+	}
+	if l.queryEnd > l.pathEnd {
+		c.query = input[l.pathEnd+1 : l.queryEnd]
+		u.query = &c.query
+	}
+	if l.queryEnd < len(input) {
+		c.fragment = input[l.queryEnd+1:]
+		u.fragment = &c.fragment
+	}
+	return u
+}
+
+// detached returns u, or a copy of u if some of its components are stored in u.own. The copy has its own copies of
+// those components, so a url parsed relative to u is not changed when u is reused by Parser.ParseInto.
+func (u *Url) detached() *Url {
+	own := u.own
+	if own == nil || (u.host != &own.host && u.path != &own.path && u.port != &own.port && u.query != &own.query &&
+		u.fragment != &own.fragment) {
+		return u
+	}
+	d := &Url{
+		inputUrl:    u.inputUrl,
+		scheme:      u.scheme,
+		username:    u.username,
+		password:    u.password,
+		host:        u.host,
+		port:        u.port,
+		decodedPort: u.decodedPort,
+		path:        u.path,
+		query:       u.query,
+		fragment:    u.fragment,
+		parser:      u.parser,
+	}
+	if d.host == &own.host {
+		h := *d.host
+		d.host = &h
+	}
+	if d.path == &own.path {
+		d.path = &path{p: append([]string(nil), own.path.p...), opaque: own.path.opaque}
+	}
+	if d.port == &own.port {
+		port := own.port
+		d.port = &port
+	}
+	if d.query == &own.query {
+		query := own.query
+		d.query = &query
+	}
+	if d.fragment == &own.fragment {
+		fragment := own.fragment
+		d.fragment = &fragment
+	}
+	return d
+}
+
+// isCanonicalLabel returns true if label is a non-empty domain label which is not changed by domain to ASCII.
+func isCanonicalLabel(label string) bool {
+	return label != "" && !strings.HasPrefix(label, "xn--")
+}
+
+// isCanonicalCodePoint returns true if the byte at index i of s is an ASCII url code point which is not percent-encoded
+// by the encode set tr, or the start of a valid percent-encoded byte.
+func isCanonicalCodePoint(s string, i int, tr *PercentEncodeSet) bool {
+	c := s[i]
+	if c == '%' {
+		if i+2 >= len(s) || !ASCIIHexDigit.Test(uint(s[i+1])) || !ASCIIHexDigit.Test(uint(s[i+2])) {
+			return false
+		}
+	} else if c >= 0x80 || !isURLCodePoint(rune(c)) {
+		return false
+	}
+	return !tr.ByteShouldBeEncoded(c)
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright 2019 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// checkParseCanonical fails the test if parseCanonical gives a different url than the basic parser for input.
+// It returns true if the fast path was taken.
+func checkParseCanonical(t *testing.T, p *parser, input string) bool {
+	t.Helper()
+	got := p.parseCanonical(input)
+	if got == nil {
+		return false
+	}
+	want, err := p.runBasicParser(context.Background(), input, nil, nil, NoState)
+	if err != nil {
+		t.Errorf("parseCanonical(%v) = %v, but basic parser fails with error = %v", input, got, err)
+		return true
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCanonical(%v) = %#v, want %#v", input, got, want)
+	}
+	return true
+}
+
+func TestParseCanonical(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		fastPath bool
+	}{
+		{"1", "https://example.com/", true},
+		{"2", "http://www.example.com:8080/a/b/c.html?q=1&r=%20#frag", true},
+		{"3", "wss://a-b.example/c//d/?", true},
+		{"4", "http://example.com/" + "a/b/c/d/e/f/g/h/i/j", true},
+		{"5", "https://example.com:443/", false},
+		{"6", "https://Example.com/", false},
+		{"7", "HTTPS://example.com/", false},
+		{"8", "https://example.com", false},
+		{"9", "https://127.0.0.1/", false},
+		{"10", "https://example.0x1/", false},
+		{"11", "https://xn--bcher-kva.example/", false},
+		{"12", "https://user@example.com/", false},
+		{"13", "https://example.com/a/../b", false},
+		{"14", "https://example.com/a/%2E/b", false},
+		{"15", "https://example.com/a b", false},
+		{"16", "https://example.com/%zz", false},
+		{"17", "https://example.com/blåbær", false},
+		{"18", "https://example.com/?'", false},
+		{"19", "https://example.com/#a#b", false},
+		{"20", "file:///etc/passwd", false},
+		{"21", "foo://example.com/", false},
+		{"22", "https://example.com:080/", false},
+		{"23", "https://example.com:99999/", false},
+		{"24", "https://example..com/", false},
+		{"25", "https://example.com./", false},
+		{"26", " https://example.com/", false},
+		{"27", "https://example.com/\\", false},
+	}
+	p := defaultParser.(*parser)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkParseCanonical(t, p, tt.input); got != tt.fastPath {
+				t.Errorf("parseCanonical(%v) took fast path = %v, want %v", tt.input, got, tt.fastPath)
+			}
+		})
+	}
+
+	if p := NewParser(WithForbidLoopback()).(*parser); p.parseCanonical("https://example.com/") != nil {
+		t.Errorf("parseCanonical() took fast path with host policy")
+	}
+	if p := NewParser(WithCollapseConsecutiveSlashes()).(*parser); p.parseCanonical("https://example.com/a//b") != nil {
+		t.Errorf("parseCanonical() took fast path with consecutive slashes to collapse")
+	}
+}
+
+// TestParseCanonicalTestData checks that the fast path agrees with the basic parser for the urls in the
+// WHATWG URL Standard test suite, both as input and as serialized by the parser.
+func TestParseCanonicalTestData(t *testing.T) {
+	var tests []struct {
+		Input string
+		Href  string
+	}
+
+	jsonFile, err := os.Open("../testdata/urltestdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer jsonFile.Close()
+	data, _ := io.ReadAll(jsonFile)
+	_ = json.Unmarshal(data, &tests)
+
+	p := defaultParser.(*parser)
+	var fastPaths int
+	for i, tt := range tests {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			for _, input := range []string{tt.Input, tt.Href} {
+				if input != "" && checkParseCanonical(t, p, input) {
+					fastPaths++
+				}
+			}
+		})
+	}
+	if fastPaths == 0 {
+		t.Errorf("no url in the test data took the fast path")
+	}
+}
+
+func BenchmarkParseCanonical(b *testing.B) {
+	input := "https://www.example.com/path/to/some/resource.html?query=value&other=thing#fragment"
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = Parse(input)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nlnwa/whatwg-url/percent"
+)
+
+// filePathPercentEncodeSet is used for the segments of a file path. In addition to the path percent-encode set, '%'
+// is encoded since it is a literal character in a file name and '\' since the parser treats it as a separator.
+var filePathPercentEncodeSet = PathPercentEncodeSet.Set('%', '\\')
+
+// FromFilePath returns a file: url for the absolute file path p.
+//
+// The kind of path is decided by its syntax, not by the operating system the program runs on, so urls can be
+// created for paths from other systems:
+//
+//	C:\dir\file.txt          file:///C:/dir/file.txt
+//	C:/dir/file.txt          file:///C:/dir/file.txt
+//	\\server\share\file.txt  file://server/share/file.txt
+//	\\?\C:\dir\file.txt      file:///C:/dir/file.txt
+//	\\?\UNC\server\share     file://server/share
+//	/dir/file.txt            file:///dir/file.txt
+//
+// In POSIX paths a '\' is part of the file name and is percent-encoded. An error is returned for relative paths.
+func FromFilePath(p string) (*Url, error) {
+	host, segments, err := splitFilePath(p)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, 0, len("file://")+len(p)+len(host))
+	b = append(b, "file://"...)
+	b = append(b, host...)
+	for _, s := range segments {
+		b = append(b, '/')
+		b = percent.AppendEncode(b, s, filePathPercentEncodeSet)
+	}
+	return defaultParser.Parse(string(b))
+}
+
+// splitFilePath splits an absolute file path into host and path segments.
+func splitFilePath(p string) (host string, segments []string, err error) {
+	// Win32 file namespace prefix
+	if strings.HasPrefix(p, `\\?\`) {
+		p = p[4:]
+		if len(p) >= 4 && strings.EqualFold(p[:4], `UNC\`) {
+			p = `\\` + p[4:]
+		}
+	}
+
+	switch {
+	case len(p) >= 3 && isNormalizedWindowsDriveLetter(p[:2]) && (p[2] == '\\' || p[2] == '/'):
+		segments = append([]string{p[:2]}, strings.FieldsFunc(p[3:], isWindowsSeparator)...)
+		if isWindowsSeparator(rune(p[len(p)-1])) && len(segments) > 1 {
+			segments = append(segments, "")
+		}
+		if len(segments) == 1 {
+			segments = append(segments, "")
+		}
+		return "", segments, nil
+	case strings.HasPrefix(p, `\\`):
+		parts := strings.FieldsFunc(p[2:], isWindowsSeparator)
+		if len(parts) == 0 {
+			return "", nil, fmt.Errorf("UNC path %q is missing server name", p)
+		}
+		segments = parts[1:]
+		if isWindowsSeparator(rune(p[len(p)-1])) || len(segments) == 0 {
+			segments = append(segments, "")
+		}
+		return parts[0], segments, nil
+	case strings.HasPrefix(p, "/"):
+		return "", strings.Split(p[1:], "/"), nil
+	}
+	return "", nil, fmt.Errorf("file path %q is not absolute", p)
+}
+
+func isWindowsSeparator(r rune) bool {
+	return r == '\\' || r == '/'
+}
+
+// ToFilePath returns the file path of a file: url. This is the reverse of FromFilePath.
+//
+// A url with a host is returned as a UNC path and a url whose first path segment is a Windows drive letter is
+// returned as a Windows path, both with '\' as separator. Other urls are returned as POSIX paths. The segments are
+// percent-decoded. An error is returned if the url is not a file: url or if a segment decodes to a separator or NUL.
+func (u *Url) ToFilePath() (string, error) {
+	if u.scheme != "file" {
+		return "", fmt.Errorf("url %q is not a file: url", u.Href(true))
+	}
+	segments := u.PathSegments()
+	host := u.Hostname()
+
+	windows := host != "" || (len(segments) > 0 && isNormalizedWindowsDriveLetter(segments[0]))
+	forbidden := "/\x00"
+	sep := byte('/')
+	if windows {
+		forbidden = "/\\\x00"
+		sep = '\\'
+	}
+
+	var b []byte
+	if host != "" {
+		b = append(b, '\\', '\\')
+		b = append(b, host...)
+	}
+	for i, s := range segments {
+		if i > 0 || !windows || host != "" {
+			b = append(b, sep)
+		}
+		start := len(b)
+		b = percent.AppendDecode(b, s)
+		if strings.ContainsAny(string(b[start:]), forbidden) {
+			return "", fmt.Errorf("url %q has a path segment which can not be represented in a file path", u.Href(true))
+		}
+	}
+	if len(segments) == 1 && windows && host == "" {
+		// A drive letter only, e.g. "file:///C:"
+		b = append(b, sep)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"testing"
+)
+
+func TestFromFilePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		want     string
+		wantPath string
+		wantErr  bool
+	}{
+		{"1", `C:\dir\file.txt`, "file:///C:/dir/file.txt", `C:\dir\file.txt`, false},
+		{"2", `C:/dir/file.txt`, "file:///C:/dir/file.txt", `C:\dir\file.txt`, false},
+		{"3", `c:\`, "file:///c:/", `c:\`, false},
+		{"4", `C:\dir\`, "file:///C:/dir/", `C:\dir\`, false},
+		{"5", `C:\a b\100%\#1?.txt`, "file:///C:/a%20b/100%25/%231%3F.txt", `C:\a b\100%\#1?.txt`, false},
+		{"6", `C:\blåbær\文件`, "file:///C:/bl%C3%A5b%C3%A6r/%E6%96%87%E4%BB%B6", `C:\blåbær\文件`, false},
+		{"7", `\\server\share\file.txt`, "file://server/share/file.txt", `\\server\share\file.txt`, false},
+		{"8", `\\server`, "file://server/", `\\server\`, false},
+		{"9", `\\?\C:\dir\file.txt`, "file:///C:/dir/file.txt", `C:\dir\file.txt`, false},
+		{"10", `\\?\UNC\server\share`, "file://server/share", `\\server\share`, false},
+		{"11", "/dir/file.txt", "file:///dir/file.txt", "/dir/file.txt", false},
+		{"12", "/", "file:///", "/", false},
+		{"13", `/dir/a\b`, "file:///dir/a%5Cb", `/dir/a\b`, false},
+		{"14", "/dir//file", "file:///dir//file", "/dir//file", false},
+		{"15", "dir/file.txt", "", "", true},
+		{"16", `C:file.txt`, "", "", true},
+		{"17", `\\`, "", "", true},
+		{"18", "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromFilePath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromFilePath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Href(false) != tt.want {
+				t.Errorf("FromFilePath() = %v, want %v", got.Href(false), tt.want)
+			}
+			p, err := got.ToFilePath()
+			if err != nil {
+				t.Fatalf("ToFilePath() error = %v", err)
+			}
+			if p != tt.wantPath {
+				t.Errorf("ToFilePath() = %v, want %v", p, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestUrl_ToFilePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{"1", "file:///C|/dir/file.txt", `C:\dir\file.txt`, false},
+		{"2", "file://localhost/etc/hosts", "/etc/hosts", false},
+		{"3", "file:///C:", `C:\`, false},
+		{"4", "file://C:/dir", `C:\dir`, false},
+		{"5", "file:///dir/a%2Fb", "", true},
+		{"6", "file:///C:/dir/a%5Cb", "", true},
+		{"7", "file:///dir/a%00b", "", true},
+		{"8", "http://example.com/file.txt", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := Parse(tt.url)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got, err := u.ToFilePath()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToFilePath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ToFilePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
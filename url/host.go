@@ -0,0 +1,166 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"strings"
+
+	"github.com/nlnwa/whatwg-url/idn"
+)
+
+// HostKind tells which kind of host (https://url.spec.whatwg.org/#concept-host) a Host represents.
+type HostKind int
+
+const (
+	// EmptyHost is the empty host.
+	EmptyHost HostKind = iota
+	// DomainHost is a domain. Hosts which are not valid domains, but accepted by lax host parsing, are also of this kind.
+	DomainHost
+	// IPv4Host is an IPv4 address.
+	IPv4Host
+	// IPv6Host is an IPv6 address.
+	IPv6Host
+	// OpaqueHost is an opaque host (the host of a URL that is not special).
+	OpaqueHost
+)
+
+func (k HostKind) String() string {
+	switch k {
+	case EmptyHost:
+		return "empty"
+	case DomainHost:
+		return "domain"
+	case IPv4Host:
+		return "IPv4"
+	case IPv6Host:
+		return "IPv6"
+	case OpaqueHost:
+		return "opaque"
+	}
+	return "unknown"
+}
+
+// Host is the result of parsing a host.
+// Only the fields relevant for the Kind are set.
+type Host struct {
+	Kind HostKind
+	// Domain is the percent decoded domain as it was before conversion to ASCII.
+	Domain string
+	// ASCII is the domain converted to ASCII. This is the serialized form of a domain.
+	ASCII string
+	// Unicode is the domain converted to Unicode.
+	Unicode string
+	// TrailingDot is true if the domain was written with a trailing dot (e.g. "example.com.").
+	// It is set even if the trailing dot was stripped by the parser.
+	TrailingDot bool
+	IPv4        IPv4Addr
+	// IPv4Original is the IPv4 address as written in the input before it was normalized
+	// (e.g. "0xffffffff" or "3279880203").
+	IPv4Original string
+	IPv6         IPv6Addr
+	// IPv6Style is the style used when serializing an IPv6 address.
+	IPv6Style IPv6Style
+	// Zone is the percent decoded zone identifier of an IPv6 address (RFC 6874).
+	// It is only set if the parser accepts zone identifiers.
+	Zone   string
+	Opaque string
+}
+
+// String returns the serialized host (https://url.spec.whatwg.org/#concept-host-serializer).
+func (h *Host) String() string {
+	switch h.Kind {
+	case DomainHost:
+		return h.ASCII
+	case IPv4Host:
+		return h.IPv4.String()
+	case IPv6Host:
+		address := h.IPv6.String()
+		if h.IPv6Style == IPv6Expanded {
+			address = h.IPv6.Expanded()
+		}
+		if h.Zone != "" {
+			return "[" + address + "%25" + percentEncodeString(h.Zone, zoneIDPercentEncodeSet) + "]"
+		}
+		return "[" + address + "]"
+	case OpaqueHost:
+		return h.Opaque
+	}
+	return ""
+}
+
+// IsObfuscatedIPv4 returns true if the host is an IPv4 address which was not written in the
+// dotted-decimal form it is serialized to (e.g. "0x7f.1" or "2130706433" for 127.0.0.1).
+// A trailing dot is not considered obfuscation, so "127.0.0.1." is not obfuscated, while "%31%32%37.0.0.1" is.
+func (h *Host) IsObfuscatedIPv4() bool {
+	return h.Kind == IPv4Host && strings.TrimSuffix(h.IPv4Original, ".") != h.IPv4.String()
+}
+
+// isEmpty returns true if the host serializes to the empty string.
+func (h *Host) isEmpty() bool {
+	switch h.Kind {
+	case DomainHost:
+		return h.ASCII == ""
+	case OpaqueHost:
+		return h.Opaque == ""
+	case IPv4Host, IPv6Host:
+		return false
+	}
+	return true
+}
+
+// newDomainHost creates a domain host and computes the Unicode form of the ASCII domain.
+func newDomainHost(domain, ascii string) *Host {
+	h := &Host{Kind: DomainHost, Domain: domain, ASCII: ascii, Unicode: ascii}
+	if strings.Contains(ascii, "xn--") {
+		if u, err := idn.ToUnicode(ascii, false); err == nil {
+			h.Unicode = u
+		}
+	}
+	return h
+}
+
+// HostEqual returns true if the hosts a and b are equal after host parsing.
+// Domains are compared after domain to ASCII and with a trailing dot removed. IP addresses are compared by value.
+// If one of the hosts can not be parsed, false is returned.
+//
+// e.g. "BÜCHER.example." is equal to "xn--bcher-kva.example" and "0x7f.1" is equal to "127.0.0.1".
+func HostEqual(a, b string, opts ...ParserOption) bool {
+	p := NewParser(opts...).(*parser)
+	ha, err := p.parseHost(inputSink(a), a, false)
+	if err != nil {
+		return false
+	}
+	hb, err := p.parseHost(inputSink(b), b, false)
+	if err != nil {
+		return false
+	}
+	return ha.Kind == hb.Kind && ha.key() == hb.key()
+}
+
+// key returns the form of the host used when comparing hosts.
+// This is the serialized host with the trailing dot removed from domains and IPv6 addresses in compressed form.
+func (h *Host) key() string {
+	switch h.Kind {
+	case DomainHost:
+		return strings.TrimSuffix(h.ASCII, ".")
+	case IPv6Host:
+		c := *h
+		c.IPv6Style = IPv6Compressed
+		return c.String()
+	}
+	return h.String()
+}
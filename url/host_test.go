@@ -0,0 +1,45 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import "testing"
+
+func TestHostEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want bool
+	}{
+		{"1", "BÜCHER.example.", "xn--bcher-kva.example", true},
+		{"2", "example.com", "EXAMPLE.COM", true},
+		{"3", "example.com", "example.org", false},
+		{"4", "0x7f.1", "127.0.0.1", true},
+		{"5", "[0:0::1]", "[::1]", true},
+		{"6", "127.0.0.1", "[::ffff:127.0.0.1]", false},
+		{"7", "example%2Ecom", "example.com", true},
+		{"8", "exa^mple.com", "exa^mple.com", false},
+		{"9", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HostEqual(tt.a, tt.b); got != tt.want {
+				t.Errorf("HostEqual(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MatchHost returns true if host matches pattern.
+//
+// A pattern is either a host, which must be equal to host, or a domain prefixed with "*.", which matches any
+// subdomain of the domain, but not the domain itself. Both pattern and host are normalized by the host parser
+// (https://url.spec.whatwg.org/#host-parsing) before matching, so "*.BÜCHER.example" matches "www.xn--bcher-kva.example."
+// and "127.0.0.1" matches "0x7f.1". If pattern or host can not be parsed, false is returned.
+//
+// Use a HostMatcher when matching against many patterns.
+func MatchHost(pattern, host string) bool {
+	m, err := NewHostMatcher(pattern)
+	if err != nil {
+		return false
+	}
+	return m.Match(host)
+}
+
+// HostMatcher matches hosts against a set of patterns. The patterns are normalized once when the matcher is created
+// and matching is done with map lookups, making it suitable for large allow and deny lists.
+// See MatchHost for the syntax of patterns.
+//
+// A HostMatcher is safe for concurrent use.
+type HostMatcher struct {
+	parser   *parser
+	exact    map[string]bool
+	wildcard map[string]bool
+}
+
+// NewHostMatcher creates a HostMatcher for the given patterns.
+// An error is returned if a pattern can not be parsed.
+func NewHostMatcher(patterns ...string) (*HostMatcher, error) {
+	m := &HostMatcher{
+		parser:   defaultParser.(*parser),
+		exact:    make(map[string]bool),
+		wildcard: make(map[string]bool),
+	}
+	for _, pattern := range patterns {
+		if err := m.Add(pattern); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Add adds a pattern to the matcher.
+// Add must not be called concurrently with Match.
+func (m *HostMatcher) Add(pattern string) error {
+	wildcard := strings.HasPrefix(pattern, "*.")
+	if wildcard {
+		pattern = pattern[2:]
+	}
+	h, err := m.parser.parseHost(inputSink(pattern), pattern, false)
+	if err != nil {
+		return err
+	}
+	if wildcard {
+		if h.Kind != DomainHost {
+			return fmt.Errorf("wildcard pattern *.%s is not a domain", pattern)
+		}
+		m.wildcard[h.key()] = true
+	} else {
+		m.exact[h.key()] = true
+	}
+	return nil
+}
+
+// Match returns true if host matches one of the patterns.
+// If host can not be parsed, false is returned.
+func (m *HostMatcher) Match(host string) bool {
+	h, err := m.parser.parseHost(inputSink(host), host, false)
+	if err != nil {
+		return false
+	}
+	return m.MatchHost(h)
+}
+
+// MatchHost returns true if the parsed host matches one of the patterns.
+func (m *HostMatcher) MatchHost(h *Host) bool {
+	key := h.key()
+	if m.exact[key] {
+		return true
+	}
+	if h.Kind != DomainHost {
+		return false
+	}
+	for i := strings.IndexByte(key, '.'); i >= 0; i = strings.IndexByte(key, '.') {
+		key = key[i+1:]
+		if m.wildcard[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// DomainMatch implements domain-matching from RFC 6265 section 5.1.3 (https://tools.ietf.org/html/rfc6265#section-5.1.3).
+//
+// Both cookieDomain and host are canonicalized by the host parser. A leading dot in cookieDomain is ignored as
+// described in section 5.2.3. The host domain-matches the cookie domain if they are identical, or if host is a
+// domain and the cookie domain is a suffix of host preceded by a dot. IP addresses only match identical addresses.
+// If cookieDomain or host can not be parsed, false is returned.
+func DomainMatch(cookieDomain, host string) bool {
+	cookieDomain = strings.TrimPrefix(cookieDomain, ".")
+	p := defaultParser.(*parser)
+	d, err := p.parseHost(inputSink(cookieDomain), cookieDomain, false)
+	if err != nil || d.isEmpty() {
+		return false
+	}
+	h, err := p.parseHost(inputSink(host), host, false)
+	if err != nil || h.isEmpty() {
+		return false
+	}
+	if d.Kind == h.Kind && d.key() == h.key() {
+		return true
+	}
+	if d.Kind != DomainHost || h.Kind != DomainHost {
+		return false
+	}
+	dk, hk := d.key(), h.key()
+	return strings.HasSuffix(hk, dk) && hk[len(hk)-len(dk)-1] == '.'
+}
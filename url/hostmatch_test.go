@@ -0,0 +1,108 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import "testing"
+
+func TestMatchHost(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		host    string
+		want    bool
+	}{
+		{"1", "example.com", "example.com", true},
+		{"2", "example.com", "EXAMPLE.COM.", true},
+		{"3", "example.com", "www.example.com", false},
+		{"4", "*.example.com", "www.example.com", true},
+		{"5", "*.example.com", "a.b.example.com", true},
+		{"6", "*.example.com", "example.com", false},
+		{"7", "*.example.com", "badexample.com", false},
+		{"8", "*.BÜCHER.example", "www.xn--bcher-kva.example.", true},
+		{"9", "127.0.0.1", "0x7f.1", true},
+		{"10", "[::1]", "[0:0::1]", true},
+		{"11", "*.127.0.0.1", "127.0.0.1", false},
+		{"12", "example.com", "exa mple.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchHost(tt.pattern, tt.host); got != tt.want {
+				t.Errorf("MatchHost(%v, %v) = %v, want %v", tt.pattern, tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHostMatcher(t *testing.T) {
+	m, err := NewHostMatcher("example.com", "*.example.org", "10.0.0.1")
+	if err != nil {
+		t.Fatalf("NewHostMatcher() error = %v", err)
+	}
+	tests := []struct {
+		name string
+		host string
+		want bool
+	}{
+		{"1", "example.com", true},
+		{"2", "www.example.com", false},
+		{"3", "www.example.org", true},
+		{"4", "example.org", false},
+		{"5", "10.0.0.1", true},
+		{"6", "167772161", true},
+		{"7", "example.net", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Match(tt.host); got != tt.want {
+				t.Errorf("Match(%v) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewHostMatcher("*.[::1]"); err == nil {
+		t.Errorf("NewHostMatcher(*.[::1]) error = nil, want error")
+	}
+}
+
+func TestDomainMatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		cookieDomain string
+		host         string
+		want         bool
+	}{
+		{"1", "example.com", "example.com", true},
+		{"2", "example.com", "www.example.com", true},
+		{"3", ".example.com", "www.example.com", true},
+		{"4", "Example.COM", "WWW.example.com", true},
+		{"5", "example.com", "badexample.com", false},
+		{"6", "www.example.com", "example.com", false},
+		{"7", "bücher.example", "www.xn--bcher-kva.example", true},
+		{"8", "127.0.0.1", "127.0.0.1", true},
+		{"9", "0.0.1", "127.0.0.1", false},
+		{"10", "0.1", "10.0.0.1", false},
+		{"11", "", "example.com", false},
+		{"12", "[::1]", "[::1]", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DomainMatch(tt.cookieDomain, tt.host); got != tt.want {
+				t.Errorf("DomainMatch(%v, %v) = %v, want %v", tt.cookieDomain, tt.host, got, tt.want)
+			}
+		})
+	}
+}
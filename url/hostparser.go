@@ -17,6 +17,7 @@
 package url
 
 import (
+	"context"
 	goerrors "errors"
 	"fmt"
 	"math"
@@ -24,76 +25,278 @@ import (
 	"strings"
 	"unicode/utf8"
 
-	"golang.org/x/net/idna"
-
 	"github.com/nlnwa/whatwg-url/errors"
+	"github.com/nlnwa/whatwg-url/idn"
+	"github.com/nlnwa/whatwg-url/percent"
 )
 
+// ParseHost parses a host string using the host parser (https://url.spec.whatwg.org/#host-parsing).
+// The input is parsed as the host of a URL with a special scheme.
+// The parser can be configured by opts in the same way as a URL parser.
+func ParseHost(input string, opts ...ParserOption) (*Host, error) {
+	p := NewParser(opts...).(*parser)
+	return p.parseHost(inputSink(input), input, false)
+}
+
+// ParseIPv4 parses a string using the IPv4 parser (https://url.spec.whatwg.org/#concept-ipv4-parser).
+// Numbers expressed using hexadecimal or octal digits and fewer than four parts are accepted as specified by the standard.
+func ParseIPv4(input string) (IPv4Addr, error) {
+	p := defaultParser.(*parser)
+	return p.parseIPv4(inputSink(input), input)
+}
+
+// ParseIPv6 parses a string using the IPv6 parser (https://url.spec.whatwg.org/#concept-ipv6-parser).
+// The input must not be enclosed in brackets.
+func ParseIPv6(input string) (IPv6Addr, error) {
+	p := defaultParser.(*parser)
+	return p.parseIPv6(inputSink(input), newInputString(input))
+}
+
+// errorSink receives the validation errors found by the host parser.
+// It is implemented by *Url, but the host parser can be used without a Url by using an inputSink.
+type errorSink interface {
+	// input returns the input reported in validation errors.
+	input() string
+
+	// addValidationError records a validation error.
+	addValidationError(err error)
+
+	// position returns the current position of the parser in the input.
+	// The second return value is false if the position is unknown.
+	position() (errors.Position, bool)
+}
+
+// inputSink is an errorSink for input which is not part of a Url. Validation errors are discarded.
+type inputSink string
+
+func (s inputSink) input() string {
+	return string(s)
+}
+
+func (s inputSink) addValidationError(error) {
+}
+
+func (s inputSink) position() (errors.Position, bool) {
+	return errors.Position{}, false
+}
+
+// hookUrl returns the Url passed to the host parser hooks.
+// When the host is not parsed as part of a Url, a Url with only the input set is returned.
+func (p *parser) hookUrl(s errorSink) *Url {
+	if u, ok := s.(*Url); ok {
+		return u
+	}
+	return &Url{inputUrl: s.input(), parser: p}
+}
+
+// isFileScheme returns true if the host is parsed as part of a Url with the file scheme.
+func isFileScheme(s errorSink) bool {
+	u, ok := s.(*Url)
+	return ok && u.scheme == "file"
+}
+
 // parseHost parses the host part of the input string.
-func (p *parser) parseHost(u *Url, parser *parser, input string, isNotSpecial bool) (string, error) {
+func (p *parser) parseHost(s errorSink, input string, isNotSpecial bool) (*Host, error) {
 	if p.opts.preParseHostFunc != nil {
-		input = p.opts.preParseHostFunc(u, input)
+		input = p.opts.preParseHostFunc(p.hookUrl(s), input)
+		// The host state only checks for a missing host before the function is called. Special urls, except
+		// file urls, must still have a host after it, or the serialized url would not parse again.
+		if input == "" && !isNotSpecial && !isFileScheme(s) {
+			if err := p.handleError(s, errors.HostMissing, true); err != nil {
+				return nil, err
+			}
+		}
 	}
 	if input == "" {
-		return "", nil
+		return &Host{}, nil
 	}
 	if input[0] == '[' {
 		if !strings.HasSuffix(input, "]") {
-			if err := p.handleError(u, errors.IPv6Unclosed, true); err != nil {
-				return "", err
+			if err := p.handleError(s, errors.IPv6Unclosed, true); err != nil {
+				return nil, err
 			}
 		}
 		input = strings.Trim(input, "[]")
-		return p.parseIPv6(u, newInputString(input))
+		var zone string
+		if p.opts.allowIPv6ZoneID {
+			if i := strings.Index(input, "%25"); i >= 0 {
+				var err error
+				if zone, err = p.parseZoneID(s, input[i+3:]); err != nil {
+					return nil, err
+				}
+				input = input[:i]
+			}
+		}
+		address, err := p.parseIPv6(s, newInputString(input))
+		if err != nil {
+			return nil, err
+		}
+		h := &Host{Kind: IPv6Host, IPv6: address, IPv6Style: p.opts.ipv6Style, Zone: zone}
+		if err := p.checkAddress(s, h); err != nil {
+			return nil, err
+		}
+		return h, nil
 	}
 	if isNotSpecial {
-		return p.parseOpaqueHost(u, input)
+		opaque, err := p.parseOpaqueHost(s, input)
+		if err != nil {
+			return nil, err
+		}
+		return &Host{Kind: OpaqueHost, Opaque: opaque}, nil
 	}
 
 	domain := p.DecodePercentEncoded(input)
 
 	if !utf8.ValidString(domain) {
 		if p.opts.laxHostParsing {
-			return percentEncodeString(input, HostPercentEncodeSet), nil
+			return &Host{Kind: DomainHost, Domain: domain, ASCII: percentEncodeString(input, laxHostPercentEncodeSet)}, nil
 		}
-		if err := p.handleErrorWithDescription(u, errors.DomainToASCII, true, "not a valid UTF-8 string"); err != nil {
-			return "", err
+		if err := p.handleErrorWithDescription(s, errors.DomainToASCII, true, "not a valid UTF-8 string"); err != nil {
+			return nil, err
 		}
 	}
 
-	asciiDomain, err := p.ToASCII(domain, false)
+	asciiDomain, err := p.ToASCII(domain, p.opts.strictIDNA)
 	if err != nil {
 		if p.opts.laxHostParsing {
-			return domain, nil
+			return newDomainHost(domain, domain), nil
 		}
-		if err := p.handleWrappedError(u, errors.DomainToASCII, true, err); err != nil {
-			return "", err
+		var labelErr *idn.LabelError
+		if goerrors.As(err, &labelErr) {
+			descr := fmt.Sprintf("label %q (index %d) violates %s", labelErr.Label, labelErr.Index, labelErr.Rule)
+			if err := p.handleWrappedErrorWithDescription(s, errors.DomainToASCII, true, descr, err); err != nil {
+				return nil, err
+			}
+		} else if err := p.handleWrappedError(s, errors.DomainToASCII, true, err); err != nil {
+			return nil, err
 		}
 	}
 	for _, c := range asciiDomain {
-		if ForbiddenDomainCodePoint.Test(uint(c)) {
+		if p.opts.forbiddenDomainCodePoints.Test(uint(c)) {
 			if p.opts.laxHostParsing {
-				return parser.PercentEncodeString(asciiDomain, HostPercentEncodeSet), nil
+				return &Host{Kind: DomainHost, Domain: domain, ASCII: p.PercentEncodeString(asciiDomain, laxHostPercentEncodeSet)}, nil
 			} else {
-				if err := p.handleErrorWithDescription(u, errors.DomainInvalidCodePoint, true, string(c)); err != nil {
-					return "", err
+				if err := p.handleErrorWithDescription(s, errors.DomainInvalidCodePoint, true, string(c)); err != nil {
+					return nil, err
 				}
 			}
 		}
 	}
 
-	if p.endsInANumber(u, asciiDomain) {
-		ipv4Host, err := p.parseIPv4(u, asciiDomain)
-		return ipv4Host, err
+	if p.endsInANumber(s, asciiDomain) {
+		ipv4Host, err := p.parseIPv4(s, asciiDomain)
+		if err != nil {
+			return nil, err
+		}
+		h := &Host{Kind: IPv4Host, IPv4: ipv4Host, IPv4Original: input}
+		if err := p.checkAddress(s, h); err != nil {
+			return nil, err
+		}
+		return h, nil
+	}
+
+	trailingDot := len(asciiDomain) > 1 && strings.HasSuffix(asciiDomain, ".")
+	if trailingDot && p.opts.stripTrailingDot {
+		asciiDomain = asciiDomain[:len(asciiDomain)-1]
+	}
+
+	if p.opts.verifyDNSLength {
+		if descr := checkDNSLength(asciiDomain); descr != "" {
+			if err := p.handleErrorWithDescription(s, errors.DomainTooLong, true, descr); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if p.opts.requireDottedHost && !isFileScheme(s) {
+		label := strings.TrimSuffix(asciiDomain, ".")
+		if !strings.Contains(label, ".") && !p.opts.singleLabelHostAllowList[label] {
+			if err := p.handleErrorWithDescription(s, errors.HostNotDotted, false, asciiDomain); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if p.opts.postParseHostFunc != nil {
-		asciiDomain = p.opts.postParseHostFunc(u, asciiDomain)
+		asciiDomain = p.opts.postParseHostFunc(p.hookUrl(s), asciiDomain)
 	}
-	return asciiDomain, nil
+	h := newDomainHost(domain, asciiDomain)
+	h.TrailingDot = trailingDot
+	return h, nil
+}
+
+// checkDNSLength returns a description of the violation if the domain has an empty label or a label longer than
+// 63 bytes, or is longer than 253 bytes, not counting a trailing dot. An empty string is returned if the domain is
+// valid.
+func checkDNSLength(domain string) string {
+	domain = strings.TrimSuffix(domain, ".")
+	if len(domain) > 253 {
+		return fmt.Sprintf("domain is %d bytes", len(domain))
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if label == "" {
+			return "domain has an empty label"
+		}
+		if len(label) > 63 {
+			return fmt.Sprintf("label %q is %d bytes", label, len(label))
+		}
+	}
+	return ""
+}
+
+// resolveHost calls the function set by WithResolveHostFunc for domains and IP addresses.
+func (p *parser) resolveHost(ctx context.Context, s errorSink, host *Host) error {
+	if p.opts.resolveHostFunc == nil {
+		return nil
+	}
+	var h string
+	switch host.Kind {
+	case DomainHost:
+		h = host.ASCII
+	case IPv4Host:
+		h = host.IPv4.String()
+	case IPv6Host:
+		h = host.IPv6.String()
+	default:
+		return nil
+	}
+	if err := p.opts.resolveHostFunc(ctx, h); err != nil {
+		return p.handleWrappedError(s, errors.HostResolution, true, err)
+	}
+	return nil
 }
 
-func (p *parser) endsInANumber(u *Url, input string) bool {
+// checkAddress rejects IP address hosts forbidden by WithForbidLoopback, WithForbidPrivateAddresses
+// and WithForbidAmbiguousIPv4.
+func (p *parser) checkAddress(s errorSink, h *Host) error {
+	if p.opts.forbidAmbiguousIPv4 && h.IsObfuscatedIPv4() {
+		if err := p.handleErrorWithDescription(s, errors.IPv4Ambiguous, true, h.IPv4Original); err != nil {
+			return err
+		}
+	}
+	if !p.opts.forbidLoopback && !p.opts.forbidPrivateAddresses {
+		return nil
+	}
+
+	var class string
+	switch {
+	case p.opts.forbidLoopback && h.IsLoopback():
+		class = "loopback"
+	case p.opts.forbidLoopback && h.IsUnspecified():
+		class = "unspecified"
+	case p.opts.forbidLoopback && h.IsThisNetwork():
+		class = "this network"
+	case p.opts.forbidPrivateAddresses && h.IsPrivate():
+		class = "private"
+	case p.opts.forbidPrivateAddresses && h.IsLinkLocal():
+		class = "link-local"
+	default:
+		return nil
+	}
+	return p.handleErrorWithDescription(s, errors.HostForbiddenAddress, true, class+" address "+h.String())
+}
+
+func (p *parser) endsInANumber(s errorSink, input string) bool {
 	parts := strings.Split(input, ".")
 	if parts[len(parts)-1] == "" {
 		if len(parts) == 1 {
@@ -105,15 +308,16 @@ func (p *parser) endsInANumber(u *Url, input string) bool {
 	if last != "" && containsOnly(last, ASCIIDigit) {
 		return true
 	}
-	if _, _, err := p.parseIPv4Number(u, last); err == nil || goerrors.Is(err, strconv.ErrRange) {
+	// This only checks if last is a number, so validation errors are not reported
+	if _, _, err := p.parseIPv4Number(inputSink(last), last); err == nil || goerrors.Is(err, strconv.ErrRange) {
 		return true
 	}
 	return false
 }
 
-func (p *parser) parseIPv4Number(u *Url, input string) (number int64, validationError bool, err error) {
+func (p *parser) parseIPv4Number(s errorSink, input string) (number int64, validationError bool, err error) {
 	if input == "" {
-		if err = p.handleError(u, errors.IPv4EmptyPart, true); err != nil {
+		if err = p.handleError(s, errors.IPv4EmptyPart, true); err != nil {
 			return
 		}
 	}
@@ -135,53 +339,53 @@ func (p *parser) parseIPv4Number(u *Url, input string) (number int64, validation
 	return
 }
 
-func (p *parser) parseIPv4(u *Url, input string) (string, error) {
+func (p *parser) parseIPv4(s errorSink, input string) (IPv4Addr, error) {
 	parts := strings.Split(input, ".")
 	if parts[len(parts)-1] == "" {
-		if err := p.handleError(u, errors.IPv4EmptyPart, false); err != nil {
-			return input, err
+		if err := p.handleError(s, errors.IPv4EmptyPart, false); err != nil {
+			return 0, err
 		}
 		if len(parts) > 1 {
 			parts = parts[:len(parts)-1]
 		}
 	}
 	if len(parts) > 4 {
-		if err := p.handleError(u, errors.IPv4TooManyParts, true); err != nil {
-			return input, err
+		if err := p.handleError(s, errors.IPv4TooManyParts, true); err != nil {
+			return 0, err
 		}
 	}
 	var numbers []int64
 	for _, part := range parts {
-		n, validationError, err := p.parseIPv4Number(u, part)
+		n, validationError, err := p.parseIPv4Number(s, part)
 		if err != nil {
-			if err := p.handleWrappedError(u, errors.IPv4NonNumericPart, true, err); err != nil {
-				return input, err
+			if err := p.handleWrappedError(s, errors.IPv4NonNumericPart, true, err); err != nil {
+				return 0, err
 			}
 		}
 		if validationError {
-			if err := p.handleError(u, errors.IPv4NonDecimalPart, false); err != nil {
-				return input, err
+			if err := p.handleError(s, errors.IPv4NonDecimalPart, false); err != nil {
+				return 0, err
 			}
 		}
 		numbers = append(numbers, n)
 	}
 	for _, n := range numbers {
 		if n > 255 {
-			if err := p.handleError(u, errors.IPv4OutOfRangePart, false); err != nil {
-				return input, err
+			if err := p.handleError(s, errors.IPv4OutOfRangePart, false); err != nil {
+				return 0, err
 			}
 		}
 	}
 	for _, n := range numbers[:len(numbers)-1] {
 		if n > 255 {
-			if err := p.handleError(u, errors.IPv4OutOfRangePart, true); err != nil {
-				return "", err
+			if err := p.handleError(s, errors.IPv4OutOfRangePart, true); err != nil {
+				return 0, err
 			}
 		}
 	}
 	if numbers[len(numbers)-1] >= int64(math.Pow(256, float64(5-len(numbers)))) {
-		if err := p.handleError(u, errors.IPv4OutOfRangePart, true); err != nil {
-			return "", err
+		if err := p.handleError(s, errors.IPv4OutOfRangePart, true); err != nil {
+			return 0, err
 		}
 	}
 	var ipv4 = IPv4Addr(numbers[len(numbers)-1])
@@ -189,20 +393,19 @@ func (p *parser) parseIPv4(u *Url, input string) (string, error) {
 	for counter, n := range numbers {
 		ipv4 += IPv4Addr(n * int64(math.Pow(256, float64(3-counter))))
 	}
-	u.isIPv4 = true
-	return ipv4.String(), nil
+	return ipv4, nil
 }
 
-func (p *parser) parseIPv6(u *Url, input *inputString) (string, error) {
-	address := &IPv6Addr{}
+func (p *parser) parseIPv6(s errorSink, input *inputString) (IPv6Addr, error) {
+	address := IPv6Addr{}
 	pieceIdx := 0
 	compress := -1
 
 	c := input.nextCodePoint()
 	if c == ':' {
 		if !input.remainingStartsWith(":") {
-			if err := p.handleError(u, errors.IPv6InvalidCompression, true); err != nil {
-				return "", err
+			if err := p.handleError(s, errors.IPv6InvalidCompression, true); err != nil {
+				return IPv6Addr{}, err
 			}
 		}
 		input.nextCodePoint()
@@ -212,14 +415,14 @@ func (p *parser) parseIPv6(u *Url, input *inputString) (string, error) {
 	}
 	for !input.eof {
 		if pieceIdx == 8 {
-			if err := p.handleError(u, errors.IPv6TooManyPieces, true); err != nil {
-				return "", err
+			if err := p.handleError(s, errors.IPv6TooManyPieces, true); err != nil {
+				return IPv6Addr{}, err
 			}
 		}
 		if c == ':' {
 			if compress >= 0 {
-				if err := p.handleError(u, errors.IPv6MultipleCompression, true); err != nil {
-					return "", err
+				if err := p.handleError(s, errors.IPv6MultipleCompression, true); err != nil {
+					return IPv6Addr{}, err
 				}
 			}
 			c = input.nextCodePoint()
@@ -239,15 +442,15 @@ func (p *parser) parseIPv6(u *Url, input *inputString) (string, error) {
 
 		if c == '.' {
 			if length == 0 {
-				if err := p.handleError(u, errors.IPv4InIPv6InvalidCodePoint, true); err != nil {
-					return "", err
+				if err := p.handleError(s, errors.IPv4InIPv6InvalidCodePoint, true); err != nil {
+					return IPv6Addr{}, err
 				}
 			}
 			input.rewind(length + 1)
 			c = input.nextCodePoint()
 			if pieceIdx > 6 {
-				if err := p.handleError(u, errors.IPv4InIPv6TooManyPieces, true); err != nil {
-					return "", err
+				if err := p.handleError(s, errors.IPv4InIPv6TooManyPieces, true); err != nil {
+					return IPv6Addr{}, err
 				}
 			}
 			numbersSeen := 0
@@ -257,14 +460,14 @@ func (p *parser) parseIPv6(u *Url, input *inputString) (string, error) {
 					if c == '.' && numbersSeen < 4 {
 						c = input.nextCodePoint()
 					} else {
-						if err := p.handleError(u, errors.IPv4InIPv6InvalidCodePoint, true); err != nil {
-							return "", err
+						if err := p.handleError(s, errors.IPv4InIPv6InvalidCodePoint, true); err != nil {
+							return IPv6Addr{}, err
 						}
 					}
 				}
 				if !ASCIIDigit.Test(uint(c)) {
-					if err := p.handleError(u, errors.IPv4InIPv6InvalidCodePoint, true); err != nil {
-						return "", err
+					if err := p.handleError(s, errors.IPv4InIPv6InvalidCodePoint, true); err != nil {
+						return IPv6Addr{}, err
 					}
 				}
 				for ASCIIDigit.Test(uint(c)) {
@@ -272,16 +475,16 @@ func (p *parser) parseIPv6(u *Url, input *inputString) (string, error) {
 					if ipv4Piece < 0 {
 						ipv4Piece = number
 					} else if ipv4Piece == 0 {
-						if err := p.handleError(u, errors.IPv4InIPv6InvalidCodePoint, true); err != nil {
-							return "", err
+						if err := p.handleError(s, errors.IPv4InIPv6InvalidCodePoint, true); err != nil {
+							return IPv6Addr{}, err
 						}
 					} else {
 						ipv4Piece = ipv4Piece*10 + number
 					}
 
 					if ipv4Piece > 255 {
-						if err := p.handleError(u, errors.IPv4InIPv6OutOfRangePart, true); err != nil {
-							return "", err
+						if err := p.handleError(s, errors.IPv4InIPv6OutOfRangePart, true); err != nil {
+							return IPv6Addr{}, err
 						}
 					}
 					c = input.nextCodePoint()
@@ -293,21 +496,21 @@ func (p *parser) parseIPv6(u *Url, input *inputString) (string, error) {
 				}
 			}
 			if numbersSeen != 4 {
-				if err := p.handleError(u, errors.IPv4InIPv6TooFewParts, true); err != nil {
-					return "", err
+				if err := p.handleError(s, errors.IPv4InIPv6TooFewParts, true); err != nil {
+					return IPv6Addr{}, err
 				}
 			}
 			break
 		} else if c == ':' {
 			c = input.nextCodePoint()
 			if input.eof {
-				if err := p.handleError(u, errors.IPv6InvalidCodePoint, true); err != nil {
-					return "", err
+				if err := p.handleError(s, errors.IPv6InvalidCodePoint, true); err != nil {
+					return IPv6Addr{}, err
 				}
 			}
 		} else if !input.eof {
-			if err := p.handleError(u, errors.IPv6InvalidCodePoint, true); err != nil {
-				return "", err
+			if err := p.handleError(s, errors.IPv6InvalidCodePoint, true); err != nil {
+				return IPv6Addr{}, err
 			}
 		}
 		address[pieceIdx] = uint16(value)
@@ -324,35 +527,60 @@ func (p *parser) parseIPv6(u *Url, input *inputString) (string, error) {
 			swaps--
 		}
 	} else if compress < 0 && pieceIdx != 8 {
-		if err := p.handleError(u, errors.IPv6TooFewPieces, true); err != nil {
+		if err := p.handleError(s, errors.IPv6TooFewPieces, true); err != nil {
+			return IPv6Addr{}, err
+		}
+	}
+	return address, nil
+}
+
+// parseZoneID parses an IPv6 zone identifier as defined in RFC 6874.
+// A zone identifier consists of unreserved characters and percent encoded octets.
+func (p *parser) parseZoneID(s errorSink, input string) (string, error) {
+	if input == "" {
+		if err := p.handleError(s, errors.IPv6InvalidZoneID, true); err != nil {
 			return "", err
 		}
 	}
-	u.isIPv6 = true
-	return "[" + address.String() + "]", nil
+	for i, c := range input {
+		if c == '%' {
+			if invalid, d := remainingIsInvalidPercentEncoded(input[i:]); invalid {
+				if err := p.handleErrorWithDescription(s, errors.IPv6InvalidZoneID, true, d); err != nil {
+					return "", err
+				}
+			}
+			continue
+		}
+		if !ASCIIAlphanumeric.Test(uint(c)) && c != '-' && c != '.' && c != '_' && c != '~' {
+			if err := p.handleErrorWithDescription(s, errors.IPv6InvalidZoneID, true, string(c)); err != nil {
+				return "", err
+			}
+		}
+	}
+	return p.DecodePercentEncoded(input), nil
 }
 
-func (p *parser) parseOpaqueHost(u *Url, input string) (string, error) {
+func (p *parser) parseOpaqueHost(s errorSink, input string) (string, error) {
 	output := ""
 	for i, c := range input {
-		if ForbiddenHostCodePoint.Test(uint(c)) {
+		if p.opts.forbiddenHostCodePoints.Test(uint(c)) {
 			if p.opts.laxHostParsing {
 				return input, nil
 			} else {
-				if err := p.handleErrorWithDescription(u, errors.HostInvalidCodePoint, true, string(c)); err != nil {
+				if err := p.handleErrorWithDescription(s, errors.HostInvalidCodePoint, true, string(c)); err != nil {
 					return "", err
 				}
 			}
 		}
 		if !isURLCodePoint(c) && c != '%' {
-			if err := p.handleErrorWithDescription(u, errors.InvalidURLUnit, false, string(c)); err != nil {
+			if err := p.handleErrorWithDescription(s, errors.InvalidURLUnit, false, string(c)); err != nil {
 				return "", err
 			}
 		}
 		if c == '%' {
-			invalidPercentEncoding, d := remainingIsInvalidPercentEncoded([]rune(input[i:]))
+			invalidPercentEncoding, d := remainingIsInvalidPercentEncoded(input[i:])
 			if invalidPercentEncoding {
-				if err := p.handleErrorWithDescription(u, errors.InvalidURLUnit, false, d); err != nil {
+				if err := p.handleErrorWithDescription(s, errors.InvalidURLUnit, false, d); err != nil {
 					return "", err
 				}
 			}
@@ -363,8 +591,33 @@ func (p *parser) parseOpaqueHost(u *Url, input string) (string, error) {
 	return output, nil
 }
 
+// IPv6Style selects how an IPv6 address is serialized.
+type IPv6Style int
+
+const (
+	// IPv6Compressed is the serialization defined by the standard, e.g. "2001:db8::1".
+	IPv6Compressed IPv6Style = iota
+	// IPv6Expanded is a fully expanded form with zero padded pieces, e.g. "2001:0db8:0000:0000:0000:0000:0000:0001".
+	IPv6Expanded
+)
+
 type IPv6Addr [8]uint16
 
+// Expanded returns the address with all eight pieces written as four lower case hex digits.
+// Unlike the compressed form, expanded addresses sort in numeric order.
+func (address *IPv6Addr) Expanded() string {
+	const hex = "0123456789abcdef"
+	b := make([]byte, 0, 39)
+	for pieceIdx := 0; pieceIdx < 8; pieceIdx++ {
+		if pieceIdx > 0 {
+			b = append(b, ':')
+		}
+		piece := address[pieceIdx]
+		b = append(b, hex[piece>>12], hex[piece>>8&0xf], hex[piece>>4&0xf], hex[piece&0xf])
+	}
+	return string(b)
+}
+
 func (address *IPv6Addr) String() string {
 	output := ""
 	compress := -1
@@ -425,24 +678,24 @@ func (address IPv4Addr) String() string {
 		strconv.Itoa(int(address&0xFF))
 }
 
-var idnaProfile = idna.New(
-	idna.MapForLookup(),
-	idna.BidiRule(),
-	idna.VerifyDNSLength(false),
-	idna.StrictDomainName(true),
-	idna.ValidateLabels(true),
-	idna.CheckHyphens(false),
-	idna.CheckJoiners(true),
-	idna.Transitional(false),
-)
-
 // ToASCII converts a string to ASCII using IDNA
 // https://url.spec.whatwg.org/#concept-domain-to-ascii
 func (p *parser) ToASCII(src string, beStrict bool) (string, error) {
 	if src == "" {
 		return "", nil
 	}
+	if p.opts.idnaCache == nil || beStrict {
+		return p.toASCII(src, beStrict)
+	}
+	if a, err, ok := p.opts.idnaCache.get(src); ok {
+		return a, err
+	}
+	a, err := p.toASCII(src, beStrict)
+	p.opts.idnaCache.add(src, a, err)
+	return a, err
+}
 
+func (p *parser) toASCII(src string, beStrict bool) (string, error) {
 	// If encoding is set, convert to Unicode
 	if p.opts.encodingOverride != nil {
 		if u, err := p.stringToUnicode(src); err == nil {
@@ -451,49 +704,11 @@ func (p *parser) ToASCII(src string, beStrict bool) (string, error) {
 	}
 
 	// Convert to punycode
-	a, err := idnaProfile.ToASCII(src)
-	if err != nil {
-		if !beStrict {
-			if containsOnlyASCIIOrMiscAndNoPunycode(src) {
-				return a, nil
-			}
-		}
-
-		if !p.opts.laxHostParsing {
-			return a, err
-		}
-	}
-	if a == "" {
-		return "", fmt.Errorf("idna toAscii returned empty string")
-	}
-	return a, nil
-}
-
-// containsOnlyASCIIOrMiscAndNoPunycode returns true if the string contains only ASCII characters or characters from Section 4.1.1 in UTS #46
-// and does not contain any labels starting with acePrefix (xn--)
-func containsOnlyASCIIOrMiscAndNoPunycode(s string) bool {
-	s = strings.ToLower(s)
-	p := 0
-	for _, r := range s {
-		if r >= utf8.RuneSelf && r != '\u2260' && r != '\u226e' && r != '\u226f' {
-			return false
-		}
-		switch {
-		case r == '.':
-			p = 0
-		case p == 0 && r == 'x':
-			p = 1
-		case p == 1 && r == 'n':
-			p = 2
-		case p == 2 && r == '-':
-			p = 3
-		case p == 3 && r == '-':
-			return false
-		default:
-			p = -1
-		}
+	a, err := idn.ToASCII(src, beStrict)
+	if err != nil && p.opts.laxHostParsing && a != "" {
+		return a, nil
 	}
-	return true
+	return a, err
 }
 
 func (p *parser) stringToUnicode(src string) (string, error) {
@@ -509,21 +724,5 @@ func (p *parser) stringToUnicode(src string) (string, error) {
 }
 
 func percentEncodeString(s string, tr *PercentEncodeSet) string {
-	sb := strings.Builder{}
-	for _, b := range []byte(s) {
-		sb.WriteString(percentEncodeByte(b, tr))
-	}
-	return sb.String()
-}
-
-func percentEncodeByte(b byte, tr *PercentEncodeSet) string {
-	if tr != nil && !tr.ByteShouldBeEncoded(b) {
-		return string(b)
-	}
-
-	percentEncoded := make([]byte, 3)
-	percentEncoded[0] = '%'
-	percentEncoded[1] = "0123456789ABCDEF"[b>>4]
-	percentEncoded[2] = "0123456789ABCDEF"[b&15]
-	return string(percentEncoded)
+	return string(percent.AppendEncode(make([]byte, 0, len(s)), s, tr))
 }
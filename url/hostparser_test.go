@@ -1,6 +1,15 @@
 package url
 
-import "testing"
+import (
+	"context"
+	goerrors "errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nlnwa/whatwg-url/errors"
+	"github.com/nlnwa/whatwg-url/idn"
+)
 
 func Test_parser_parseHost(t *testing.T) {
 	type args struct {
@@ -47,16 +56,513 @@ func Test_parser_parseHost(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p := &parser{opts: parserOptions{failOnValidationError: tt.failOnValidationError}}
+			opts := defaultParserOptions()
+			opts.failOnValidationError = tt.failOnValidationError
+			p := &parser{opts: opts}
 
-			got, err := p.parseHost(&Url{}, p, tt.args.input, tt.args.isNotSpecial)
+			got, err := p.parseHost(inputSink(tt.args.input), tt.args.input, tt.args.isNotSpecial)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("parseHost() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got != tt.want {
+			if err != nil {
+				return
+			}
+			if got.String() != tt.want {
 				t.Errorf("parseHost() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestParseHost(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantKind    HostKind
+		want        string
+		wantUnicode string
+		wantErr     bool
+	}{
+		{"1", "EXAMPLE.COM", DomainHost, "example.com", "example.com", false},
+		{"2", "faß.example", DomainHost, "xn--fa-hia.example", "faß.example", false},
+		{"3", "0x7f.1", IPv4Host, "127.0.0.1", "", false},
+		{"4", "[0:0::1]", IPv6Host, "[::1]", "", false},
+		{"5", "example^example", DomainHost, "", "", true},
+		{"6", "[::1", IPv6Host, "", "", true},
+		{"7", "", EmptyHost, "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHost(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseHost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.Kind != tt.wantKind {
+				t.Errorf("ParseHost() got kind = %v, want %v", got.Kind, tt.wantKind)
+			}
+			if got.String() != tt.want {
+				t.Errorf("ParseHost() got = %v, want %v", got, tt.want)
+			}
+			if got.Unicode != tt.wantUnicode {
+				t.Errorf("ParseHost() got Unicode = %v, want %v", got.Unicode, tt.wantUnicode)
+			}
+		})
+	}
+}
+
+func TestParseIPv4(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    IPv4Addr
+		wantErr bool
+	}{
+		{"1", "127.0.0.1", 0x7f000001, false},
+		{"2", "0xffffffff", 0xffffffff, false},
+		{"3", "3279880203", 0xc37f000b, false},
+		{"4", "0177.1", 0x7f000001, false},
+		{"5", "256.1.1.1", 0, true},
+		{"6", "1.2.3.4.5", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseIPv4(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseIPv4() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseIPv4() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIPv6(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    IPv6Addr
+		wantErr bool
+	}{
+		{"1", "::1", IPv6Addr{0, 0, 0, 0, 0, 0, 0, 1}, false},
+		{"2", "2001:db8::ff00:42:8329", IPv6Addr{0x2001, 0xdb8, 0, 0, 0, 0xff00, 0x42, 0x8329}, false},
+		{"3", "::ffff:192.168.0.1", IPv6Addr{0, 0, 0, 0, 0, 0xffff, 0xc0a8, 0x1}, false},
+		{"4", "1::2::3", IPv6Addr{}, true},
+		{"5", "[::1]", IPv6Addr{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseIPv6(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseIPv6() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseIPv6() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIPv6Addr_Expanded(t *testing.T) {
+	tests := []struct {
+		name    string
+		address IPv6Addr
+		want    string
+	}{
+		{"1", IPv6Addr{0, 0, 0, 0, 0, 0, 0, 1}, "0000:0000:0000:0000:0000:0000:0000:0001"},
+		{"2", IPv6Addr{0x2001, 0xdb8, 0, 0, 0, 0xff00, 0x42, 0x8329}, "2001:0db8:0000:0000:0000:ff00:0042:8329"},
+		{"3", IPv6Addr{0xffff, 0xffff, 0xffff, 0xffff, 0xffff, 0xffff, 0xffff, 0xffff}, "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.address.Expanded(); got != tt.want {
+				t.Errorf("Expanded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithIPv6Style(t *testing.T) {
+	tests := []struct {
+		name  string
+		style IPv6Style
+		input string
+		want  string
+	}{
+		{"1", IPv6Compressed, "http://[2001:DB8::1]:8080/", "http://[2001:db8::1]:8080/"},
+		{"2", IPv6Expanded, "http://[2001:DB8::1]:8080/", "http://[2001:0db8:0000:0000:0000:0000:0000:0001]:8080/"},
+		{"3", IPv6Expanded, "http://example.com/", "http://example.com/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := NewParser(WithIPv6Style(tt.style)).Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", tt.input, err)
+			}
+			if got := u.Href(false); got != tt.want {
+				t.Errorf("Href() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithAllowIPv6ZoneID(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		allow    bool
+		want     string
+		wantZone string
+		wantErr  bool
+	}{
+		{"1", "http://[fe80::1%25eth0]/", true, "http://[fe80::1%25eth0]/", "eth0", false},
+		{"2", "http://[fe80::1%25eth0]/", false, "", "", true},
+		{"3", "http://[fe80::1%25en%2530]:8080/", true, "http://[fe80::1%25en%2530]:8080/", "en%30", false},
+		{"4", "http://[fe80::1%25]/", true, "", "", true},
+		{"5", "http://[fe80::1%25eth/0]/", true, "", "", true},
+		{"6", "http://[fe80::1%eth0]/", true, "", "", true},
+		{"7", "http://[::1]/", true, "http://[::1]/", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []ParserOption
+			if tt.allow {
+				opts = append(opts, WithAllowIPv6ZoneID())
+			}
+			got, err := NewParser(opts...).Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("Parse(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+			if got.IPv6Zone() != tt.wantZone {
+				t.Errorf("IPv6Zone() = %v, want %v", got.IPv6Zone(), tt.wantZone)
+			}
+		})
+	}
+}
+
+func TestWithStripTrailingDot(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		strip           bool
+		want            string
+		wantTrailingDot bool
+	}{
+		{"1", "http://example.com./", true, "http://example.com/", true},
+		{"2", "http://example.com./", false, "http://example.com./", true},
+		{"3", "http://example.com/", true, "http://example.com/", false},
+		{"4", "http://127.0.0.1./", true, "http://127.0.0.1/", false},
+		{"5", "foo://example.com./", true, "foo://example.com./", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []ParserOption
+			if tt.strip {
+				opts = append(opts, WithStripTrailingDot())
+			}
+			got, err := NewParser(opts...).Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", tt.input, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Parse(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+			if got.HasTrailingDot() != tt.wantTrailingDot {
+				t.Errorf("HasTrailingDot() = %v, want %v", got.HasTrailingDot(), tt.wantTrailingDot)
+			}
+		})
+	}
+}
+
+func TestWithForbiddenCodePoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		opts    []ParserOption
+		want    string
+		wantErr bool
+	}{
+		{"1", "http://a_b.example/", nil, "http://a_b.example/", false},
+		{"2", "http://a_b.example/", []ParserOption{WithForbiddenDomainCodePoints(ForbiddenDomainCodePoint.Clone().Set('_'))}, "", true},
+		{"3", "foo://a|b/", nil, "", true},
+		{"4", "foo://a|b/", []ParserOption{WithForbiddenHostCodePoints(ForbiddenHostCodePoint.Clone().Clear('|'))}, "foo://a|b/", false},
+		{"5", "http://example.com/", []ParserOption{WithForbiddenDomainCodePoints(nil)}, "http://example.com/", false},
+		{"6", "http://exa%25mple.com/", []ParserOption{WithForbiddenDomainCodePoints(nil)}, "", true},
+		{"7", "foo://example.com/", []ParserOption{WithForbiddenHostCodePoints(nil)}, "foo://example.com/", false},
+		{"8", "foo://a|b/", []ParserOption{WithForbiddenHostCodePoints(nil)}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewParser(tt.opts...).Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				return
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("Parse(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithResolveHostFunc(t *testing.T) {
+	type ctxKey struct{}
+	var resolved []string
+	var ctxValue interface{}
+	p := NewParser(WithResolveHostFunc(func(ctx context.Context, host string) error {
+		resolved = append(resolved, host)
+		ctxValue = ctx.Value(ctxKey{})
+		if host == "blocked.example" {
+			return goerrors.New("blocked")
+		}
+		return nil
+	}))
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{"1", "http://example.com:8080/", []string{"example.com"}, false},
+		{"2", "http://[::1]/", []string{"::1"}, false},
+		{"3", "http://0x7f.1/", []string{"127.0.0.1"}, false},
+		{"4", "foo://example.com/", nil, false},
+		{"5", "file:///foo", nil, false},
+		{"6", "http://blocked.example/", []string{"blocked.example"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved = nil
+			ctx := context.WithValue(context.Background(), ctxKey{}, tt.name)
+			_, err := p.ParseContext(ctx, tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseContext(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err != nil && errors.Type(err) != errors.HostResolution {
+				t.Errorf("ParseContext(%v) error type = %v, want %v", tt.input, errors.Type(err), errors.HostResolution)
+			}
+			if !reflect.DeepEqual(resolved, tt.want) {
+				t.Errorf("resolved hosts = %v, want %v", resolved, tt.want)
+			}
+			if tt.want != nil && ctxValue != tt.name {
+				t.Errorf("context was not passed on to resolve function")
+			}
+		})
+	}
+}
+
+func TestWithPreParseHostFunc(t *testing.T) {
+	p := NewParser(WithPreParseHostFunc(func(u *Url, host string) string {
+		return strings.Trim(host, ".")
+	}))
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"1", "http://.example.com./", "http://example.com/", false},
+		{"2", "http://./", "", true},
+		{"3", "foo://./", "foo:///", false},
+		{"4", "file://./foo", "file:///foo", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err != nil {
+				if errors.Type(err) != errors.HostMissing {
+					t.Errorf("Parse(%v) error type = %v, want %v", tt.input, errors.Type(err), errors.HostMissing)
+				}
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("Parse(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithLaxHostParsing(t *testing.T) {
+	p := NewParser(WithLaxHostParsing())
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"1", "http://a%2Fb%3Cc/", "http://a%2Fb<c/"},
+		{"2", "http://a%3Fb%5E/", "http://a%3Fb^/"},
+		{"3", "http://a%3Ab^/x", "http://a%3Ab^/x"},
+		{"4", "http://a%5Bb^/", "http://a%5Bb^/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", tt.input, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Parse(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+			// The host must not end early when the serialized url is parsed again
+			again, err := p.Parse(got.String())
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", got, err)
+			}
+			if again.String() != tt.want {
+				t.Errorf("Parse(%v) = %v, want %v", got, again, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRequireDottedHost(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"1", "http://example.com/", false},
+		{"2", "http://intranet/", true},
+		{"3", "http://intranet./", true},
+		{"4", "http://localhost/", false},
+		{"5", "http://LocalHost:8080/", false},
+		{"6", "http://127.0.0.1/", false},
+		{"7", "http://[::1]/", false},
+		{"8", "foo://intranet/", false},
+		{"9", "file://server/share", false},
+	}
+	p := NewParser(WithRequireDottedHost("localhost"), WithFailOnValidationError())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := p.Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				return
+			}
+			if err != nil && errors.Type(err) != errors.HostNotDotted {
+				t.Errorf("Parse(%v) error type = %v, want %v", tt.input, errors.Type(err), errors.HostNotDotted)
+			}
+		})
+	}
+
+	u, err := NewParser(WithRequireDottedHost(), WithReportValidationErrors()).Parse("http://intranet/")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(u.ValidationErrors()) != 1 || errors.Type(u.ValidationErrors()[0]) != errors.HostNotDotted {
+		t.Errorf("ValidationErrors() = %v, want one error of type %v", u.ValidationErrors(), errors.HostNotDotted)
+	}
+}
+
+func TestParseHost_LabelError(t *testing.T) {
+	_, err := ParseHost("www.xn--a.example")
+	if err == nil {
+		t.Fatal("ParseHost() error = nil, want error")
+	}
+	if errors.Type(err) != errors.DomainToASCII {
+		t.Errorf("ParseHost() error type = %v, want %v", errors.Type(err), errors.DomainToASCII)
+	}
+	want := `label "xn--a" (index 1) violates ValidityCriteria`
+	if errors.Description(err) != want {
+		t.Errorf("ParseHost() error description = %v, want %v", errors.Description(err), want)
+	}
+	var labelErr *idn.LabelError
+	if !goerrors.As(err, &labelErr) || labelErr.Index != 1 {
+		t.Errorf("ParseHost() error = %v, want wrapped *idn.LabelError", err)
+	}
+}
+
+func TestWithForbidAddresses(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []ParserOption
+		input    string
+		wantType errors.ErrorType
+	}{
+		{"1", []ParserOption{WithForbidLoopback()}, "http://127.0.0.1/", errors.HostForbiddenAddress},
+		{"2", []ParserOption{WithForbidLoopback()}, "http://0x7f.1/", errors.HostForbiddenAddress},
+		{"3", []ParserOption{WithForbidLoopback()}, "http://0/", errors.HostForbiddenAddress},
+		{"4", []ParserOption{WithForbidLoopback()}, "http://[::ffff:7f00:1]/", errors.HostForbiddenAddress},
+		{"5", []ParserOption{WithForbidLoopback()}, "http://10.0.0.1/", ""},
+		{"6", []ParserOption{WithForbidPrivateAddresses()}, "http://10.0.0.1/", errors.HostForbiddenAddress},
+		{"7", []ParserOption{WithForbidPrivateAddresses()}, "http://169.254.169.254/", errors.HostForbiddenAddress},
+		{"8", []ParserOption{WithForbidPrivateAddresses()}, "http://[fe80::1]/", errors.HostForbiddenAddress},
+		{"9", []ParserOption{WithForbidPrivateAddresses()}, "http://127.0.0.1/", ""},
+		{"10", []ParserOption{WithForbidLoopback(), WithForbidPrivateAddresses()}, "http://example.com/", ""},
+		{"11", []ParserOption{WithForbidAmbiguousIPv4()}, "http://2130706433/", errors.IPv4Ambiguous},
+		{"12", []ParserOption{WithForbidAmbiguousIPv4()}, "http://0x7f.0.0.1/", errors.IPv4Ambiguous},
+		{"13", []ParserOption{WithForbidAmbiguousIPv4()}, "http://127.0.0.1/", ""},
+		{"14", []ParserOption{WithForbidAmbiguousIPv4()}, "http://127.0.0.1./", ""},
+		{"15", []ParserOption{WithForbidAmbiguousIPv4()}, "http://%31%32%37.0.0.1/", errors.IPv4Ambiguous},
+		{"16", []ParserOption{WithForbidAmbiguousIPv4()}, "http://0x7f.0.0.1./", errors.IPv4Ambiguous},
+		{"17", []ParserOption{WithForbidLoopback()}, "http://0.1.2.3/", errors.HostForbiddenAddress},
+		{"18", []ParserOption{WithForbidLoopback()}, "http://[::ffff:0.0.0.1]/", errors.HostForbiddenAddress},
+		{"19", []ParserOption{WithForbidLoopback()}, "http://1.0.0.0/", ""},
+		{"20", []ParserOption{WithForbidLoopback()}, "http://[::127.0.0.1]/", errors.HostForbiddenAddress},
+		{"21", []ParserOption{WithForbidLoopback()}, "http://[64:ff9b::7f00:1]/", errors.HostForbiddenAddress},
+		{"22", []ParserOption{WithForbidPrivateAddresses()}, "http://[64:ff9b::a9fe:a9fe]/", errors.HostForbiddenAddress},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParser(tt.opts...).Parse(tt.input)
+			if errors.Type(err) != tt.wantType {
+				t.Errorf("Parse(%v) error = %v, want error of type %q", tt.input, err, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestWithVerifyDNSLength(t *testing.T) {
+	label63 := strings.Repeat("a", 63)
+	label64 := strings.Repeat("a", 64)
+	domain253 := strings.Repeat(label63+".", 3) + strings.Repeat("b", 61)
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"1", "http://" + label63 + ".example/", false},
+		{"2", "http://" + label64 + ".example/", true},
+		{"3", "http://" + domain253 + "/", false},
+		{"4", "http://" + domain253 + "./", false},
+		{"5", "http://" + domain253 + "b/", true},
+		{"6", "http://" + strings.Repeat("\u00e6", 60) + ".example/", true},
+		{"7", "foo://" + label64 + "/", false},
+		{"8", "http://a..example/", true},
+		{"9", "http://.example/", true},
+		{"10", "http://example../", true},
+	}
+	p := NewParser(WithVerifyDNSLength())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := p.Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				return
+			}
+			if err != nil && errors.Type(err) != errors.DomainTooLong {
+				t.Errorf("Parse(%v) error type = %v, want %v", tt.input, errors.Type(err), errors.DomainTooLong)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// HostList is a list of hosts used to build a HostPolicy.
+type HostList struct {
+	// Hosts are exact hosts and "*." wildcard patterns, see MatchHost.
+	Hosts []string
+	// RegistrableDomains matches any host with one of these registrable domains, e.g. "example.co.uk" matches
+	// "example.co.uk" and "www.example.co.uk". The registrable domain is found with the public suffix list of the
+	// parser which parsed the url.
+	RegistrableDomains []string
+	// CIDRs matches IP addresses within one of these ranges, e.g. "10.0.0.0/8" or "fc00::/7". IPv4-mapped IPv6
+	// addresses are matched as IPv4 addresses.
+	CIDRs []string
+}
+
+// hostRules is a compiled HostList.
+type hostRules struct {
+	hosts       *HostMatcher
+	registrable map[string]bool
+	prefixes    []netip.Prefix
+}
+
+func newHostRules(list HostList) (*hostRules, error) {
+	hosts, err := NewHostMatcher(list.Hosts...)
+	if err != nil {
+		return nil, err
+	}
+	r := &hostRules{hosts: hosts, registrable: make(map[string]bool)}
+	for _, d := range list.RegistrableDomains {
+		h, err := hosts.parser.parseHost(inputSink(d), d, false)
+		if err != nil {
+			return nil, err
+		}
+		if h.Kind != DomainHost {
+			return nil, fmt.Errorf("registrable domain %s is not a domain", d)
+		}
+		r.registrable[h.key()] = true
+	}
+	for _, c := range list.CIDRs {
+		prefix, err := netip.ParsePrefix(c)
+		if err != nil {
+			return nil, err
+		}
+		r.prefixes = append(r.prefixes, prefix.Masked())
+	}
+	return r, nil
+}
+
+func (r *hostRules) isEmpty() bool {
+	return len(r.hosts.exact) == 0 && len(r.hosts.wildcard) == 0 && len(r.registrable) == 0 && len(r.prefixes) == 0
+}
+
+func (r *hostRules) match(u *Url) bool {
+	h := u.host
+	if r.hosts.MatchHost(h) {
+		return true
+	}
+	switch h.Kind {
+	case DomainHost:
+		if len(r.registrable) > 0 {
+			if d := strings.TrimSuffix(u.RegistrableDomain(), "."); d != "" && r.registrable[d] {
+				return true
+			}
+		}
+	case IPv4Host, IPv6Host:
+		if len(r.prefixes) > 0 {
+			addr := h.netipAddr()
+			for _, prefix := range r.prefixes {
+				if prefix.Contains(addr) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// netipAddr returns the IP address of an IPv4 or IPv6 host. IPv4-mapped IPv6 addresses are returned as IPv4
+// addresses.
+func (h *Host) netipAddr() netip.Addr {
+	if h.Kind == IPv4Host {
+		a := h.IPv4
+		return netip.AddrFrom4([4]byte{byte(a >> 24), byte(a >> 16), byte(a >> 8), byte(a)})
+	}
+	var b [16]byte
+	for i, piece := range h.IPv6 {
+		b[2*i] = byte(piece >> 8)
+		b[2*i+1] = byte(piece)
+	}
+	return netip.AddrFrom16(b).Unmap()
+}
+
+// HostDecision is the result of matching a url against a HostPolicy.
+type HostDecision int
+
+const (
+	// HostUnmatched means that the host matched neither the allow list nor the deny list.
+	HostUnmatched HostDecision = iota
+	// HostAllowed means that the host matched the allow list and not the deny list.
+	HostAllowed
+	// HostDenied means that the host matched the deny list.
+	HostDenied
+)
+
+func (d HostDecision) String() string {
+	switch d {
+	case HostAllowed:
+		return "allowed"
+	case HostDenied:
+		return "denied"
+	}
+	return "unmatched"
+}
+
+// HostPolicy decides whether urls are allowed based on their host, e.g. for filtering requests to internal
+// addresses, scoping a crawl or excluding sites. The hosts in the lists are normalized by the host parser when the
+// policy is created, so they are compared exactly as the parser would serialize them.
+//
+// The deny list takes precedence over the allow list.
+//
+// A HostPolicy is safe for concurrent use.
+type HostPolicy struct {
+	allow *hostRules
+	deny  *hostRules
+}
+
+// NewHostPolicy creates a HostPolicy from an allow list and a deny list.
+// An error is returned if a host, domain or CIDR range can not be parsed.
+func NewHostPolicy(allow, deny HostList) (*HostPolicy, error) {
+	a, err := newHostRules(allow)
+	if err != nil {
+		return nil, err
+	}
+	d, err := newHostRules(deny)
+	if err != nil {
+		return nil, err
+	}
+	return &HostPolicy{allow: a, deny: d}, nil
+}
+
+// Match matches the host of u against the deny list and the allow list. Urls without a host are unmatched.
+func (p *HostPolicy) Match(u *Url) HostDecision {
+	if u.host == nil {
+		return HostUnmatched
+	}
+	if p.deny.match(u) {
+		return HostDenied
+	}
+	if p.allow.match(u) {
+		return HostAllowed
+	}
+	return HostUnmatched
+}
+
+// Allowed returns true if u is allowed by the policy. A url is allowed if its host is not denied and either matches
+// the allow list or the allow list is empty.
+func (p *HostPolicy) Allowed(u *Url) bool {
+	switch p.Match(u) {
+	case HostAllowed:
+		return true
+	case HostUnmatched:
+		return p.allow.isEmpty()
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"testing"
+)
+
+func TestHostPolicy_Match(t *testing.T) {
+	policy, err := NewHostPolicy(
+		HostList{
+			Hosts:              []string{"example.com", "*.example.org"},
+			RegistrableDomains: []string{"BÜCHER.co.uk"},
+			CIDRs:              []string{"192.0.2.0/24", "2001:db8::/32"},
+		},
+		HostList{
+			Hosts: []string{"private.example.org"},
+			CIDRs: []string{"192.0.2.128/25"},
+		},
+	)
+	if err != nil {
+		t.Fatalf("NewHostPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		url         string
+		want        HostDecision
+		wantAllowed bool
+	}{
+		{"1", "http://example.com/", HostAllowed, true},
+		{"2", "http://EXAMPLE.com./", HostAllowed, true},
+		{"3", "http://www.example.com/", HostUnmatched, false},
+		{"4", "http://www.example.org/", HostAllowed, true},
+		{"5", "http://example.org/", HostUnmatched, false},
+		{"6", "http://private.example.org/", HostDenied, false},
+		{"7", "http://www.xn--bcher-kva.co.uk/", HostAllowed, true},
+		{"8", "http://bücher.co.uk/", HostAllowed, true},
+		{"9", "http://co.uk/", HostUnmatched, false},
+		{"10", "http://192.0.2.1/", HostAllowed, true},
+		{"11", "http://0xc0.0.2.1/", HostAllowed, true},
+		{"12", "http://192.0.2.200/", HostDenied, false},
+		{"13", "http://[2001:db8::1]/", HostAllowed, true},
+		{"14", "http://[::ffff:192.0.2.1]/", HostAllowed, true},
+		{"15", "http://[::ffff:192.0.2.130]/", HostDenied, false},
+		{"16", "http://198.51.100.1/", HostUnmatched, false},
+		{"17", "mailto:user@example.com", HostUnmatched, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := Parse(tt.url)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := policy.Match(u); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+			if got := policy.Allowed(u); got != tt.wantAllowed {
+				t.Errorf("Allowed() = %v, want %v", got, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestHostPolicy_EmptyAllowList(t *testing.T) {
+	policy, err := NewHostPolicy(HostList{}, HostList{CIDRs: []string{"127.0.0.0/8", "10.0.0.0/8", "::1/128"}})
+	if err != nil {
+		t.Fatalf("NewHostPolicy() error = %v", err)
+	}
+	tests := []struct {
+		name string
+		url  string
+		want bool
+	}{
+		{"1", "http://example.com/", true},
+		{"2", "http://127.0.0.1/", false},
+		{"3", "http://2130706433/", false},
+		{"4", "http://10.1.2.3/", false},
+		{"5", "http://[::1]/", false},
+		{"6", "file:///etc/passwd", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := Parse(tt.url)
+			if got := policy.Allowed(u); got != tt.want {
+				t.Errorf("Allowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewHostPolicy_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		list HostList
+	}{
+		{"1", HostList{Hosts: []string{"exa mple.com"}}},
+		{"2", HostList{Hosts: []string{"*.127.0.0.1"}}},
+		{"3", HostList{RegistrableDomains: []string{"127.0.0.1"}}},
+		{"4", HostList{CIDRs: []string{"10.0.0.0"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewHostPolicy(tt.list, HostList{}); err == nil {
+				t.Errorf("NewHostPolicy() error = nil, want error")
+			}
+			if _, err := NewHostPolicy(HostList{}, tt.list); err == nil {
+				t.Errorf("NewHostPolicy() error = nil, want error")
+			}
+		})
+	}
+}
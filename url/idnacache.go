@@ -0,0 +1,79 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"container/list"
+	"sync"
+)
+
+// idnaCache is a least recently used cache for domain to ASCII results.
+// It is safe for concurrent use.
+type idnaCache struct {
+	mu      sync.Mutex
+	size    int
+	ll      *list.List
+	entries map[string]*list.Element
+}
+
+type idnaCacheEntry struct {
+	key   string
+	ascii string
+	err   error
+}
+
+func newIDNACache(size int) *idnaCache {
+	return &idnaCache{
+		size:    size,
+		ll:      list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+func (c *idnaCache) get(key string) (ascii string, err error, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, hit := c.entries[key]; hit {
+		c.ll.MoveToFront(e)
+		entry := e.Value.(*idnaCacheEntry)
+		return entry.ascii, entry.err, true
+	}
+	return "", nil, false
+}
+
+func (c *idnaCache) add(key string, ascii string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, hit := c.entries[key]; hit {
+		c.ll.MoveToFront(e)
+		entry := e.Value.(*idnaCacheEntry)
+		entry.ascii, entry.err = ascii, err
+		return
+	}
+	c.entries[key] = c.ll.PushFront(&idnaCacheEntry{key: key, ascii: ascii, err: err})
+	if c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.entries, oldest.Value.(*idnaCacheEntry).key)
+	}
+}
+
+func (c *idnaCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_idnaCache(t *testing.T) {
+	c := newIDNACache(2)
+	c.add("a", "A", nil)
+	c.add("b", "B", nil)
+	if _, _, ok := c.get("a"); !ok {
+		t.Errorf("get(a) not found")
+	}
+	c.add("c", "C", fmt.Errorf("err"))
+	if _, _, ok := c.get("b"); ok {
+		t.Errorf("get(b) found, expected least recently used entry to be evicted")
+	}
+	if a, err, ok := c.get("c"); !ok || a != "C" || err == nil {
+		t.Errorf("get(c) = %v, %v, %v, want C, err, true", a, err, ok)
+	}
+	if c.len() != 2 {
+		t.Errorf("len() = %v, want 2", c.len())
+	}
+}
+
+func TestWithIDNACache(t *testing.T) {
+	p := NewParser(WithIDNACache(10))
+	for i := 0; i < 3; i++ {
+		u, err := p.Parse("http://BÜCHER.example/")
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if got, want := u.Hostname(), "xn--bcher-kva.example"; got != want {
+			t.Errorf("Hostname() = %v, want %v", got, want)
+		}
+		if _, err := p.Parse("http://a.b.c.xn--pokxncvks/"); err == nil {
+			t.Errorf("Parse() expected error")
+		}
+	}
+	if got := p.(*parser).opts.idnaCache.len(); got != 2 {
+		t.Errorf("cache len = %v, want 2", got)
+	}
+}
@@ -21,98 +21,137 @@ import (
 	"unicode/utf8"
 )
 
+// inputString iterates over the code points of a string. The code points are decoded from the UTF-8 bytes as they
+// are visited, so no []rune copy of the input is made. Invalid UTF-8 bytes are returned as utf8.RuneError, one byte
+// at a time, just like converting the string to []rune would do.
 type inputString struct {
-	s       string
-	runes   []rune
+	s string
+	// pointer is the index, counted in code points, of the current code point. It is -1 before the first call to
+	// nextCodePoint and might be greater than the number of code points when reading past the end of the input.
 	pointer int
+	// offset is the byte offset of the current code point, -1 before the first code point and len(s) at the end.
+	offset int
+	// width is the number of bytes of the current code point, 0 when not pointing at a code point.
+	width int
+	// overrun is the number of times nextCodePoint has been called after reaching the end of the input.
+	overrun int
 	eof     bool
-	length  int
 }
 
 func newInputString(s string) *inputString {
-	i := &inputString{runes: []rune(s), pointer: -1}
-	i.s = s
-	i.length = len(i.runes)
-	return i
+	return &inputString{s: s, pointer: -1, offset: -1}
 }
 
 func (i *inputString) nextCodePoint() rune {
 	i.pointer++
-	if i.pointer >= i.length {
+	if i.offset < 0 {
+		i.offset = 0
+	} else if i.offset < len(i.s) {
+		i.offset += i.width
+	} else {
+		i.overrun++
+	}
+	if i.offset >= len(i.s) {
+		i.width = 0
 		i.eof = true
 		return utf8.RuneError
 	}
-	r := i.runes[i.pointer]
-
+	r, w := utf8.DecodeRuneInString(i.s[i.offset:])
+	i.width = w
 	return r
 }
 
 func (i *inputString) currentIsInvalid() bool {
-	return i.runes[i.pointer] == utf8.RuneError
+	r, _ := utf8.DecodeRuneInString(i.s[i.offset:])
+	return r == utf8.RuneError
 }
 
 func (i *inputString) getCurrentAsByte() byte {
-	if i.pointer >= i.length {
+	if i.offset >= len(i.s) {
 		i.eof = true
 		return 0
 	}
-	var pos int
-	for j := 0; j < i.pointer; j++ {
-		pos += utf8.RuneLen(i.runes[j])
-	}
-	return i.s[pos]
+	return i.s[i.offset]
 }
 
 func (i *inputString) rewindLast() {
 	i.eof = false
 	i.pointer--
+	switch {
+	case i.overrun > 0:
+		i.overrun--
+	case i.offset <= 0:
+		i.offset = -1
+		i.width = 0
+	default:
+		_, w := utf8.DecodeLastRuneInString(i.s[:i.offset])
+		i.offset -= w
+		i.width = w
+	}
 }
 
 func (i *inputString) reset() {
 	i.pointer = -1
+	i.offset = -1
+	i.width = 0
+	i.overrun = 0
 	i.eof = false
 }
 
 func (i *inputString) rewind(length int) {
-	i.pointer -= length
-	i.eof = false
+	for ; length > 0; length-- {
+		i.rewindLast()
+	}
+}
+
+// length returns the number of code points in the input.
+func (i *inputString) length() int {
+	return utf8.RuneCountInString(i.s)
 }
 
 func (i *inputString) remainingFromPointer() string {
 	if i.eof {
 		return ""
 	}
-	return string(i.runes[i.pointer:])
+	if i.offset < 0 {
+		return i.s
+	}
+	return i.s[i.offset:]
 }
 
 func (i *inputString) remainingStartsWith(s string) bool {
 	if i.eof {
 		return false
 	}
-
-	return strings.HasPrefix(string(i.runes[i.pointer+1:]), s)
+	if i.offset < 0 {
+		return strings.HasPrefix(i.s, s)
+	}
+	return strings.HasPrefix(i.s[i.offset+i.width:], s)
 }
 
-// remainingIsInvalidPercentEncoded returns true if the first three characters in the rune array are not '%' followed by two hex digits.
+// remainingIsInvalidPercentEncoded returns true if the remaining input starts with '%' not followed by two hex digits.
 // If true, the second return value is the invalid percent encoded string.
 func (i *inputString) remainingIsInvalidPercentEncoded() (bool, string) {
-	return remainingIsInvalidPercentEncoded(i.runes[i.pointer:])
+	return remainingIsInvalidPercentEncoded(i.remainingFromPointer())
 }
 
-// remainingIsInvalidPercentEncoded returns true if the first three characters in the rune array are not '%' followed by two hex digits.
-// If true, the second return value is the invalid percent encoded string.
-func remainingIsInvalidPercentEncoded(runes []rune) (bool, string) {
-	if len(runes) >= 1 && runes[0] == '%' &&
-		(len(runes) < 3 || (!ASCIIHexDigit.Test(uint(runes[1])) || !ASCIIHexDigit.Test(uint(runes[2])))) {
-		l := 3
-		if len(runes) < 3 {
-			l = len(runes)
-		}
-		return true, string(runes[0:l])
+// remainingIsInvalidPercentEncoded returns true if s starts with '%' not followed by two hex digits.
+// If true, the second return value is the invalid percent encoded string, which is at most three code points long.
+func remainingIsInvalidPercentEncoded(s string) (bool, string) {
+	if len(s) == 0 || s[0] != '%' {
+		return false, ""
+	}
+	if len(s) >= 3 && ASCIIHexDigit.Test(uint(s[1])) && ASCIIHexDigit.Test(uint(s[2])) {
+		return false, ""
+	}
+	end := 0
+	for n := 0; n < 3 && end < len(s); n++ {
+		_, w := utf8.DecodeRuneInString(s[end:])
+		end += w
 	}
-	return false, ""
+	return true, s[:end]
 }
 
 func (i *inputString) String() string {
-	return string(i.runes)
+	return i.s
 }
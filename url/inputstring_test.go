@@ -0,0 +1,73 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestInputString(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"1", ""},
+		{"2", "http://example.com/"},
+		{"3", "http://bücher.example/ä/€/𝔘"},
+		{"4", "a\xffb\xe2\x82c\xf0\x9f"},
+		{"5", "�%zz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runes := []rune(tt.input)
+			in := newInputString(tt.input)
+			if in.length() != len(runes) {
+				t.Fatalf("length() = %v, want %v", in.length(), len(runes))
+			}
+			for i := 0; i <= len(runes)+1; i++ {
+				want := utf8.RuneError
+				if i < len(runes) {
+					want = runes[i]
+				}
+				if got := in.nextCodePoint(); got != want || in.pointer != i || in.eof != (i >= len(runes)) {
+					t.Fatalf("nextCodePoint() = %q at %d (eof %v), want %q at %d", got, in.pointer, in.eof, want, i)
+				}
+			}
+
+			// Rewind from past the end back to the start, checking each code point on the way
+			in.rewind(2)
+			for i := len(runes) - 1; i >= 0; i-- {
+				in.rewindLast()
+				if got := in.nextCodePoint(); got != runes[i] || in.pointer != i {
+					t.Fatalf("nextCodePoint() after rewind = %q at %d, want %q at %d", got, in.pointer, runes[i], i)
+				}
+				if got, want := in.remainingFromPointer(), string(runes[i:]); []rune(got)[0] != []rune(want)[0] {
+					t.Errorf("remainingFromPointer() = %q, want %q", got, want)
+				}
+				in.rewindLast()
+			}
+			if in.pointer != -1 {
+				t.Errorf("pointer = %d after rewinding to start, want -1", in.pointer)
+			}
+			in.reset()
+			if len(runes) > 0 && in.nextCodePoint() != runes[0] {
+				t.Errorf("nextCodePoint() after reset() did not return %q", runes[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+// The address classification follows the definitions used by net/netip, except that IPv6 addresses with an embedded
+// IPv4 address (IPv4-mapped, IPv4-compatible and NAT64 addresses) are classified by the embedded IPv4 address.
+
+// IsLoopback returns true if the address is in 127.0.0.0/8.
+func (address IPv4Addr) IsLoopback() bool {
+	return address>>24 == 127
+}
+
+// IsPrivate returns true if the address is in 10.0.0.0/8, 172.16.0.0/12 or 192.168.0.0/16 (RFC 1918).
+func (address IPv4Addr) IsPrivate() bool {
+	return address>>24 == 10 || address>>20 == 0xac1 || address>>16 == 0xc0a8
+}
+
+// IsLinkLocal returns true if the address is in 169.254.0.0/16.
+func (address IPv4Addr) IsLinkLocal() bool {
+	return address>>16 == 0xa9fe
+}
+
+// IsUnspecified returns true if the address is 0.0.0.0.
+func (address IPv4Addr) IsUnspecified() bool {
+	return address == 0
+}
+
+// IsThisNetwork returns true if the address is in 0.0.0.0/8, which means "this network" (RFC 1122).
+// Common network stacks connect to the local host when given such an address.
+func (address IPv4Addr) IsThisNetwork() bool {
+	return address>>24 == 0
+}
+
+// IPv4Mapped returns the embedded IPv4 address and true if the address is an IPv4-mapped IPv6 address (::ffff:a.b.c.d).
+func (address *IPv6Addr) IPv4Mapped() (IPv4Addr, bool) {
+	for i := 0; i < 5; i++ {
+		if address[i] != 0 {
+			return 0, false
+		}
+	}
+	if address[5] != 0xffff {
+		return 0, false
+	}
+	return IPv4Addr(address[6])<<16 | IPv4Addr(address[7]), true
+}
+
+// embeddedIPv4 returns the embedded IPv4 address and true if the address is an IPv4-mapped address (::ffff:a.b.c.d),
+// an IPv4-compatible address (::a.b.c.d, except :: and ::1) or a NAT64 address (64:ff9b::a.b.c.d, RFC 6052).
+// Common network stacks connect to the embedded IPv4 address for all of them.
+func (address *IPv6Addr) embeddedIPv4() (IPv4Addr, bool) {
+	if v4, ok := address.IPv4Mapped(); ok {
+		return v4, true
+	}
+	for i := 2; i < 6; i++ {
+		if address[i] != 0 {
+			return 0, false
+		}
+	}
+	v4 := IPv4Addr(address[6])<<16 | IPv4Addr(address[7])
+	switch {
+	case address[0] == 0 && address[1] == 0:
+		return v4, v4 > 1
+	case address[0] == 0x64 && address[1] == 0xff9b:
+		return v4, true
+	}
+	return 0, false
+}
+
+// IsLoopback returns true if the address is ::1.
+func (address *IPv6Addr) IsLoopback() bool {
+	if v4, ok := address.embeddedIPv4(); ok {
+		return v4.IsLoopback()
+	}
+	return *address == IPv6Addr{0, 0, 0, 0, 0, 0, 0, 1}
+}
+
+// IsPrivate returns true if the address is in fc00::/7 (RFC 4193).
+func (address *IPv6Addr) IsPrivate() bool {
+	if v4, ok := address.embeddedIPv4(); ok {
+		return v4.IsPrivate()
+	}
+	return address[0]&0xfe00 == 0xfc00
+}
+
+// IsLinkLocal returns true if the address is in fe80::/10.
+func (address *IPv6Addr) IsLinkLocal() bool {
+	if v4, ok := address.embeddedIPv4(); ok {
+		return v4.IsLinkLocal()
+	}
+	return address[0]&0xffc0 == 0xfe80
+}
+
+// IsUnspecified returns true if the address is ::.
+func (address *IPv6Addr) IsUnspecified() bool {
+	if v4, ok := address.embeddedIPv4(); ok {
+		return v4.IsUnspecified()
+	}
+	return *address == IPv6Addr{}
+}
+
+// IsThisNetwork returns true if the address has an embedded IPv4 address in 0.0.0.0/8.
+func (address *IPv6Addr) IsThisNetwork() bool {
+	v4, ok := address.embeddedIPv4()
+	return ok && v4.IsThisNetwork()
+}
+
+// IsLoopback returns true if the host is a loopback IP address. Domains are never classified.
+func (h *Host) IsLoopback() bool {
+	switch h.Kind {
+	case IPv4Host:
+		return h.IPv4.IsLoopback()
+	case IPv6Host:
+		return h.IPv6.IsLoopback()
+	}
+	return false
+}
+
+// IsPrivate returns true if the host is a private IP address. Domains are never classified.
+func (h *Host) IsPrivate() bool {
+	switch h.Kind {
+	case IPv4Host:
+		return h.IPv4.IsPrivate()
+	case IPv6Host:
+		return h.IPv6.IsPrivate()
+	}
+	return false
+}
+
+// IsLinkLocal returns true if the host is a link-local IP address. Domains are never classified.
+func (h *Host) IsLinkLocal() bool {
+	switch h.Kind {
+	case IPv4Host:
+		return h.IPv4.IsLinkLocal()
+	case IPv6Host:
+		return h.IPv6.IsLinkLocal()
+	}
+	return false
+}
+
+// IsUnspecified returns true if the host is the unspecified IP address. Domains are never classified.
+func (h *Host) IsUnspecified() bool {
+	switch h.Kind {
+	case IPv4Host:
+		return h.IPv4.IsUnspecified()
+	case IPv6Host:
+		return h.IPv6.IsUnspecified()
+	}
+	return false
+}
+
+// IsThisNetwork returns true if the host is an IPv4 address in 0.0.0.0/8. Domains are never classified.
+func (h *Host) IsThisNetwork() bool {
+	switch h.Kind {
+	case IPv4Host:
+		return h.IPv4.IsThisNetwork()
+	case IPv6Host:
+		return h.IPv6.IsThisNetwork()
+	}
+	return false
+}
+
+// IsLoopback returns true if the host is a loopback IP address.
+// The classification is done on the parsed address, so "http://0x7f.1/" and "http://2130706433/" are loopback.
+func (u *Url) IsLoopback() bool {
+	return u.host != nil && u.host.IsLoopback()
+}
+
+// IsPrivate returns true if the host is a private IP address.
+func (u *Url) IsPrivate() bool {
+	return u.host != nil && u.host.IsPrivate()
+}
+
+// IsLinkLocal returns true if the host is a link-local IP address.
+func (u *Url) IsLinkLocal() bool {
+	return u.host != nil && u.host.IsLinkLocal()
+}
+
+// IsUnspecified returns true if the host is the unspecified IP address (e.g. "http://0/" or "http://[::]/").
+func (u *Url) IsUnspecified() bool {
+	return u.host != nil && u.host.IsUnspecified()
+}
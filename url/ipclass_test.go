@@ -0,0 +1,130 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import "testing"
+
+func TestIPAddr_Classification(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		wantLoopback    bool
+		wantPrivate     bool
+		wantLinkLocal   bool
+		wantUnspecified bool
+	}{
+		{"1", "127.0.0.1", true, false, false, false},
+		{"2", "127.255.255.254", true, false, false, false},
+		{"3", "10.1.2.3", false, true, false, false},
+		{"4", "172.16.0.1", false, true, false, false},
+		{"5", "172.32.0.1", false, false, false, false},
+		{"6", "192.168.1.1", false, true, false, false},
+		{"7", "169.254.169.254", false, false, true, false},
+		{"8", "0.0.0.0", false, false, false, true},
+		{"9", "8.8.8.8", false, false, false, false},
+		{"10", "[::1]", true, false, false, false},
+		{"11", "[::]", false, false, false, true},
+		{"12", "[fd00::1]", false, true, false, false},
+		{"13", "[fe80::1]", false, false, true, false},
+		{"14", "[::ffff:127.0.0.1]", true, false, false, false},
+		{"15", "[::ffff:192.168.0.1]", false, true, false, false},
+		{"16", "[2001:db8::1]", false, false, false, false},
+		{"17", "[::127.0.0.1]", true, false, false, false},
+		{"18", "[::10.0.0.1]", false, true, false, false},
+		{"19", "[::169.254.169.254]", false, false, true, false},
+		{"20", "[64:ff9b::7f00:1]", true, false, false, false},
+		{"21", "[64:ff9b::192.168.0.1]", false, true, false, false},
+		{"22", "[64:ff9b::a9fe:a9fe]", false, false, true, false},
+		{"23", "[64:ff9b::8.8.8.8]", false, false, false, false},
+		{"24", "[64:ff9b:1::7f00:1]", false, false, false, false},
+		{"25", "[::2]", false, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := ParseHost(tt.input)
+			if err != nil {
+				t.Fatalf("ParseHost(%v) error = %v", tt.input, err)
+			}
+			loopback, private, linkLocal, unspecified := h.IsLoopback(), h.IsPrivate(), h.IsLinkLocal(), h.IsUnspecified()
+			if loopback != tt.wantLoopback || private != tt.wantPrivate || linkLocal != tt.wantLinkLocal || unspecified != tt.wantUnspecified {
+				t.Errorf("classification of %v = %v %v %v %v, want %v %v %v %v", tt.input,
+					loopback, private, linkLocal, unspecified,
+					tt.wantLoopback, tt.wantPrivate, tt.wantLinkLocal, tt.wantUnspecified)
+			}
+		})
+	}
+}
+
+func TestHost_IsThisNetwork(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{"1", "0.0.0.0", true},
+		{"2", "0.255.1.2", true},
+		{"3", "1.0.0.0", false},
+		{"4", "[::ffff:0.1.2.3]", true},
+		{"5", "[::]", false},
+		{"6", "example.com", false},
+		{"7", "[::1]", false},
+		{"8", "[64:ff9b::1]", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := ParseHost(tt.input)
+			if err != nil {
+				t.Fatalf("ParseHost(%v) error = %v", tt.input, err)
+			}
+			if got := h.IsThisNetwork(); got != tt.want {
+				t.Errorf("IsThisNetwork() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUrl_IsLoopback(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		wantLoopback    bool
+		wantUnspecified bool
+	}{
+		{"1", "http://127.0.0.1/", true, false},
+		{"2", "http://0x7f.1/", true, false},
+		{"3", "http://2130706433/", true, false},
+		{"4", "http://0/", false, true},
+		{"5", "http://[::1]:8080/", true, false},
+		{"6", "http://localhost/", false, false},
+		{"7", "mailto:user@127.0.0.1", false, false},
+		{"8", "foo://127.0.0.1/", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", tt.input, err)
+			}
+			if got := u.IsLoopback(); got != tt.wantLoopback {
+				t.Errorf("IsLoopback() = %v, want %v", got, tt.wantLoopback)
+			}
+			if got := u.IsUnspecified(); got != tt.wantUnspecified {
+				t.Errorf("IsUnspecified() = %v, want %v", got, tt.wantUnspecified)
+			}
+		})
+	}
+}
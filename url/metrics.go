@@ -0,0 +1,73 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"time"
+
+	"github.com/nlnwa/whatwg-url/errors"
+)
+
+// MetricsSink receives metrics from a parser. Set it with WithMetricsSink to instrument all parsing done by a parser,
+// e.g. with Prometheus or OpenTelemetry, without wrapping every call site.
+//
+// The methods are called once for every url parsed by Parse, ParseBytes, ParseInto, ParseContext, ParseRef and
+// Url.Parse (ParseRef parses both the base and the reference). Setters are not counted. The methods might be called
+// concurrently and should return quickly since they are called while parsing.
+//
+// This API is EXPERIMENTAL.
+type MetricsSink interface {
+	// IncParses is called for every parse, successful or not.
+	IncParses()
+	// IncFailures is called for every failed parse with the type of the error. The type is empty if the error has
+	// no error type, e.g. when the function set by WithResolveHostFunc fails.
+	IncFailures(errorType errors.ErrorType)
+	// ObserveDuration is called for every parse with the time it took.
+	ObserveDuration(d time.Duration)
+}
+
+// WithMetricsSink sets a MetricsSink which receives metrics for every url parsed. Nil disables metrics, which is
+// the default.
+//
+// This API is EXPERIMENTAL.
+func WithMetricsSink(sink MetricsSink) ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.metricsSink = sink
+	})
+}
+
+// observeParse reports a parse which started at start to the metrics sink. It is meant to be deferred, so err is
+// a pointer to the named result of the parse function.
+func (p *parser) observeParse(start time.Time, err *error) {
+	sink := p.opts.metricsSink
+	sink.IncParses()
+	if *err != nil {
+		sink.IncFailures(failureType(*err))
+	}
+	sink.ObserveDuration(time.Since(start))
+}
+
+// failureType returns the error type of err. For joined validation errors, the last error is the one which made
+// the parse fail.
+func failureType(err error) errors.ErrorType {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		if errs := joined.Unwrap(); len(errs) > 0 {
+			return errors.Type(errs[len(errs)-1])
+		}
+	}
+	return errors.Type(err)
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nlnwa/whatwg-url/errors"
+)
+
+type testMetricsSink struct {
+	mu        sync.Mutex
+	parses    int
+	failures  []errors.ErrorType
+	durations int
+}
+
+func (s *testMetricsSink) IncParses() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parses++
+}
+
+func (s *testMetricsSink) IncFailures(errorType errors.ErrorType) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, errorType)
+}
+
+func (s *testMetricsSink) ObserveDuration(time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.durations++
+}
+
+func TestWithMetricsSink(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []ParserOption
+		parse        func(p Parser)
+		wantParses   int
+		wantFailures []errors.ErrorType
+	}{
+		{"1", nil, func(p Parser) { _, _ = p.Parse("http://example.com/") }, 1, nil},
+		{"2", nil, func(p Parser) { _, _ = p.Parse("http://example.com/a/../b") }, 1, nil},
+		{"3", nil, func(p Parser) { _, _ = p.Parse("http://[::1") }, 1, []errors.ErrorType{errors.IPv6Unclosed}},
+		{"4", nil, func(p Parser) { _, _ = p.ParseBytes([]byte("http://example.com/")) }, 1, nil},
+		{"5", nil, func(p Parser) { _ = p.ParseInto("http://example.com:99999/", &Url{}) }, 1,
+			[]errors.ErrorType{errors.PortOutOfRange}},
+		{"6", nil, func(p Parser) { _, _ = p.ParseRef("http://example.com/a", "b") }, 2, nil},
+		{"7", nil, func(p Parser) {
+			u, _ := p.Parse("http://example.com/")
+			u.SetPathname("a")
+			u.SetHost("example.org")
+		}, 1, nil},
+		{"8", []ParserOption{WithFailOnValidationError(), WithJoinValidationErrors()},
+			func(p Parser) { _, _ = p.Parse("http://user@example.com:99999/") }, 1,
+			[]errors.ErrorType{errors.InvalidCredentials}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &testMetricsSink{}
+			tt.parse(NewParser(append(tt.opts, WithMetricsSink(sink))...))
+			if sink.parses != tt.wantParses {
+				t.Errorf("IncParses() called %v times, want %v", sink.parses, tt.wantParses)
+			}
+			if sink.durations != tt.wantParses {
+				t.Errorf("ObserveDuration() called %v times, want %v", sink.durations, tt.wantParses)
+			}
+			if !reflect.DeepEqual(sink.failures, tt.wantFailures) {
+				t.Errorf("IncFailures() called with %v, want %v", sink.failures, tt.wantFailures)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"fmt"
+	neturl "net/url"
+	"strings"
+
+	"github.com/nlnwa/whatwg-url/percent"
+)
+
+// NetURLDifference is a component which this package and net/url interpret differently.
+//
+// This API is EXPERIMENTAL.
+type NetURLDifference struct {
+	// Component is the name of the component: scheme, username, password, host, port, path, query or fragment.
+	Component string
+	// WHATWG is the component as parsed by this package.
+	WHATWG string
+	// NetURL is the component as parsed by net/url.
+	NetURL string
+}
+
+func (d NetURLDifference) String() string {
+	return fmt.Sprintf("%s: %q (WHATWG) != %q (net/url)", d.Component, d.WHATWG, d.NetURL)
+}
+
+// NetURLReport is the result of CompareNetURL.
+//
+// This API is EXPERIMENTAL.
+type NetURLReport struct {
+	Input string
+	// WHATWGErr and NetURLErr are the errors from parsing Input, if any.
+	WHATWGErr error
+	NetURLErr error
+	// Differences are the components which differ. They are only compared when both parsers accept the input.
+	Differences []NetURLDifference
+}
+
+// Divergent returns true if one parser accepted the input and the other did not, or if they disagree on a component.
+func (r NetURLReport) Divergent() bool {
+	return (r.WHATWGErr == nil) != (r.NetURLErr == nil) || len(r.Differences) > 0
+}
+
+// CompareNetURL parses input with p and with net/url's Parse and reports the components they interpret differently.
+// If p is nil, the default parser is used. Such differences are what request smuggling and server-side request
+// forgery attacks exploit when a url is checked with one parser and fetched with another, e.g. a host of
+// "0x7f.1" which net/url keeps as is, but which is the IPv4 address 127.0.0.1.
+//
+// The components are compared in percent-decoded form, and default ports and empty paths of special urls are
+// normalized, so differences only in encoding or serialization are not reported.
+//
+// This API is EXPERIMENTAL.
+func CompareNetURL(p Parser, input string) NetURLReport {
+	if p == nil {
+		p = defaultParser
+	}
+	r := NetURLReport{Input: input}
+	var u *Url
+	var n *neturl.URL
+	u, r.WHATWGErr = p.Parse(input)
+	n, r.NetURLErr = neturl.Parse(input)
+	if r.WHATWGErr != nil || r.NetURLErr != nil {
+		return r
+	}
+
+	add := func(component, whatwg, netURL string) {
+		if whatwg != netURL {
+			r.Differences = append(r.Differences, NetURLDifference{Component: component, WHATWG: whatwg, NetURL: netURL})
+		}
+	}
+
+	scheme := strings.ToLower(n.Scheme)
+	add("scheme", u.Scheme(), scheme)
+
+	var username, password string
+	if n.User != nil {
+		username = n.User.Username()
+		password, _ = n.User.Password()
+	}
+	add("username", percent.Decode(u.Username()), username)
+	add("password", percent.Decode(u.Password()), password)
+
+	host := u.Hostname()
+	if u.host != nil && u.host.Kind == IPv6Host {
+		host = strings.Trim(host, "[]")
+	}
+	add("host", percent.Decode(host), strings.ToLower(n.Hostname()))
+
+	defaultPort, _ := u.parser.opts.specialScheme(u.Scheme())
+	port, netPort := u.Port(), n.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	if netPort == "" {
+		netPort, _ = u.parser.opts.specialScheme(scheme)
+	}
+	add("port", port, netPort)
+
+	path := n.Path
+	if n.Opaque != "" {
+		path, _ = neturl.PathUnescape(n.Opaque)
+	}
+	if path == "" && u.IsSpecialScheme() {
+		path = "/"
+	}
+	add("path", percent.Decode(u.Pathname()), path)
+
+	query, err := neturl.PathUnescape(n.RawQuery)
+	if err != nil {
+		query = n.RawQuery
+	}
+	add("query", percent.Decode(u.Query()), query)
+	add("fragment", percent.Decode(u.Fragment()), n.Fragment)
+	return r
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareNetURL(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		wantDivergent bool
+		wantDiffs     []NetURLDifference
+	}{
+		{"1", "http://example.com/a?b#c", false, nil},
+		{"2", "HTTP://EXAMPLE.com:80", false, nil},
+		{"3", "http://0x7f.1/", true, []NetURLDifference{{"host", "127.0.0.1", "0x7f.1"}}},
+		{"4", "http://example.com/a/../b", true, []NetURLDifference{{"path", "/b", "/a/../b"}}},
+		{"5", "http://example.com/a\\b", true, []NetURLDifference{{"path", "/a/b", "/a\\b"}}},
+		{"6", "http://example.com\\a", true, nil},
+		{"7", "http://example.com:8080/a b", false, nil},
+		{"8", "http://[::1]:080/", true, []NetURLDifference{{"port", "80", "080"}}},
+		{"9", "http://exa mple.com/", false, nil},
+		{"10", "http://example.com:99999/", true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := CompareNetURL(nil, tt.input)
+			if r.Divergent() != tt.wantDivergent {
+				t.Errorf("CompareNetURL(%v).Divergent() = %v, want %v (%+v)", tt.input, r.Divergent(), tt.wantDivergent, r)
+			}
+			if !reflect.DeepEqual(r.Differences, tt.wantDiffs) {
+				t.Errorf("CompareNetURL(%v).Differences = %v, want %v", tt.input, r.Differences, tt.wantDiffs)
+			}
+		})
+	}
+}
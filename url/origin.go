@@ -0,0 +1,160 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+// Origin is the origin of a url as defined by the [HTML Standard]. An origin is either a tuple of scheme, host, port
+// and domain, or an opaque origin. Opaque origins are only same origin with themselves, so two calls to
+// ParsedOrigin for the same url with an opaque origin return origins which are not same origin.
+//
+// [HTML Standard]: https://html.spec.whatwg.org/multipage/browsers.html#concept-origin
+type Origin struct {
+	scheme string
+	host   *Host
+	port   *string
+	domain *string
+	// opaque is non-nil for opaque origins. Its address identifies the origin.
+	opaque *opaqueOrigin
+}
+
+// opaqueOrigin must not be zero sized since distinct zero sized values may share the same address.
+type opaqueOrigin struct {
+	_ byte
+}
+
+// NewOpaqueOrigin returns a new opaque origin which is only same origin with itself.
+func NewOpaqueOrigin() *Origin {
+	return &Origin{opaque: &opaqueOrigin{}}
+}
+
+// ParsedOrigin returns the origin of the url.
+//
+// The origin of a blob: url is the origin of the url in its path if that url is http or https. Urls with the
+// schemes ftp, http, https, ws and wss have a tuple origin. All other urls, including file: urls, get a new opaque
+// origin.
+//
+// See: https://url.spec.whatwg.org/#concept-url-origin
+func (u *Url) ParsedOrigin() *Origin {
+	switch u.scheme {
+	case "blob":
+		return u.blobOrigin()
+	case "ftp", "http", "https", "ws", "wss":
+		o := &Origin{scheme: u.scheme, host: u.ParsedHost()}
+		if u.port != nil {
+			port := *u.port
+			o.port = &port
+		}
+		return o
+	}
+	return NewOpaqueOrigin()
+}
+
+// Origin implements WHATWG url api (https://url.spec.whatwg.org/#dom-url-origin)
+func (u *Url) Origin() string {
+	return u.ParsedOrigin().Serialize()
+}
+
+// IsOpaque returns true if the origin is an opaque origin.
+func (o *Origin) IsOpaque() bool {
+	return o.opaque != nil
+}
+
+// Scheme returns the scheme of a tuple origin or the empty string for an opaque origin.
+func (o *Origin) Scheme() string {
+	return o.scheme
+}
+
+// Host returns a copy of the host of a tuple origin or nil for an opaque origin.
+func (o *Origin) Host() *Host {
+	if o.host == nil {
+		return nil
+	}
+	h := *o.host
+	return &h
+}
+
+// Port returns the port of a tuple origin or the empty string if the port is the default port for the scheme.
+func (o *Origin) Port() string {
+	if o.port == nil {
+		return ""
+	}
+	return *o.port
+}
+
+// Domain returns the domain of a tuple origin. The returned bool is false if the domain is null, which it is
+// unless set with WithDomain.
+func (o *Origin) Domain() (string, bool) {
+	if o.domain == nil {
+		return "", false
+	}
+	return *o.domain, true
+}
+
+// WithDomain returns a copy of a tuple origin with the domain set, like setting document.domain does.
+// Opaque origins are returned unchanged.
+func (o *Origin) WithDomain(domain string) *Origin {
+	if o.IsOpaque() {
+		return o
+	}
+	c := *o
+	c.domain = &domain
+	return &c
+}
+
+// Serialize returns the ASCII serialization of the origin, e.g. "https://example.com:8080", or "null" for an opaque
+// origin.
+//
+// See: https://html.spec.whatwg.org/multipage/browsers.html#ascii-serialisation-of-an-origin
+func (o *Origin) Serialize() string {
+	if o.IsOpaque() {
+		return "null"
+	}
+	result := o.scheme + "://" + o.host.String()
+	if o.port != nil {
+		result += ":" + *o.port
+	}
+	return result
+}
+
+func (o *Origin) String() string {
+	return o.Serialize()
+}
+
+// IsSameOrigin returns true if o and other are the same opaque origin or tuple origins with identical scheme, host
+// and port. The domain is not considered.
+//
+// See: https://html.spec.whatwg.org/multipage/browsers.html#same-origin
+func (o *Origin) IsSameOrigin(other *Origin) bool {
+	if o.IsOpaque() || other.IsOpaque() {
+		return o.opaque == other.opaque
+	}
+	return o.scheme == other.scheme && o.host.String() == other.host.String() && o.Port() == other.Port() &&
+		(o.port == nil) == (other.port == nil)
+}
+
+// IsSameOriginDomain returns true if o and other are the same opaque origin, tuple origins with identical scheme and
+// identical non-null domain, or same origin tuple origins which both have a null domain.
+//
+// See: https://html.spec.whatwg.org/multipage/browsers.html#same-origin-domain
+func (o *Origin) IsSameOriginDomain(other *Origin) bool {
+	if o.IsOpaque() || other.IsOpaque() {
+		return o.opaque == other.opaque
+	}
+	if o.domain != nil && other.domain != nil {
+		return o.scheme == other.scheme && *o.domain == *other.domain
+	}
+	return o.domain == nil && other.domain == nil && o.IsSameOrigin(other)
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2020 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// ParseBytes parses rawUrl like Parse, but without first copying rawUrl into a string.
+//
+// The returned url and errors do not refer to rawUrl, so the caller is free to reuse it once ParseBytes returns.
+func (p *parser) ParseBytes(rawUrl []byte) (u *Url, err error) {
+	if p.opts.metricsSink != nil {
+		defer p.observeParse(time.Now(), &err)
+	}
+
+	// The string is only used while parsing. The components of the url are built in buffers owned by the parser,
+	// except for canonical urls which are sliced from a copy of the input.
+	input := unsafe.String(unsafe.SliceData(rawUrl), len(rawUrl))
+
+	if l, ok := p.scanCanonical(input); ok {
+		return l.build(p, strings.Clone(input), nil), nil
+	}
+
+	u, err = p.parse(context.Background(), input, nil, &Url{path: &path{}, unstableInput: true}, NoState)
+	if u != nil {
+		u.inputUrl = ""
+		u.unstableInput = false
+	}
+	return u, err
+}
+
+// ParseBytes parses rawUrl with the default parser. See Parser.ParseBytes.
+func ParseBytes(rawUrl []byte) (*Url, error) {
+	return defaultParser.ParseBytes(rawUrl)
+}
@@ -17,16 +17,19 @@
 package url
 
 import (
+	"context"
 	goerrors "errors"
 	u2 "net/url"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
 	"github.com/bits-and-blooms/bitset"
 
 	"github.com/nlnwa/whatwg-url/errors"
+	"github.com/nlnwa/whatwg-url/percent"
 )
 
 func NewParser(opts ...ParserOption) Parser {
@@ -39,20 +42,74 @@ func NewParser(opts ...ParserOption) Parser {
 
 type Parser interface {
 	Parse(rawUrl string) (*Url, error)
+	ParseBytes(rawUrl []byte) (*Url, error)
+	ParseInto(rawUrl string, u *Url) error
+	ParseContext(ctx context.Context, rawUrl string) (*Url, error)
 	ParseRef(rawUrl, ref string) (*Url, error)
+	Validate(rawUrl string) []*errors.ValidationError
 	BasicParser(urlOrRef string, base *Url, url *Url, stateOverride State) (*Url, error)
 	PercentEncodeString(s string, tr *PercentEncodeSet) string
 	NewUrl() *Url
+	Options() Options
+	With(opts ...ParserOption) Parser
 }
 
 type parser struct {
 	opts parserOptions
 }
 
+// Options returns a read-only snapshot of the parser's configuration.
+func (p *parser) Options() Options {
+	return Options{opts: p.opts}
+}
+
+// With returns a new parser with the same configuration as this parser, modified by opts.
+// The original parser is not changed.
+func (p *parser) With(opts ...ParserOption) Parser {
+	np := &parser{opts: p.opts}
+	for _, opt := range opts {
+		opt.apply(&np.opts)
+	}
+	if np.opts.idnaCache != nil && np.opts.idnaCache == p.opts.idnaCache {
+		// Cached results depend on the options, so the derived parser gets its own cache
+		np.opts.idnaCache = newIDNACache(p.opts.idnaCache.size)
+	}
+	return np
+}
+
 func (p *parser) Parse(rawUrl string) (*Url, error) {
 	return p.BasicParser(rawUrl, nil, nil, NoState)
 }
 
+// ParseContext parses rawUrl like Parse. The context is passed on to the function set by WithResolveHostFunc.
+func (p *parser) ParseContext(ctx context.Context, rawUrl string) (*Url, error) {
+	return p.basicParser(ctx, rawUrl, nil, nil, NoState)
+}
+
+// ParseInto parses rawUrl like Parse, but stores the result in u instead of allocating a new Url. Any previous
+// content of u is discarded. If parsing fails, u is reset and the error is returned.
+//
+// This allows a Url to be reused when parsing many urls, e.g. one Url per goroutine.
+func (p *parser) ParseInto(rawUrl string, u *Url) error {
+	return p.parseInto(context.Background(), rawUrl, u)
+}
+
+func (p *parser) parseInto(ctx context.Context, rawUrl string, u *Url) (err error) {
+	if p.opts.metricsSink != nil {
+		defer p.observeParse(time.Now(), &err)
+	}
+	if l, ok := p.scanCanonical(rawUrl); ok {
+		l.build(p, rawUrl, u)
+		return nil
+	}
+	u.Reset()
+	if _, err = p.parse(ctx, rawUrl, nil, u, NoState); err != nil {
+		u.Reset()
+		return err
+	}
+	return nil
+}
+
 func (p *parser) ParseRef(rawUrl, ref string) (*Url, error) {
 	if rawUrl == "" {
 		return p.Parse(ref)
@@ -80,6 +137,11 @@ func ParseRef(rawUrl, ref string) (*Url, error) {
 	return defaultParser.ParseRef(rawUrl, ref)
 }
 
+// ParseInto parses rawUrl with the default parser and stores the result in u. See Parser.ParseInto.
+func ParseInto(rawUrl string, u *Url) error {
+	return defaultParser.ParseInto(rawUrl, u)
+}
+
 type State int
 
 const (
@@ -107,12 +169,114 @@ const (
 	StateRelativeSlash
 )
 
+// String returns the name of the state as used in the WHATWG URL Standard, e.g. "special authority slashes state".
+func (s State) String() string {
+	switch s {
+	case NoState:
+		return "no state"
+	case StateSchemeStart:
+		return "scheme start state"
+	case StateScheme:
+		return "scheme state"
+	case StateNoScheme:
+		return "no scheme state"
+	case StateOpaquePath:
+		return "opaque path state"
+	case StateSpecialRelativeOrAuthority:
+		return "special relative or authority state"
+	case StateSpecialAuthoritySlashes:
+		return "special authority slashes state"
+	case StateSpecialAuthorityIgnoreSlashes:
+		return "special authority ignore slashes state"
+	case StatePathOrAuthority:
+		return "path or authority state"
+	case StateAuthority:
+		return "authority state"
+	case StateHost:
+		return "host state"
+	case StateHostname:
+		return "hostname state"
+	case StateFile:
+		return "file state"
+	case StateFileHost:
+		return "file host state"
+	case StateFileSlash:
+		return "file slash state"
+	case StatePort:
+		return "port state"
+	case StatePath:
+		return "path state"
+	case StatePathStart:
+		return "path start state"
+	case StateQuery:
+		return "query state"
+	case StateFragment:
+		return "fragment state"
+	case StateRelative:
+		return "relative state"
+	case StateRelativeSlash:
+		return "relative slash state"
+	}
+	return "unknown state"
+}
+
+// component returns the name of the url component parsed in the state.
+func (s State) component() string {
+	switch s {
+	case StateSchemeStart, StateScheme, StateNoScheme:
+		return "scheme"
+	case StateSpecialRelativeOrAuthority, StateSpecialAuthoritySlashes, StateSpecialAuthorityIgnoreSlashes,
+		StatePathOrAuthority, StateAuthority, StateFile, StateFileSlash:
+		return "authority"
+	case StateHost, StateHostname, StateFileHost:
+		return "host"
+	case StatePort:
+		return "port"
+	case StateQuery:
+		return "query"
+	case StateFragment:
+		return "fragment"
+	}
+	return "path"
+}
+
 // BasicParser implements WHATWG basic URL parser (https://url.spec.whatwg.org/#concept-basic-url-parser)
 // In most cases, when possible, prefer using the higher level Parse method.
 func (p *parser) BasicParser(urlOrRef string, base *Url, url *Url, stateOverride State) (*Url, error) {
+	return p.basicParser(context.Background(), urlOrRef, base, url, stateOverride)
+}
+
+func (p *parser) basicParser(ctx context.Context, urlOrRef string, base *Url, url *Url, stateOverride State) (u *Url, err error) {
+	if p.opts.metricsSink != nil && stateOverride == NoState {
+		defer p.observeParse(time.Now(), &err)
+	}
+	return p.parse(ctx, urlOrRef, base, url, stateOverride)
+}
+
+// parse is basicParser without metrics, for callers which report metrics themselves.
+func (p *parser) parse(ctx context.Context, urlOrRef string, base *Url, url *Url, stateOverride State) (*Url, error) {
+	if url == nil && base == nil && stateOverride == NoState {
+		if u := p.parseCanonical(urlOrRef); u != nil {
+			return u, nil
+		}
+	}
+	if base != nil {
+		base = base.detached()
+	}
+	u, err := p.runBasicParser(ctx, urlOrRef, base, url, stateOverride)
+	if err == nil && u != nil && p.opts.collectValidationErrors && p.opts.failOnValidationError && len(u.validationErrors) > 0 {
+		return nil, p.joinedError(u, u.validationErrors[len(u.validationErrors)-1])
+	}
+	return u, err
+}
+
+func (p *parser) runBasicParser(ctx context.Context, urlOrRef string, base *Url, url *Url, stateOverride State) (*Url, error) {
 	stateOverridden := stateOverride > NoState
-	if url == nil {
-		url = &Url{inputUrl: urlOrRef, path: &path{}}
+	if !stateOverridden {
+		if url == nil {
+			url = &Url{path: &path{}}
+		}
+		url.inputUrl = urlOrRef
 		if i, changed := trim(url.inputUrl, C0OrSpacePercentEncodeSet); changed {
 			if err := p.handleError(url, errors.InvalidURLUnit, false); err != nil {
 				return nil, err
@@ -139,13 +303,31 @@ func (p *parser) BasicParser(urlOrRef string, base *Url, url *Url, stateOverride
 		state = StateSchemeStart
 	}
 
-	var buffer strings.Builder
+	prevCursor := url.cursor
+	url.cursor = &parseCursor{input: input, state: &state}
+	defer func() {
+		url.cursor = prevCursor
+	}()
+
+	buffer := p.getParseBuffer()
+	defer p.putParseBuffer(buffer)
 	atFlag := false
 	bracketFlag := false
 	passwordTokenSeenFlag := false
+	portInvalidFlag := false
+	var spans *spanRecorder
+	if p.opts.recordSpans && !stateOverridden {
+		spans = newSpanRecorder()
+	}
 
 	for {
 		r := input.nextCodePoint()
+		if p.opts.trace != nil {
+			p.traceStep(input, state, r, buffer)
+		}
+		if spans != nil {
+			spans.step(state, input.offset, r)
+		}
 
 		switch state {
 		case StateSchemeStart:
@@ -161,8 +343,7 @@ func (p *parser) BasicParser(urlOrRef string, base *Url, url *Url, stateOverride
 				}
 			}
 		case StateScheme:
-			tr := ASCIIAlphanumeric.Clone().Set(0x2b).Set(0x2d).Set(0x2e)
-			if tr.Test(uint(r)) {
+			if schemeCodePoint.Test(uint(r)) {
 				buffer.WriteRune(unicode.ToLower(r))
 			} else if r == ':' {
 				if stateOverridden {
@@ -179,7 +360,7 @@ func (p *parser) BasicParser(urlOrRef string, base *Url, url *Url, stateOverride
 						return url, nil
 					}
 					// If url’s scheme is "file" and its host is an empty host or null, then return.
-					if url.scheme == "file" && *url.host == "" {
+					if url.scheme == "file" && url.host.isEmpty() {
 						return url, nil
 					}
 				}
@@ -200,7 +381,7 @@ func (p *parser) BasicParser(urlOrRef string, base *Url, url *Url, stateOverride
 					state = StateSpecialRelativeOrAuthority
 				} else if url.IsSpecialScheme() {
 					state = StateSpecialAuthoritySlashes
-				} else if input.remainingStartsWith("/") {
+				} else if input.remainingStartsWith("/") && !p.opts.quirks.has(QuirkOpaqueNonSpecialPaths) {
 					state = StatePathOrAuthority
 					input.nextCodePoint()
 				} else {
@@ -217,7 +398,18 @@ func (p *parser) BasicParser(urlOrRef string, base *Url, url *Url, stateOverride
 				}
 			}
 		case StateNoScheme:
-			if base == nil || (base.path.isOpaque() && r != '#') {
+			if base == nil && p.opts.schemeRelativeDefault != "" && r == '/' && input.remainingStartsWith("/") {
+				url.scheme = p.opts.schemeRelativeDefault
+				if url.scheme == "file" {
+					state = StateFile
+					input.rewindLast()
+				} else if url.IsSpecialScheme() {
+					state = StateSpecialAuthorityIgnoreSlashes
+					input.nextCodePoint()
+				} else {
+					state = StatePathOrAuthority
+				}
+			} else if base == nil || (base.path.isOpaque() && r != '#') {
 				if err := p.handleError(url, errors.MissingSchemeNonRelativeURL, true); err != nil {
 					return nil, err
 				}
@@ -356,7 +548,7 @@ func (p *parser) BasicParser(urlOrRef string, base *Url, url *Url, stateOverride
 						return nil, err
 					}
 				}
-				input.rewind(len([]rune(buffer.String())) + 1)
+				input.rewind(utf8.RuneCountInString(buffer.String()) + 1)
 				buffer.Reset()
 				state = StateHost
 			} else {
@@ -377,11 +569,14 @@ func (p *parser) BasicParser(urlOrRef string, base *Url, url *Url, stateOverride
 				if stateOverride == StateHostname {
 					return url, nil
 				}
-				host, err := p.parseHost(url, p, buffer.String(), !url.IsSpecialScheme())
+				host, err := p.recoverHostFailure(p.parseHost(url, buffer.String(), !url.IsSpecialScheme()))
 				if err != nil {
 					return url, err
 				}
-				url.host = &host
+				if err := p.resolveHost(ctx, url, host); err != nil {
+					return url, err
+				}
+				url.host = host
 				buffer.Reset()
 				state = StatePort
 			} else if input.eof || (r == '/' || r == '?' || r == '#' || url.isSpecialSchemeAndBackslash(r)) {
@@ -390,14 +585,19 @@ func (p *parser) BasicParser(urlOrRef string, base *Url, url *Url, stateOverride
 					if err := p.handleError(url, errors.HostMissing, true); err != nil {
 						return nil, err
 					}
+					// Only reached when Validate recovers from the failure
+					state = StatePathStart
 				} else if stateOverridden && buffer.Len() == 0 && (url.username != "" || url.password != "" || url.port != nil) {
 					return url, nil
 				} else {
-					host, err := p.parseHost(url, p, buffer.String(), !url.IsSpecialScheme())
+					host, err := p.recoverHostFailure(p.parseHost(url, buffer.String(), !url.IsSpecialScheme()))
 					if err != nil {
 						return url, err
 					}
-					url.host = &host
+					if err := p.resolveHost(ctx, url, host); err != nil {
+						return url, err
+					}
+					url.host = host
 					buffer.Reset()
 					state = StatePathStart
 					if stateOverridden {
@@ -411,7 +611,7 @@ func (p *parser) BasicParser(urlOrRef string, base *Url, url *Url, stateOverride
 					bracketFlag = false
 				}
 				if input.currentIsInvalid() && p.opts.acceptInvalidCodepoints {
-					buffer.WriteString(string([]byte{input.getCurrentAsByte()}))
+					_ = buffer.WriteByte(input.getCurrentAsByte())
 				} else {
 					buffer.WriteRune(r)
 				}
@@ -438,14 +638,17 @@ func (p *parser) BasicParser(urlOrRef string, base *Url, url *Url, stateOverride
 				}
 				state = StatePathStart
 				input.rewindLast()
-			} else {
+			} else if !portInvalidFlag {
 				if err := p.handleError(url, errors.PortInvalid, true); err != nil {
 					return nil, err
 				}
+				// Only reached when Validate recovers from the failure. The rest of the port is checked for
+				// errors, but invalid code points are only reported once.
+				portInvalidFlag = true
 			}
 		case StateFile:
 			url.scheme = "file"
-			url.host = new(string)
+			url.host = &Host{}
 			if r == '/' || r == '\\' {
 				if r == '\\' {
 					if err := p.handleError(url, errors.InvalidReverseSolidus, false); err != nil {
@@ -508,20 +711,23 @@ func (p *parser) BasicParser(urlOrRef string, base *Url, url *Url, stateOverride
 					}
 					state = StatePath
 				} else if buffer.Len() == 0 {
-					url.host = new(string)
+					url.host = &Host{}
 					if stateOverridden {
 						return nil, nil
 					}
 					state = StatePathStart
 				} else {
-					host, err := p.parseHost(url, p, buffer.String(), !url.IsSpecialScheme())
+					host, err := p.recoverHostFailure(p.parseHost(url, buffer.String(), !url.IsSpecialScheme()))
 					if err != nil {
 						return url, err
 					}
-					if host == "localhost" {
-						host = ""
+					if host.Kind == DomainHost && host.ASCII == "localhost" {
+						host = &Host{}
+					}
+					if err := p.resolveHost(ctx, url, host); err != nil {
+						return url, err
 					}
-					url.host = &host
+					url.host = host
 					if stateOverridden {
 						return url, nil
 					}
@@ -566,15 +772,8 @@ func (p *parser) BasicParser(urlOrRef string, base *Url, url *Url, stateOverride
 						return nil, err
 					}
 				}
-				if isDoubleDotPathSegment(buffer.String()) {
-					url.path.shortenPath(url.scheme)
-
-					if r != '/' && !url.isSpecialSchemeAndBackslash(r) {
-						url.path.addSegment("")
-					}
-				} else if isSingleDotPathSegment(buffer.String()) && r != '/' && !url.isSpecialSchemeAndBackslash(r) {
-					url.path.addSegment("")
-				} else if !isSingleDotPathSegment(buffer.String()) {
+				last := r != '/' && !url.isSpecialSchemeAndBackslash(r)
+				if !url.path.handleDotSegment(buffer.String(), last, url.scheme) {
 					if url.scheme == "file" && url.path.isEmpty() && isWindowsDriveLetter(buffer.String()) {
 						// replace second code point in buffer with U+003A (:).
 						// This is a (platform-independent) Windows drive letter quirk.
@@ -611,17 +810,19 @@ func (p *parser) BasicParser(urlOrRef string, base *Url, url *Url, stateOverride
 					}
 				}
 				if invalidPercentEncoding {
-					buffer.WriteString(p.percentEncodeInvalidRune(r, p.opts.pathPercentEncodeSet))
+					p.writePercentEncodedInvalidRune(buffer, r, p.opts.pathPercentEncodeSet)
 				} else {
-					buffer.WriteString(p.percentEncodeRune(r, p.opts.pathPercentEncodeSet))
+					p.writePercentEncodedRune(buffer, r, p.opts.pathPercentEncodeSet)
 				}
 			}
 		case StateOpaquePath:
 			if r == '?' {
+				url.path.setOpaque(buffer.String())
 				url.query = new(string)
 				state = StateQuery
 				buffer.Reset()
 			} else if r == '#' {
+				url.path.setOpaque(buffer.String())
 				url.fragment = new(string)
 				state = StateFragment
 				buffer.Reset()
@@ -636,10 +837,11 @@ func (p *parser) BasicParser(urlOrRef string, base *Url, url *Url, stateOverride
 					if err := p.handleErrorWithDescription(url, errors.InvalidURLUnit, false, d); err != nil {
 						return nil, err
 					}
-					buffer.WriteString(p.percentEncodeInvalidRune(r, C0PercentEncodeSet))
+					p.writePercentEncodedInvalidRune(buffer, r, C0PercentEncodeSet)
 				} else {
-					buffer.WriteString(p.percentEncodeRune(r, C0PercentEncodeSet))
+					p.writePercentEncodedRune(buffer, r, C0PercentEncodeSet)
 				}
+			} else {
 				url.path.setOpaque(buffer.String())
 			}
 		case StateQuery:
@@ -664,7 +866,7 @@ func (p *parser) BasicParser(urlOrRef string, base *Url, url *Url, stateOverride
 				if url.isSpecialScheme(url.scheme) {
 					encodeSet = p.opts.specialQueryPercentEncodeSet
 				}
-				buffer.WriteString(p.percentEncodeRune(r, encodeSet))
+				p.writePercentEncodedRune(buffer, r, encodeSet)
 			} else {
 				q := buffer.String()
 				url.query = &q
@@ -686,7 +888,7 @@ func (p *parser) BasicParser(urlOrRef string, base *Url, url *Url, stateOverride
 				if url.isSpecialScheme(url.scheme) {
 					encodeSet = p.opts.specialFragmentPercentEncodeSet
 				}
-				buffer.WriteString(p.percentEncodeRune(r, encodeSet))
+				p.writePercentEncodedRune(buffer, r, encodeSet)
 			} else {
 				f := buffer.String()
 				url.fragment = &f
@@ -698,14 +900,10 @@ func (p *parser) BasicParser(urlOrRef string, base *Url, url *Url, stateOverride
 		}
 	}
 
-	return url, nil
-}
-
-func (p *parser) percentEncodeInvalidRune(r rune, tr *PercentEncodeSet) string {
-	if p.opts.percentEncodeSinglePercentSign {
-		return p.percentEncodeRune(r, tr.Set(0x25))
+	if spans != nil {
+		url.spans = spans.finish(urlOrRef, input.s)
 	}
-	return p.percentEncodeRune(r, tr)
+	return url, nil
 }
 
 func (p *parser) percentEncodeRune(r rune, tr *PercentEncodeSet) string {
@@ -713,17 +911,17 @@ func (p *parser) percentEncodeRune(r rune, tr *PercentEncodeSet) string {
 		return string(r)
 	}
 
-	var bytes = make([]byte, 4)
+	var bytes [utf8.UTFMax]byte
 	var n int
 	if p.opts.encodingOverride != nil {
 		b, _ := p.opts.encodingOverride.EncodeRune(r)
 		bytes[0] = b
 		n = 1
 	} else {
-		n = utf8.EncodeRune(bytes, r)
+		n = utf8.EncodeRune(bytes[:], r)
 	}
 
-	percentEncoded := make([]byte, 4*3)
+	var percentEncoded [utf8.UTFMax * 3]byte
 	j := 0
 	for i := 0; i < n; i++ {
 		c := bytes[i]
@@ -754,6 +952,12 @@ func (p *parser) PercentEncodeString(s string, tr *PercentEncodeSet) string {
 }
 
 func (p *parser) DecodePercentEncoded(s string) string {
+	if p.opts.encodingOverride == nil {
+		if strings.IndexByte(s, '%') < 0 {
+			return s
+		}
+		return string(percent.AppendDecode(make([]byte, 0, len(s)), s))
+	}
 	sb := strings.Builder{}
 	bytes := []byte(s)
 	for i := 0; i < len(bytes); i++ {
@@ -900,13 +1104,11 @@ func (u *Url) isSpecialScheme(s string) bool {
 }
 
 func (u *Url) getSpecialScheme(s string) (string, bool) {
-	dp, ok := u.parser.opts.specialSchemes[s]
-	return dp, ok
+	return u.parser.opts.specialScheme(s)
 }
 
 func (u *Url) isSpecialSchemeAndBackslash(r rune) bool {
-	ok := u.IsSpecialScheme()
-	return ok && r == '\\'
+	return r == '\\' && u.IsSpecialScheme()
 }
 
 func (u *Url) cleanDefaultPort() {
@@ -16,7 +16,15 @@
 
 package url
 
-import "golang.org/x/text/encoding/charmap"
+import (
+	"context"
+	"strings"
+
+	"github.com/bits-and-blooms/bitset"
+	"golang.org/x/text/encoding/charmap"
+
+	"github.com/nlnwa/whatwg-url/errors"
+)
 
 var defaultSpecialSchemes = map[string]string{
 	"ftp":   "21",
@@ -27,28 +35,315 @@ var defaultSpecialSchemes = map[string]string{
 	"wss":   "443",
 }
 
+// defaultSpecialScheme is a switch based lookup in defaultSpecialSchemes. It is called for nearly every code point
+// while parsing, which makes it worth avoiding the map lookup when the default table is in use.
+func defaultSpecialScheme(s string) (string, bool) {
+	switch s {
+	case "http", "ws":
+		return "80", true
+	case "https", "wss":
+		return "443", true
+	case "ftp":
+		return "21", true
+	case "file":
+		return "", true
+	}
+	return "", false
+}
+
+// specialScheme returns the default port for s and whether s is a special scheme.
+func (o *parserOptions) specialScheme(s string) (string, bool) {
+	if !o.customSpecialSchemes {
+		return defaultSpecialScheme(s)
+	}
+	dp, ok := o.specialSchemes[s]
+	return dp, ok
+}
+
 // parserOptions configure a url parser. parserOptions are set by the ParserOption
 // values passed to NewParser.
 type parserOptions struct {
-	reportValidationErrors              bool
-	failOnValidationError               bool
-	laxHostParsing                      bool
-	collapseConsecutiveSlashes          bool
-	acceptInvalidCodepoints             bool
-	preParseHostFunc                    func(url *Url, host string) string
-	postParseHostFunc                   func(url *Url, host string) string
-	percentEncodeSinglePercentSign      bool
-	allowSettingPathForNonBaseUrl       bool
-	skipWindowsDriveLetterNormalization bool
-	specialSchemes                      map[string]string
-	skipTrailingSlashNormalization      bool
-	encodingOverride                    *charmap.Charmap
-	pathPercentEncodeSet                *PercentEncodeSet
-	specialQueryPercentEncodeSet        *PercentEncodeSet
-	queryPercentEncodeSet               *PercentEncodeSet
-	specialFragmentPercentEncodeSet     *PercentEncodeSet
-	fragmentPercentEncodeSet            *PercentEncodeSet
-	skipEqualsForEmptySearchParamsValue bool
+	reportValidationErrors               bool
+	failOnValidationError                bool
+	validationErrorHandler               func(*errors.ValidationError) bool
+	joinValidationErrors                 bool
+	collectValidationErrors              bool
+	laxHostParsing                       bool
+	collapseConsecutiveSlashes           bool
+	acceptInvalidCodepoints              bool
+	preParseHostFunc                     func(url *Url, host string) string
+	postParseHostFunc                    func(url *Url, host string) string
+	percentEncodeSinglePercentSign       bool
+	allowSettingPathForNonBaseUrl        bool
+	skipWindowsDriveLetterNormalization  bool
+	specialSchemes                       map[string]string
+	customSpecialSchemes                 bool
+	skipTrailingSlashNormalization       bool
+	encodingOverride                     *charmap.Charmap
+	pathPercentEncodeSet                 *PercentEncodeSet
+	specialQueryPercentEncodeSet         *PercentEncodeSet
+	queryPercentEncodeSet                *PercentEncodeSet
+	specialFragmentPercentEncodeSet      *PercentEncodeSet
+	fragmentPercentEncodeSet             *PercentEncodeSet
+	skipEqualsForEmptySearchParamsValue  bool
+	allowIPv6ZoneID                      bool
+	publicSuffixList                     PublicSuffixList
+	stripTrailingDot                     bool
+	idnaCache                            *idnaCache
+	forbiddenHostCodePoints              *bitset.BitSet
+	forbiddenDomainCodePoints            *bitset.BitSet
+	resolveHostFunc                      func(ctx context.Context, host string) error
+	requireDottedHost                    bool
+	forbidLoopback                       bool
+	forbidPrivateAddresses               bool
+	forbidAmbiguousIPv4                  bool
+	ipv6Style                            IPv6Style
+	verifyDNSLength                      bool
+	singleLabelHostAllowList             map[string]bool
+	disablePooling                       bool
+	metricsSink                          MetricsSink
+	trace                                func(ev TraceEvent)
+	recoverFailures                      bool // set by Validate
+	recordSpans                          bool
+	quirks                               Quirk
+	schemeRelativeDefault                string
+	strictIDNA                           bool
+	queryPercentEncodeSetForSearchParams bool
+}
+
+// Options is a read-only snapshot of the configuration of a parser.
+// Use Parser.Options to get the options of a parser.
+type Options struct {
+	opts parserOptions
+}
+
+// ReportValidationErrors returns true if non fatal validation errors are recorded.
+func (o Options) ReportValidationErrors() bool {
+	return o.opts.reportValidationErrors
+}
+
+// FailOnValidationError returns true if the parser fails on non fatal validation errors.
+func (o Options) FailOnValidationError() bool {
+	return o.opts.failOnValidationError
+}
+
+// JoinValidationErrors returns true if a failed parse returns all validation errors joined together.
+func (o Options) JoinValidationErrors() bool {
+	return o.opts.joinValidationErrors
+}
+
+// CollectValidationErrors returns true if parsing continues after non fatal validation errors
+// when the parser fails on validation errors.
+func (o Options) CollectValidationErrors() bool {
+	return o.opts.collectValidationErrors
+}
+
+// ValidationErrorHandler returns the validation error handler or nil if not set.
+func (o Options) ValidationErrorHandler() func(*errors.ValidationError) bool {
+	return o.opts.validationErrorHandler
+}
+
+// LaxHostParsing returns true if lax host parsing is enabled.
+func (o Options) LaxHostParsing() bool {
+	return o.opts.laxHostParsing
+}
+
+// CollapseConsecutiveSlashes returns true if consecutive slashes in path are collapsed.
+func (o Options) CollapseConsecutiveSlashes() bool {
+	return o.opts.collapseConsecutiveSlashes
+}
+
+// AcceptInvalidCodepoints returns true if invalid code points are accepted.
+func (o Options) AcceptInvalidCodepoints() bool {
+	return o.opts.acceptInvalidCodepoints
+}
+
+// PreParseHostFunc returns the function called before host parsing or nil if not set.
+func (o Options) PreParseHostFunc() func(url *Url, host string) string {
+	return o.opts.preParseHostFunc
+}
+
+// PostParseHostFunc returns the function called after host parsing or nil if not set.
+func (o Options) PostParseHostFunc() func(url *Url, host string) string {
+	return o.opts.postParseHostFunc
+}
+
+// PercentEncodeSinglePercentSign returns true if a single '%' is percent encoded.
+func (o Options) PercentEncodeSinglePercentSign() bool {
+	return o.opts.percentEncodeSinglePercentSign
+}
+
+// AllowSettingPathForNonBaseUrl returns true if setting path is allowed for urls which cannot be a base url.
+func (o Options) AllowSettingPathForNonBaseUrl() bool {
+	return o.opts.allowSettingPathForNonBaseUrl
+}
+
+// SkipWindowsDriveLetterNormalization returns true if conversion of 'C|' to 'C:' is skipped.
+func (o Options) SkipWindowsDriveLetterNormalization() bool {
+	return o.opts.skipWindowsDriveLetterNormalization
+}
+
+// SpecialSchemes returns a copy of the map of special schemes to default ports.
+func (o Options) SpecialSchemes() map[string]string {
+	m := make(map[string]string, len(o.opts.specialSchemes))
+	for k, v := range o.opts.specialSchemes {
+		m[k] = v
+	}
+	return m
+}
+
+// SkipTrailingSlashNormalization returns true if normalizing of empty paths is skipped.
+func (o Options) SkipTrailingSlashNormalization() bool {
+	return o.opts.skipTrailingSlashNormalization
+}
+
+// EncodingOverride returns the encoding used instead of UTF-8 or nil if not set.
+func (o Options) EncodingOverride() *charmap.Charmap {
+	return o.opts.encodingOverride
+}
+
+// PathPercentEncodeSet returns a copy of the set of characters to percent encode in path component.
+func (o Options) PathPercentEncodeSet() *PercentEncodeSet {
+	return o.opts.pathPercentEncodeSet.Set()
+}
+
+// SpecialQueryPercentEncodeSet returns a copy of the set of characters to percent encode in query component
+// when scheme is special.
+func (o Options) SpecialQueryPercentEncodeSet() *PercentEncodeSet {
+	return o.opts.specialQueryPercentEncodeSet.Set()
+}
+
+// QueryPercentEncodeSet returns a copy of the set of characters to percent encode in query component
+// when scheme is not special.
+func (o Options) QueryPercentEncodeSet() *PercentEncodeSet {
+	return o.opts.queryPercentEncodeSet.Set()
+}
+
+// SpecialFragmentPercentEncodeSet returns a copy of the set of characters to percent encode in fragment component
+// when scheme is special.
+func (o Options) SpecialFragmentPercentEncodeSet() *PercentEncodeSet {
+	return o.opts.specialFragmentPercentEncodeSet.Set()
+}
+
+// FragmentPercentEncodeSet returns a copy of the set of characters to percent encode in fragment component
+// when scheme is not special.
+func (o Options) FragmentPercentEncodeSet() *PercentEncodeSet {
+	return o.opts.fragmentPercentEncodeSet.Set()
+}
+
+// SkipEqualsForEmptySearchParamsValue returns true if '=' is skipped for empty search parameter values.
+func (o Options) SkipEqualsForEmptySearchParamsValue() bool {
+	return o.opts.skipEqualsForEmptySearchParamsValue
+}
+
+// AllowIPv6ZoneID returns true if IPv6 zone identifiers are accepted.
+func (o Options) AllowIPv6ZoneID() bool {
+	return o.opts.allowIPv6ZoneID
+}
+
+// PublicSuffixList returns the public suffix list used by the parser.
+func (o Options) PublicSuffixList() PublicSuffixList {
+	return o.opts.publicSuffixList
+}
+
+// StripTrailingDot returns true if a trailing dot is removed from domains.
+func (o Options) StripTrailingDot() bool {
+	return o.opts.stripTrailingDot
+}
+
+// ForbiddenHostCodePoints returns the set of code points which are forbidden in opaque hosts.
+func (o Options) ForbiddenHostCodePoints() *bitset.BitSet {
+	return o.opts.forbiddenHostCodePoints.Clone()
+}
+
+// ForbiddenDomainCodePoints returns the set of code points which are forbidden in domains.
+func (o Options) ForbiddenDomainCodePoints() *bitset.BitSet {
+	return o.opts.forbiddenDomainCodePoints.Clone()
+}
+
+// IDNACacheSize returns the size of the domain to ASCII cache or 0 if caching is disabled.
+func (o Options) IDNACacheSize() int {
+	if o.opts.idnaCache == nil {
+		return 0
+	}
+	return o.opts.idnaCache.size
+}
+
+// ResolveHostFunc returns the function called after host parsing or nil if not set.
+func (o Options) ResolveHostFunc() func(ctx context.Context, host string) error {
+	return o.opts.resolveHostFunc
+}
+
+// RequireDottedHost returns true if single label domains are reported as validation errors.
+func (o Options) RequireDottedHost() bool {
+	return o.opts.requireDottedHost
+}
+
+// ForbidLoopback returns true if loopback and unspecified addresses are rejected.
+func (o Options) ForbidLoopback() bool {
+	return o.opts.forbidLoopback
+}
+
+// ForbidPrivateAddresses returns true if private and link-local addresses are rejected.
+func (o Options) ForbidPrivateAddresses() bool {
+	return o.opts.forbidPrivateAddresses
+}
+
+// ForbidAmbiguousIPv4 returns true if IPv4 addresses not written in dotted decimal notation are rejected.
+func (o Options) ForbidAmbiguousIPv4() bool {
+	return o.opts.forbidAmbiguousIPv4
+}
+
+// IPv6Style returns the style used when serializing IPv6 hosts.
+func (o Options) IPv6Style() IPv6Style {
+	return o.opts.ipv6Style
+}
+
+// VerifyDNSLength returns true if domains exceeding the length limits of DNS are rejected.
+func (o Options) VerifyDNSLength() bool {
+	return o.opts.verifyDNSLength
+}
+
+// DisablePooling returns true if the parser allocates new scratch buffers for every parse instead of reusing pooled ones.
+func (o Options) DisablePooling() bool {
+	return o.opts.disablePooling
+}
+
+// MetricsSink returns the sink receiving parse metrics, or nil if metrics are disabled.
+func (o Options) MetricsSink() MetricsSink {
+	return o.opts.metricsSink
+}
+
+// Trace returns the function called for every step of the basic URL parser, or nil if tracing is disabled.
+func (o Options) Trace() func(ev TraceEvent) {
+	return o.opts.trace
+}
+
+// RecordSpans returns true if the parser records the spans of the input the components of a url were parsed from.
+func (o Options) RecordSpans() bool {
+	return o.opts.recordSpans
+}
+
+// Quirks returns the deviations from the standard the parser reproduces.
+func (o Options) Quirks() Quirk {
+	return o.opts.quirks
+}
+
+// SchemeRelativeDefault returns the scheme given to scheme-relative urls parsed without a base url, or the empty
+// string if parsing them fails.
+func (o Options) SchemeRelativeDefault() string {
+	return o.opts.schemeRelativeDefault
+}
+
+// StrictIDNA returns true if domains are converted to ASCII with beStrict set to true.
+func (o Options) StrictIDNA() bool {
+	return o.opts.strictIDNA
+}
+
+// QueryPercentEncodeSetForSearchParams returns true if search params are serialized with the query percent-encode
+// sets of the parser instead of the application/x-www-form-urlencoded percent-encode set.
+func (o Options) QueryPercentEncodeSetForSearchParams() bool {
+	return o.opts.queryPercentEncodeSetForSearchParams
 }
 
 // ParserOption configures how we parse a URL.
@@ -86,6 +381,9 @@ func defaultParserOptions() parserOptions {
 		specialFragmentPercentEncodeSet: FragmentPercentEncodeSet,
 		fragmentPercentEncodeSet:        FragmentPercentEncodeSet,
 		specialSchemes:                  defaultSpecialSchemes,
+		publicSuffixList:                DefaultPublicSuffixList,
+		forbiddenHostCodePoints:         ForbiddenHostCodePoint,
+		forbiddenDomainCodePoints:       ForbiddenDomainCodePoint,
 	}
 }
 
@@ -103,6 +401,43 @@ func WithFailOnValidationError() ParserOption {
 	})
 }
 
+// WithJoinValidationErrors makes a failed parse return all validation errors found up to and including the one
+// which made parsing fail, joined in the order they were found. Without this option only the last error is returned.
+// The returned error unwraps to all the errors like the result of errors.Join, while errors.Type, errors.Code and the
+// other helpers in the errors package report the error which made parsing fail.
+// The errors are also recorded as with WithReportValidationErrors.
+//
+// This API is EXPERIMENTAL.
+func WithJoinValidationErrors() ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.joinValidationErrors = true
+	})
+}
+
+// WithCollectValidationErrors makes a parser configured with WithFailOnValidationError continue parsing after non
+// fatal validation errors. If any validation errors were found, parsing fails when the whole input is parsed and all
+// validation errors are returned joined together. A fatal validation error still stops parsing immediately.
+// This option implies WithJoinValidationErrors.
+//
+// This API is EXPERIMENTAL.
+func WithCollectValidationErrors() ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.joinValidationErrors = true
+		o.collectValidationErrors = true
+	})
+}
+
+// WithValidationErrorHandler sets a function which is called for each non fatal validation error.
+// If the function returns false, parsing is aborted and the validation error is returned.
+// If the function returns true, parsing continues.
+//
+// This is a more fine-grained alternative to WithFailOnValidationError.
+func WithValidationErrorHandler(f func(*errors.ValidationError) bool) ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.validationErrorHandler = f
+	})
+}
+
 // WithLaxHostParsing ignores some decoding errors and returns the host as is.
 //
 // This API is EXPERIMENTAL.
@@ -196,7 +531,11 @@ func WithSkipWindowsDriveLetterNormalization() ParserOption {
 // This API is EXPERIMENTAL.
 func WithSpecialSchemes(special map[string]string) ParserOption {
 	return newFuncParserOption(func(o *parserOptions) {
-		o.specialSchemes = special
+		o.specialSchemes = make(map[string]string, len(special))
+		for k, v := range special {
+			o.specialSchemes[k] = v
+		}
+		o.customSpecialSchemes = true
 	})
 }
 
@@ -277,3 +616,224 @@ func WithSkipEqualsForEmptySearchParamsValue() ParserOption {
 		o.skipEqualsForEmptySearchParamsValue = true
 	})
 }
+
+// WithAllowIPv6ZoneID accepts zone identifiers in IPv6 addresses using the syntax from RFC 6874
+// (e.g. http://[fe80::1%25eth0]/). The zone identifier is available in Host.Zone.
+// WhatWg standard does not allow zone identifiers.
+//
+// This API is EXPERIMENTAL.
+func WithAllowIPv6ZoneID() ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.allowIPv6ZoneID = true
+	})
+}
+
+// WithPublicSuffixList sets the public suffix list used by Url.PublicSuffix and Url.RegistrableDomain.
+// Default is golang.org/x/net/publicsuffix.List.
+func WithPublicSuffixList(list PublicSuffixList) ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.publicSuffixList = list
+	})
+}
+
+// WithStripTrailingDot removes the trailing dot from fully qualified domain names
+// (e.g. http://example.com./ => http://example.com/).
+// Use Url.HasTrailingDot to find out if the host had a trailing dot.
+//
+// This API is EXPERIMENTAL.
+func WithStripTrailingDot() ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.stripTrailingDot = true
+	})
+}
+
+// WithIDNACache caches the results of domain to ASCII conversion in a least recently used cache
+// holding up to size entries. Parsers derived with Parser.With get their own cache of the same size.
+// The cache is safe for concurrent use.
+//
+// This API is EXPERIMENTAL.
+func WithIDNACache(size int) ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		if size > 0 {
+			o.idnaCache = newIDNACache(size)
+		} else {
+			o.idnaCache = nil
+		}
+	})
+}
+
+// WithForbiddenHostCodePoints allows to set an alternative set of code points which are forbidden in opaque hosts
+// (the host of a URL that is not special). Default is ForbiddenHostCodePoint, which is also used if set is nil.
+//
+// e.g. to allow '|' in opaque hosts:
+//
+//	url.WithForbiddenHostCodePoints(url.ForbiddenHostCodePoint.Clone().Clear('|'))
+//
+// This API is EXPERIMENTAL.
+func WithForbiddenHostCodePoints(set *bitset.BitSet) ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		if set == nil {
+			set = ForbiddenHostCodePoint
+		}
+		o.forbiddenHostCodePoints = set.Clone()
+	})
+}
+
+// WithForbiddenDomainCodePoints allows to set an alternative set of code points which are forbidden in domains.
+// Default is ForbiddenDomainCodePoint, which is also used if set is nil.
+//
+// e.g. to additionally forbid '_' in domains:
+//
+//	url.WithForbiddenDomainCodePoints(url.ForbiddenDomainCodePoint.Clone().Set('_'))
+//
+// This API is EXPERIMENTAL.
+func WithForbiddenDomainCodePoints(set *bitset.BitSet) ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		if set == nil {
+			set = ForbiddenDomainCodePoint
+		}
+		o.forbiddenDomainCodePoints = set.Clone()
+	})
+}
+
+// WithResolveHostFunc sets a function which is called after a host is parsed. The function can be used to verify
+// that a host is resolvable or to apply DNS-based policy. If the function returns an error, parsing fails with
+// a validation error of type errors.HostResolution wrapping the returned error.
+//
+// The function is called for domains and IP addresses, but not for opaque or empty hosts. IPv6 addresses are not
+// enclosed in brackets, so the host can be passed directly to a net.Resolver.
+// The context is the one given to ParseContext or context.Background() for other methods.
+//
+// This API is EXPERIMENTAL.
+func WithResolveHostFunc(f func(ctx context.Context, host string) error) ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.resolveHostFunc = f
+	})
+}
+
+// WithRequireDottedHost reports a validation error of type errors.HostNotDotted when the host of a URL with a
+// special scheme (except file) is a domain consisting of a single label (e.g. http://intranet/).
+// Hosts in allowList (e.g. "localhost") are accepted.
+//
+// The validation error is not a failure. Combine with WithFailOnValidationError or WithValidationErrorHandler
+// to reject such URLs.
+//
+// This API is EXPERIMENTAL.
+func WithRequireDottedHost(allowList ...string) ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.requireDottedHost = true
+		o.singleLabelHostAllowList = make(map[string]bool, len(allowList))
+		for _, h := range allowList {
+			o.singleLabelHostAllowList[strings.ToLower(h)] = true
+		}
+	})
+}
+
+// WithForbidLoopback makes parsing fail with errors.HostForbiddenAddress if the host is a loopback address
+// (127.0.0.0/8, ::1), the unspecified address (::) or in 0.0.0.0/8, which also reach the local host on common
+// network stacks.
+// IPv4-mapped, IPv4-compatible and NAT64 (64:ff9b::/96) IPv6 addresses are classified by the embedded IPv4 address.
+//
+// Only IP address hosts are checked. Use WithResolveHostFunc to check the addresses a domain resolves to.
+//
+// This API is EXPERIMENTAL.
+func WithForbidLoopback() ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.forbidLoopback = true
+	})
+}
+
+// WithForbidPrivateAddresses makes parsing fail with errors.HostForbiddenAddress if the host is a private
+// (10.0.0.0/8, 172.16.0.0/12, 192.168.0.0/16, fc00::/7) or link-local (169.254.0.0/16, fe80::/10) address.
+// IPv4-mapped, IPv4-compatible and NAT64 (64:ff9b::/96) IPv6 addresses are classified by the embedded IPv4 address.
+//
+// Only IP address hosts are checked. Use WithResolveHostFunc to check the addresses a domain resolves to.
+//
+// This API is EXPERIMENTAL.
+func WithForbidPrivateAddresses() ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.forbidPrivateAddresses = true
+	})
+}
+
+// WithForbidAmbiguousIPv4 makes parsing fail with errors.IPv4Ambiguous if the host is an IPv4 address
+// which is not written in dotted decimal notation with four parts (e.g. "0x7f.1", "2130706433" or "%31%32%37.0.0.1").
+// Such hosts are often used to sneak addresses past filters.
+//
+// This API is EXPERIMENTAL.
+func WithForbidAmbiguousIPv4() ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.forbidAmbiguousIPv4 = true
+	})
+}
+
+// WithIPv6Style sets the style used when serializing IPv6 hosts.
+// The default is IPv6Compressed as defined by the standard. IPv6Expanded gives addresses which sort in numeric order
+// and which can be handled by systems not supporting "::".
+//
+// This API is EXPERIMENTAL.
+func WithIPv6Style(style IPv6Style) ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.ipv6Style = style
+	})
+}
+
+// WithVerifyDNSLength makes parsing fail with errors.DomainTooLong if a domain, after conversion to ASCII,
+// has an empty label (e.g. "a..b"), a label longer than 63 bytes or is longer than 253 bytes (not counting a
+// trailing dot).
+// The standard does not verify DNS length, so this check is not done by default.
+//
+// This API is EXPERIMENTAL.
+func WithVerifyDNSLength() ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.verifyDNSLength = true
+	})
+}
+
+// WithDisablePooling makes the parser allocate new scratch buffers for every parse instead of reusing buffers from a
+// pool shared by all parsers. Pooling reduces allocations when parsing many urls, but keeps some memory alive between
+// parses.
+//
+// This API is EXPERIMENTAL.
+func WithDisablePooling() ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.disablePooling = true
+	})
+}
+
+// WithSchemeRelativeDefault makes the parser accept scheme-relative urls (e.g. "//example.com/a") without a base url
+// by giving them scheme, which must be a lowercase ASCII scheme without the trailing ':'. By default, as defined by
+// the standard, parsing such urls fails with errors.MissingSchemeNonRelativeURL. Urls parsed relative to a base url
+// always get the scheme of the base url.
+//
+// This API is EXPERIMENTAL.
+func WithSchemeRelativeDefault(scheme string) ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.schemeRelativeDefault = scheme
+	})
+}
+
+// WithStrictIDNA makes domain to ASCII run with beStrict set to true. By default, as defined by the standard, a domain
+// which contains only ASCII (or a few allowed symbols) and no punycode labels is accepted even if it is not a valid
+// domain name (e.g. "exa_mple.com"). With this option such domains give errors.DomainToASCII.
+// Results of strict conversions are not cached.
+//
+// This API is EXPERIMENTAL.
+func WithStrictIDNA() ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.strictIDNA = true
+	})
+}
+
+// WithQueryPercentEncodeSetForSearchParams makes SearchParams serialize names and values with the query
+// percent-encode set of the parser (the special-query set for special urls) instead of the
+// application/x-www-form-urlencoded percent-encode set defined by the standard. Spaces are still written as '+'.
+// This keeps e.g. '/' and ':' in the query as they are, but '&', '=' and '+' in names and values are not encoded
+// either, so the serialized query may parse back to other parameters.
+//
+// This API is EXPERIMENTAL.
+func WithQueryPercentEncodeSetForSearchParams() ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.queryPercentEncodeSetForSearchParams = true
+	})
+}
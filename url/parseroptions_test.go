@@ -0,0 +1,288 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	goerrors "errors"
+	"reflect"
+	"testing"
+
+	"github.com/nlnwa/whatwg-url/errors"
+)
+
+func TestWithValidationErrorHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		abortOn   errors.ErrorType
+		wantTypes []errors.ErrorType
+		wantErr   bool
+	}{
+		{"1", "http://example.com/", errors.InvalidReverseSolidus, nil, false},
+		{"2", "http://example.com\\a\\b", errors.InvalidCredentials,
+			[]errors.ErrorType{errors.InvalidReverseSolidus, errors.InvalidReverseSolidus}, false},
+		{"3", "http://example.com\\a\\b", errors.InvalidReverseSolidus, []errors.ErrorType{errors.InvalidReverseSolidus}, true},
+		{"4", "http://user@example.com/", errors.InvalidCredentials, []errors.ErrorType{errors.InvalidCredentials}, true},
+		{"5", "http://example.com:99999/", errors.InvalidCredentials, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []errors.ErrorType
+			p := NewParser(WithValidationErrorHandler(func(e *errors.ValidationError) bool {
+				got = append(got, e.Type())
+				return e.Type() != tt.abortOn
+			}))
+			_, err := p.Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if len(got) != len(tt.wantTypes) {
+				t.Fatalf("handler called with %v, want %v", got, tt.wantTypes)
+			}
+			for i := range got {
+				if got[i] != tt.wantTypes[i] {
+					t.Errorf("handler called with %v, want %v", got, tt.wantTypes)
+				}
+			}
+		})
+	}
+}
+
+func TestWithJoinValidationErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []ParserOption
+		input     string
+		wantTypes []errors.ErrorType
+	}{
+		{"1", []ParserOption{WithFailOnValidationError()}, "http://user@example.com:99999/",
+			[]errors.ErrorType{errors.InvalidCredentials}},
+		{"2", []ParserOption{WithJoinValidationErrors()}, "http://user@example.com:99999/",
+			[]errors.ErrorType{errors.InvalidCredentials, errors.PortOutOfRange}},
+		{"3", []ParserOption{WithFailOnValidationError(), WithCollectValidationErrors()}, "http://user@example.com/a\\b",
+			[]errors.ErrorType{errors.InvalidCredentials, errors.InvalidReverseSolidus}},
+		{"4", []ParserOption{WithFailOnValidationError(), WithCollectValidationErrors()}, "http://user@example.com:99999/a\\b",
+			[]errors.ErrorType{errors.InvalidCredentials, errors.PortOutOfRange}},
+		{"5", []ParserOption{WithJoinValidationErrors()}, "http://example.com:99999/",
+			[]errors.ErrorType{errors.PortOutOfRange}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParser(tt.opts...).Parse(tt.input)
+			if err == nil {
+				t.Fatalf("Parse(%v) error = nil, want error", tt.input)
+			}
+			var got []errors.ErrorType
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				for _, e := range joined.Unwrap() {
+					got = append(got, errors.Type(e))
+				}
+			} else {
+				got = append(got, errors.Type(err))
+			}
+			if !reflect.DeepEqual(got, tt.wantTypes) {
+				t.Errorf("Parse(%v) error types = %v, want %v", tt.input, got, tt.wantTypes)
+			}
+		})
+	}
+
+	// The helpers in the errors package report the error which made parsing fail
+	_, err := NewParser(WithJoinValidationErrors()).Parse(" \tfoo")
+	if _, ok := err.(interface{ Unwrap() []error }); !ok {
+		t.Fatalf("Parse() error = %v, want joined errors", err)
+	}
+	if got, want := errors.Type(err), errors.MissingSchemeNonRelativeURL; got != want {
+		t.Errorf("Type() = %v, want %v", got, want)
+	}
+	if got, want := errors.Code(err), errors.MissingSchemeNonRelativeURL.Code(); got != want {
+		t.Errorf("Code() = %v, want %v", got, want)
+	}
+	if got, want := errors.Url(err), "foo"; got != want {
+		t.Errorf("Url() = %q, want %q", got, want)
+	}
+	if !errors.Failure(err) {
+		t.Errorf("Failure() = false, want true")
+	}
+	if !goerrors.Is(err, errors.ErrInvalidURLUnit) || !goerrors.Is(err, errors.ErrMissingSchemeNonRelativeURL) {
+		t.Errorf("Parse() error = %v, want both joined errors", err)
+	}
+
+	u, err := NewParser(WithCollectValidationErrors()).Parse("http://user@example.com/")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(u.ValidationErrors()) != 1 {
+		t.Errorf("ValidationErrors() = %v, want 1 error", u.ValidationErrors())
+	}
+}
+
+func TestParser_With(t *testing.T) {
+	base := NewParser(WithCollapseConsecutiveSlashes())
+	derived := base.With(WithSkipTrailingSlashNormalization())
+
+	if !derived.Options().CollapseConsecutiveSlashes() {
+		t.Errorf("With() did not keep base configuration")
+	}
+	if !derived.Options().SkipTrailingSlashNormalization() {
+		t.Errorf("With() did not apply new option")
+	}
+	if base.Options().SkipTrailingSlashNormalization() {
+		t.Errorf("With() modified base parser")
+	}
+
+	u, err := derived.Parse("http://example.com//a//b")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got, want := u.String(), "http://example.com/a/b"; got != want {
+		t.Errorf("Parse() = %v, want %v", got, want)
+	}
+}
+
+func TestOptions_ReturnsCopies(t *testing.T) {
+	special := map[string]string{"http": "80", "gopher": "70"}
+	p := NewParser(WithSpecialSchemes(special))
+	special["foo"] = "1"
+	p.Options().SpecialSchemes()["bar"] = "2"
+	if got := p.Options().SpecialSchemes(); len(got) != 2 {
+		t.Errorf("SpecialSchemes() = %v, want map with 2 entries", got)
+	}
+
+	if p.Options().PathPercentEncodeSet() == PathPercentEncodeSet {
+		t.Errorf("PathPercentEncodeSet() returned the package level set")
+	}
+	if p.Options().QueryPercentEncodeSet() == QueryPercentEncodeSet {
+		t.Errorf("QueryPercentEncodeSet() returned the package level set")
+	}
+	if !p.Options().PathPercentEncodeSet().RuneShouldBeEncoded(' ') {
+		t.Errorf("PathPercentEncodeSet() does not contain ' '")
+	}
+
+	p.Options().ForbiddenDomainCodePoints().Set('_')
+	if _, err := NewParser().Parse("http://a_b.example/"); err != nil {
+		t.Errorf("Parse() error = %v, changing ForbiddenDomainCodePoints() changed the default parser", err)
+	}
+}
+
+func TestDefaultSpecialScheme(t *testing.T) {
+	for _, s := range []string{"ftp", "file", "http", "https", "ws", "wss", "gopher", "HTTP", "htt", ""} {
+		dp, ok := defaultSpecialScheme(s)
+		wantDp, wantOk := defaultSpecialSchemes[s]
+		if dp != wantDp || ok != wantOk {
+			t.Errorf("defaultSpecialScheme(%q) = %v, %v, want %v, %v", s, dp, ok, wantDp, wantOk)
+		}
+	}
+}
+
+func TestWithSpecialSchemes(t *testing.T) {
+	p := NewParser(WithSpecialSchemes(map[string]string{"http": "80", "gopher": "70"}))
+	u, err := p.Parse("gopher://example.com:70/a/../b")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got, want := u.String(), "gopher://example.com/b"; got != want {
+		t.Errorf("Parse() = %v, want %v", got, want)
+	}
+	u, err = p.Parse("wss://example.com:443")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got, want := u.String(), "wss://example.com:443"; got != want {
+		t.Errorf("Parse() = %v, want %v", got, want)
+	}
+}
+
+func TestWithSchemeRelativeDefault(t *testing.T) {
+	tests := []struct {
+		name    string
+		scheme  string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"1", "", "//example.com/a", "", true},
+		{"2", "https", "//example.com/a", "https://example.com/a", false},
+		{"3", "https", "\\\\example.com/a", "", true},
+		{"4", "http", "//user@example.com:80/a?b#c", "http://user@example.com/a?b#c", false},
+		{"5", "foo", "//example.com/a", "foo://example.com/a", false},
+		{"6", "file", "//server/share", "file://server/share", false},
+		{"7", "https", "/a", "", true},
+		{"8", "https", "example.com", "", true},
+		{"9", "https", "http://example.com", "http://example.com/", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := NewParser(WithSchemeRelativeDefault(tt.scheme)).Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err != nil {
+				if errors.Type(err) != errors.MissingSchemeNonRelativeURL {
+					t.Errorf("Parse(%v) error = %v, want %v", tt.input, err, errors.MissingSchemeNonRelativeURL)
+				}
+				return
+			}
+			if got := u.String(); got != tt.want {
+				t.Errorf("Parse(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	u, err := NewParser(WithSchemeRelativeDefault("https")).ParseRef("http://example.com/", "//other.com/")
+	if err != nil {
+		t.Fatalf("ParseRef() error = %v", err)
+	}
+	if got, want := u.String(), "http://other.com/"; got != want {
+		t.Errorf("ParseRef() = %v, want %v", got, want)
+	}
+}
+
+func TestWithStrictIDNA(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"1", "http://example.com/", "http://example.com/", false},
+		{"2", "http://EXAMPLE.com/", "http://example.com/", false},
+		{"3", "http://exa_mple.com/", "", true},
+		{"4", "http://bücher.example/", "http://xn--bcher-kva.example/", false},
+	}
+	p := NewParser(WithStrictIDNA())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := p.Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err != nil {
+				if errors.Type(err) != errors.DomainToASCII {
+					t.Errorf("Parse(%v) error = %v, want %v", tt.input, err, errors.DomainToASCII)
+				}
+				return
+			}
+			if got := u.String(); got != tt.want {
+				t.Errorf("Parse(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	if !p.Options().StrictIDNA() {
+		t.Errorf("Options().StrictIDNA() = false, want true")
+	}
+}
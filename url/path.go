@@ -42,6 +42,23 @@ func (p *path) shortenPath(scheme string) {
 	p.p = p.p[0 : len(p.p)-1]
 }
 
+// handleDotSegment applies segment to the path and returns true if it is a single-dot or double-dot path segment.
+// A double-dot segment shortens the path. If last is true, an empty segment is added so the path ends with '/'.
+// Other segments are left to the caller and false is returned.
+func (p *path) handleDotSegment(segment string, last bool, scheme string) bool {
+	switch {
+	case isDoubleDotPathSegment(segment):
+		p.shortenPath(scheme)
+	case isSingleDotPathSegment(segment):
+	default:
+		return false
+	}
+	if last {
+		p.addSegment("")
+	}
+	return true
+}
+
 func (p *path) stripTrailingSpacesIfOpaque() {
 	if p.opaque {
 		p.p[0] = strings.TrimRight(p.p[0], "\u0020")
@@ -51,11 +68,45 @@ func (p *path) stripTrailingSpacesIfOpaque() {
 func (p *path) String() string {
 	if p.opaque {
 		return p.p[0]
-	} else {
-		output := ""
-		for _, pp := range p.p {
-			output += "/" + pp
-		}
-		return output
+	}
+	var sb strings.Builder
+	sb.Grow(p.serializedLen())
+	p.writeTo(&sb)
+	return sb.String()
+}
+
+// serializedLen returns the length of the serialized path.
+func (p *path) serializedLen() int {
+	if p.opaque {
+		return len(p.p[0])
+	}
+	n := len(p.p)
+	for _, pp := range p.p {
+		n += len(pp)
+	}
+	return n
+}
+
+// appendTo appends the serialized path to dst and returns the extended buffer.
+func (p *path) appendTo(dst []byte) []byte {
+	if p.opaque {
+		return append(dst, p.p[0]...)
+	}
+	for _, pp := range p.p {
+		dst = append(dst, '/')
+		dst = append(dst, pp...)
+	}
+	return dst
+}
+
+// writeTo writes the serialized path to sb.
+func (p *path) writeTo(sb *strings.Builder) {
+	if p.opaque {
+		sb.WriteString(p.p[0])
+		return
+	}
+	for _, pp := range p.p {
+		sb.WriteByte('/')
+		sb.WriteString(pp)
 	}
 }
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"strings"
+
+	"github.com/nlnwa/whatwg-url/percent"
+)
+
+// The path helpers below work on the path segments of the url rather than on the serialized path. Unlike string
+// functions applied to Pathname, they do not treat a percent-encoded slash ("%2F") as a separator.
+
+// PathDir returns the serialized path up to and including the last '/', e.g. "/a/" for "/a/b%2Fc.txt".
+// The result is percent-encoded like Pathname. An empty string is returned for an opaque or empty path.
+func (u *Url) PathDir() string {
+	if u.path.opaque || len(u.path.p) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	for _, s := range u.path.p[:len(u.path.p)-1] {
+		sb.WriteByte('/')
+		sb.WriteString(s)
+	}
+	sb.WriteByte('/')
+	return sb.String()
+}
+
+// PathBase returns the percent-decoded last path segment, e.g. "b/c.txt" for "/a/b%2Fc.txt".
+// An empty string is returned if the path ends with '/', or if it is opaque or empty.
+func (u *Url) PathBase() string {
+	if u.path.opaque || len(u.path.p) == 0 {
+		return ""
+	}
+	return percent.Decode(u.path.p[len(u.path.p)-1])
+}
+
+// PathExt returns the extension of PathBase, which is the suffix beginning at the final '.', e.g. ".gz" for
+// "/a/b.tar.gz". An empty string is returned if the last path segment has no '.'.
+func (u *Url) PathExt() string {
+	base := u.PathBase()
+	i := strings.LastIndexByte(base, '.')
+	if i < 0 || strings.IndexByte(base[i:], '/') >= 0 {
+		return ""
+	}
+	return base[i:]
+}
+
+// TrimPathPrefix returns the rest of the path if it starts with the path segments of prefix, and false if it does
+// not. The segments are compared percent-decoded and a segment must match as a whole, so the prefix "/api" matches
+// "/api/users" and "/api", but not "/apis". A trailing '/' in prefix is ignored.
+//
+// The rest of the path is percent-encoded like Pathname, e.g. "/users" for "/api/users" and "" for "/api".
+func (u *Url) TrimPathPrefix(prefix string) (string, bool) {
+	if u.path.opaque {
+		return "", false
+	}
+	prefix = strings.TrimSuffix(strings.TrimPrefix(prefix, "/"), "/")
+	segments := u.path.p
+	if prefix != "" {
+		for _, ps := range strings.Split(prefix, "/") {
+			if len(segments) == 0 || percent.Decode(segments[0]) != percent.Decode(ps) {
+				return "", false
+			}
+			segments = segments[1:]
+		}
+	}
+	var sb strings.Builder
+	for _, s := range segments {
+		sb.WriteByte('/')
+		sb.WriteString(s)
+	}
+	return sb.String(), true
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"testing"
+)
+
+func TestUrl_PathHelpers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantDir  string
+		wantBase string
+		wantExt  string
+	}{
+		{"1", "http://example.com/a/b.tar.gz", "/a/", "b.tar.gz", ".gz"},
+		{"2", "http://example.com/a/b%2Fc.txt", "/a/", "b/c.txt", ".txt"},
+		{"3", "http://example.com/a.b%2Fc", "/", "a.b/c", ""},
+		{"4", "http://example.com/a/", "/a/", "", ""},
+		{"5", "http://example.com", "/", "", ""},
+		{"6", "http://example.com/%C3%A6%20%C3%B8.html?a.b", "/", "æ ø.html", ".html"},
+		{"7", "mailto:user@example.com", "", "", ""},
+		{"8", "foo://example.com", "", "", ""},
+		{"9", "http://example.com/.htaccess", "/", ".htaccess", ".htaccess"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", tt.input, err)
+			}
+			if got := u.PathDir(); got != tt.wantDir {
+				t.Errorf("PathDir() = %v, want %v", got, tt.wantDir)
+			}
+			if got := u.PathBase(); got != tt.wantBase {
+				t.Errorf("PathBase() = %v, want %v", got, tt.wantBase)
+			}
+			if got := u.PathExt(); got != tt.wantExt {
+				t.Errorf("PathExt() = %v, want %v", got, tt.wantExt)
+			}
+		})
+	}
+}
+
+func TestUrl_TrimPathPrefix(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		prefix string
+		want   string
+		wantOk bool
+	}{
+		{"1", "http://example.com/api/users/1", "/api", "/users/1", true},
+		{"2", "http://example.com/api/users/1", "/api/", "/users/1", true},
+		{"3", "http://example.com/api", "/api", "", true},
+		{"4", "http://example.com/api/", "/api", "/", true},
+		{"5", "http://example.com/apis/users", "/api", "", false},
+		{"6", "http://example.com/api%2Fusers/1", "/api", "", false},
+		{"7", "http://example.com/a%2Fb/c", "/a%2fb", "/c", true},
+		{"8", "http://example.com/%7Euser/x", "/~user", "/x", true},
+		{"9", "http://example.com/a/b", "/", "/a/b", true},
+		{"10", "http://example.com/a", "/a/b", "", false},
+		{"11", "mailto:user@example.com", "/", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", tt.input, err)
+			}
+			got, ok := u.TrimPathPrefix(tt.prefix)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("TrimPathPrefix(%v) = %v, %v, want %v, %v", tt.prefix, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2020 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"io"
+	"strings"
+
+	"github.com/nlnwa/whatwg-url/percent"
+)
+
+// percentChunkSize is the number of input bytes encoded or decoded before the output is written by PercentEncodeTo
+// and PercentDecodeTo.
+const percentChunkSize = 4096
+
+// PercentEncodeTo writes s to w with the bytes in set percent-encoded (https://url.spec.whatwg.org/#string-utf-8-percent-encode).
+// Bytes of non-ASCII code points are always encoded. The output is written in chunks using a pooled buffer, so large
+// inputs like the opaque path of a data url can be encoded without allocating the encoded string.
+// It returns the number of bytes written and any error from w.
+func PercentEncodeTo(w io.Writer, s string, set *PercentEncodeSet) (int, error) {
+	buf := parseBufferPool.Get().(*parseBuffer)
+	defer putPooledBuffer(buf)
+
+	var n int
+	for len(s) > 0 {
+		chunk := s
+		if len(chunk) > percentChunkSize {
+			chunk = chunk[:percentChunkSize]
+		}
+		s = s[len(chunk):]
+		buf.b = percent.AppendEncode(buf.b[:0], chunk, set)
+		m, err := w.Write(buf.b)
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// PercentDecodeTo writes s to w with percent-encoded bytes decoded (https://url.spec.whatwg.org/#percent-decode).
+// A '%' which is not followed by two hex digits is written as is.
+// It returns the number of bytes written and any error from w.
+func PercentDecodeTo(w io.Writer, s string) (int, error) {
+	buf := parseBufferPool.Get().(*parseBuffer)
+	defer putPooledBuffer(buf)
+
+	var n int
+	for len(s) > 0 {
+		end := len(s)
+		if end > percentChunkSize {
+			end = percentChunkSize
+			// Don't split a percent-encoded byte between chunks
+			if i := strings.LastIndexByte(s[:end], '%'); i >= 0 && i+3 > end {
+				end = i + 3
+				if end > len(s) {
+					end = len(s)
+				}
+			}
+		}
+		buf.b = percent.AppendDecode(buf.b[:0], s[:end])
+		s = s[end:]
+		m, err := w.Write(buf.b)
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
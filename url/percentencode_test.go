@@ -0,0 +1,80 @@
+/*
+ * Copyright 2020 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPercentEncodeTo(t *testing.T) {
+	long := strings.Repeat("a b", percentChunkSize)
+	tests := []struct {
+		name string
+		s    string
+		set  *PercentEncodeSet
+		want string
+	}{
+		{"1", "", PathPercentEncodeSet, ""},
+		{"2", "a b?c", PathPercentEncodeSet, "a%20b%3Fc"},
+		{"3", "blåbær", C0PercentEncodeSet, "bl%C3%A5b%C3%A6r"},
+		{"4", "50%", C0PercentEncodeSet, "50%"},
+		{"5", "\xff", C0PercentEncodeSet, "%FF"},
+		{"6", long, PathPercentEncodeSet, strings.ReplaceAll(long, " ", "%20")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w strings.Builder
+			n, err := PercentEncodeTo(&w, tt.s, tt.set)
+			if err != nil {
+				t.Fatalf("PercentEncodeTo() error = %v", err)
+			}
+			if w.String() != tt.want || n != len(tt.want) {
+				t.Errorf("PercentEncodeTo() = %v, %v, want %v, %v", n, w.String(), len(tt.want), tt.want)
+			}
+		})
+	}
+}
+
+func TestPercentDecodeTo(t *testing.T) {
+	long := strings.Repeat("ab%20", percentChunkSize)
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"1", "", ""},
+		{"2", "a%20b%3fc", "a b?c"},
+		{"3", "bl%C3%A5b%C3%A6r", "blåbær"},
+		{"4", "50%", "50%"},
+		{"5", "%zz%4", "%zz%4"},
+		{"6", "%%41", "%A"},
+		{"7", long, strings.Repeat("ab ", percentChunkSize)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w strings.Builder
+			n, err := PercentDecodeTo(&w, tt.s)
+			if err != nil {
+				t.Fatalf("PercentDecodeTo() error = %v", err)
+			}
+			if w.String() != tt.want || n != len(tt.want) {
+				t.Errorf("PercentDecodeTo() = %v, %v, want %v, %v", n, w.String(), len(tt.want), tt.want)
+			}
+		})
+	}
+}
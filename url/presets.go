@@ -0,0 +1,54 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+// NewStrictParser returns a parser which fails on every validation error, not only on the failures defined by the
+// standard, converts domains to ASCII with strict IDNA and verifies that domains fit within the length limits of DNS.
+// This is meant for checking urls from users or configuration, where anything but a valid url should be rejected.
+// The options in opts are applied after the preset ones.
+//
+// This API is EXPERIMENTAL.
+func NewStrictParser(opts ...ParserOption) Parser {
+	return NewParser(append([]ParserOption{
+		WithFailOnValidationError(),
+		WithStrictIDNA(),
+		WithVerifyDNSLength(),
+	}, opts...)...)
+}
+
+// NewLenientParser returns a parser which accepts hosts with forbidden code points and input with invalid code
+// points, which the standard rejects. This is meant for urls found in the wild, e.g. in crawled pages and archived
+// records, where getting a url is more important than getting a valid one. The options in opts are applied after
+// the preset ones.
+//
+// This API is EXPERIMENTAL.
+func NewLenientParser(opts ...ParserOption) Parser {
+	return NewParser(append([]ParserOption{
+		WithLaxHostParsing(),
+		WithAcceptInvalidCodepoints(),
+	}, opts...)...)
+}
+
+// NewBrowserParser returns a parser which parses urls like a web browser does. Browsers implement the WHATWG URL
+// Standard, so this is the same as the default parser: validation errors are ignored and only the failures defined by
+// the standard make parsing fail. To parse like an older browser, add its quirks with WithQuirks, e.g.
+// NewBrowserParser(WithQuirks(ChromeLegacyQuirks)). The options in opts are applied after the preset ones.
+//
+// This API is EXPERIMENTAL.
+func NewBrowserParser(opts ...ParserOption) Parser {
+	return NewParser(opts...)
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"testing"
+)
+
+func TestPresetParsers(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       Parser
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"1", NewStrictParser(), "http://example.com/a", "http://example.com/a", false},
+		{"2", NewStrictParser(), "http://example.com\\a", "", true},
+		{"3", NewStrictParser(), "http://" + longLabel + ".com/", "", true},
+		{"4", NewLenientParser(), "http://exa mple.com/", "http://exa%20mple.com/", false},
+		{"5", NewLenientParser(), "http://exa\xffmple.com/", "http://exa%FFmple.com/", false},
+		{"6", NewBrowserParser(), "http://example.com\\a", "http://example.com/a", false},
+		{"7", NewBrowserParser(), "http://exa mple.com/", "", true},
+		{"8", NewStrictParser(WithCollectValidationErrors()), "http://example.com\\a\\b", "", true},
+		{"9", NewStrictParser(), "http://exa_mple.com/", "", true},
+		{"10", NewBrowserParser(), "http://exa_mple.com/", "http://exa_mple.com/", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := tt.p.Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err == nil && u.Href(false) != tt.want {
+				t.Errorf("Parse(%q) = %v, want %v", tt.input, u.Href(false), tt.want)
+			}
+		})
+	}
+}
+
+// longLabel is a domain label longer than the 63 bytes allowed by DNS.
+var longLabel = "a123456789b123456789c123456789d123456789e123456789f123456789g1234"
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// PublicSuffixList provides the public suffix of a domain.
+//
+// The domain is a lowercase ASCII domain without trailing dot. If no rule matches, the implementation
+// should return the last label of the domain as required by the "*" rule of the public suffix list algorithm.
+//
+// golang.org/x/net/publicsuffix.List implements this interface and is used by default.
+type PublicSuffixList interface {
+	PublicSuffix(domain string) string
+}
+
+// DefaultPublicSuffixList is the public suffix list used when no list is configured on the parser.
+var DefaultPublicSuffixList PublicSuffixList = publicsuffix.List
+
+// PublicSuffix returns the public suffix of the host (https://url.spec.whatwg.org/#host-public-suffix)
+// or the empty string if the host is not a domain.
+func (u *Url) PublicSuffix() string {
+	if u.host == nil || u.host.Kind != DomainHost {
+		return ""
+	}
+	return publicSuffix(u.parser.opts.publicSuffixList, u.host.ASCII)
+}
+
+// RegistrableDomain returns the registrable domain of the host (https://url.spec.whatwg.org/#host-registrable-domain),
+// also known as eTLD+1, or the empty string if the host is not a domain or has no registrable domain.
+func (u *Url) RegistrableDomain() string {
+	if u.host == nil || u.host.Kind != DomainHost {
+		return ""
+	}
+	return registrableDomain(u.parser.opts.publicSuffixList, u.host.ASCII)
+}
+
+// publicSuffix implements https://url.spec.whatwg.org/#host-public-suffix for a domain.
+func publicSuffix(list PublicSuffixList, domain string) string {
+	if domain == "" {
+		return ""
+	}
+	if list == nil {
+		list = DefaultPublicSuffixList
+	}
+	trailingDot := ""
+	if strings.HasSuffix(domain, ".") {
+		trailingDot = "."
+		domain = domain[:len(domain)-1]
+	}
+	if domain == "" {
+		return ""
+	}
+	return list.PublicSuffix(domain) + trailingDot
+}
+
+// registrableDomain implements https://url.spec.whatwg.org/#host-registrable-domain for a domain.
+func registrableDomain(list PublicSuffixList, domain string) string {
+	suffix := publicSuffix(list, domain)
+	if suffix == "" || suffix == domain {
+		return ""
+	}
+	rest := strings.TrimSuffix(domain, suffix)
+	if !strings.HasSuffix(rest, ".") {
+		return ""
+	}
+	rest = rest[:len(rest)-1]
+	if i := strings.LastIndexByte(rest, '.'); i >= 0 {
+		rest = rest[i+1:]
+	}
+	if rest == "" {
+		return ""
+	}
+	return rest + "." + suffix
+}
+
+// Site returns the serialized schemeful site (https://html.spec.whatwg.org/multipage/browsers.html#obtain-a-site)
+// of the URL, i.e. the scheme and the registrable domain of the host (e.g. "https://example.co.uk" for
+// "https://www.example.co.uk/"). If the host has no registrable domain, the host is used instead.
+//
+// The empty string is returned for URLs with an opaque origin, which are URLs with a scheme other than
+// ftp, http, https, ws or wss.
+func (u *Url) Site() string {
+	switch u.scheme {
+	case "ftp", "http", "https", "ws", "wss":
+	default:
+		return ""
+	}
+	if u.host == nil {
+		return ""
+	}
+	host := u.host.String()
+	if u.host.Kind == DomainHost {
+		if d := registrableDomain(u.parser.opts.publicSuffixList, u.host.ASCII); d != "" {
+			host = d
+		}
+	}
+	return u.scheme + "://" + host
+}
+
+// SameSite returns true if a and b are same site (https://html.spec.whatwg.org/multipage/browsers.html#concept-site-same-site).
+// URLs with an opaque origin are never same site with another URL.
+func SameSite(a, b *Url) bool {
+	sa := a.Site()
+	return sa != "" && sa == b.Site()
+}
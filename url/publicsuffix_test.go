@@ -0,0 +1,135 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUrl_RegistrableDomain(t *testing.T) {
+	tests := []struct {
+		name                  string
+		inputUrl              string
+		wantPublicSuffix      string
+		wantRegistrableDomain string
+	}{
+		// Examples from https://url.spec.whatwg.org/#host-public-suffix
+		{"1", "http://com", "com", ""},
+		{"2", "http://example.com", "com", "example.com"},
+		{"3", "http://www.example.com", "com", "example.com"},
+		{"4", "http://sub.www.example.com", "com", "example.com"},
+		{"5", "http://EXAMPLE.COM", "com", "example.com"},
+		{"6", "http://example.com.", "com.", "example.com."},
+		{"7", "http://github.io", "github.io", ""},
+		{"8", "http://whatwg.github.io", "github.io", "whatwg.github.io"},
+		{"9", "http://إختبار", "xn--kgbechtv", ""},
+		{"10", "http://example.إختبار", "xn--kgbechtv", "example.xn--kgbechtv"},
+		{"11", "http://sub.example.إختبار", "xn--kgbechtv", "example.xn--kgbechtv"},
+		{"12", "http://[2001:0db8:85a3:0000:0000:8a2e:0370:7334]", "", ""},
+		{"13", "http://192.168.0.1", "", ""},
+		{"14", "foo://example.com", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := Parse(tt.inputUrl)
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", tt.inputUrl, err)
+			}
+			if got := u.PublicSuffix(); got != tt.wantPublicSuffix {
+				t.Errorf("PublicSuffix() = %v, want %v", got, tt.wantPublicSuffix)
+			}
+			if got := u.RegistrableDomain(); got != tt.wantRegistrableDomain {
+				t.Errorf("RegistrableDomain() = %v, want %v", got, tt.wantRegistrableDomain)
+			}
+		})
+	}
+}
+
+type testSuffixList struct{}
+
+func (testSuffixList) PublicSuffix(domain string) string {
+	if strings.HasSuffix(domain, ".example.com") || domain == "example.com" {
+		return "example.com"
+	}
+	return domain[strings.LastIndexByte(domain, '.')+1:]
+}
+
+func TestWithPublicSuffixList(t *testing.T) {
+	u, _ := NewParser(WithPublicSuffixList(testSuffixList{})).Parse("http://a.b.example.com/")
+	if got, want := u.RegistrableDomain(), "b.example.com"; got != want {
+		t.Errorf("RegistrableDomain() = %v, want %v", got, want)
+	}
+}
+
+func TestUrl_Site(t *testing.T) {
+	tests := []struct {
+		name     string
+		inputUrl string
+		want     string
+	}{
+		{"1", "https://www.example.com/path", "https://example.com"},
+		{"2", "https://a.b.example.co.uk:8443/", "https://example.co.uk"},
+		{"3", "http://github.io/", "http://github.io"},
+		{"4", "http://192.168.0.1/", "http://192.168.0.1"},
+		{"5", "http://[::1]/", "http://[::1]"},
+		{"6", "file:///etc/passwd", ""},
+		{"7", "foo://example.com/", ""},
+		{"8", "wss://chat.example.com/", "wss://example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := Parse(tt.inputUrl)
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", tt.inputUrl, err)
+			}
+			if got := u.Site(); got != tt.want {
+				t.Errorf("Site() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameSite(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want bool
+	}{
+		{"1", "https://www.example.com/", "https://api.example.com:8443/x", true},
+		{"2", "http://www.example.com/", "https://www.example.com/", false},
+		{"3", "https://a.github.io/", "https://b.github.io/", false},
+		{"4", "https://example.com/", "https://example.org/", false},
+		{"5", "file:///a", "file:///a", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := Parse(tt.a)
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", tt.a, err)
+			}
+			b, err := Parse(tt.b)
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", tt.b, err)
+			}
+			if got := SameSite(a, b); got != tt.want {
+				t.Errorf("SameSite(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
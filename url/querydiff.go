@@ -0,0 +1,87 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+// QueryDiff is the difference between the search parameters of two urls, as returned by SearchParams.Diff.
+// The pairs are listed in the order they appear in the query they are taken from.
+//
+// This API is EXPERIMENTAL.
+type QueryDiff struct {
+	// Added are the pairs only found in the new query.
+	Added []NameValuePair
+	// Removed are the pairs only found in the old query.
+	Removed []NameValuePair
+	// Changed are the parameters found in both queries, but with different values.
+	Changed []QueryChange
+}
+
+// QueryChange is a search parameter whose value differs between two queries.
+type QueryChange struct {
+	Name     string
+	OldValue string
+	NewValue string
+}
+
+// Empty returns true if the queries have the same parameters with the same values.
+func (d QueryDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff compares the search parameters of s, the old query, with other, the new query. The order of the parameters
+// does not matter. When a name occurs more than once, the n-th value in s is compared with the n-th value in other,
+// so that "a=1&a=2" compared with "a=1&a=3" is a single change.
+//
+// This API is EXPERIMENTAL.
+func (s *SearchParams) Diff(other *SearchParams) QueryDiff {
+	s.load()
+	other.load()
+
+	newValues := make(map[string][]string, len(other.params))
+	for _, p := range other.params {
+		newValues[p.Name] = append(newValues[p.Name], p.Value)
+	}
+
+	var d QueryDiff
+	seen := make(map[string]int, len(s.params))
+	for _, p := range s.params {
+		i := seen[p.Name]
+		seen[p.Name]++
+		values := newValues[p.Name]
+		switch {
+		case i >= len(values):
+			d.Removed = append(d.Removed, *p)
+		case values[i] != p.Value:
+			d.Changed = append(d.Changed, QueryChange{Name: p.Name, OldValue: p.Value, NewValue: values[i]})
+		}
+	}
+	added := make(map[string]int, len(other.params))
+	for _, p := range other.params {
+		i := added[p.Name]
+		added[p.Name]++
+		if i >= seen[p.Name] {
+			d.Added = append(d.Added, *p)
+		}
+	}
+	return d
+}
+
+// DiffQuery compares the query of u with the query of other. See SearchParams.Diff.
+//
+// This API is EXPERIMENTAL.
+func (u *Url) DiffQuery(other *Url) QueryDiff {
+	return u.SearchParams().Diff(other.SearchParams())
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUrl_DiffQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want QueryDiff
+	}{
+		{"1", "http://example.com/?a=1&b=2", "http://example.com/?b=2&a=1", QueryDiff{}},
+		{"2", "http://example.com/", "http://example.com/?a=1",
+			QueryDiff{Added: []NameValuePair{{Name: "a", Value: "1"}}}},
+		{"3", "http://example.com/?a=1&sid=x", "http://example.com/?a=1",
+			QueryDiff{Removed: []NameValuePair{{Name: "sid", Value: "x"}}}},
+		{"4", "http://example.com/?page=1&q=go", "http://example.com/?q=go&page=2",
+			QueryDiff{Changed: []QueryChange{{Name: "page", OldValue: "1", NewValue: "2"}}}},
+		{"5", "http://example.com/?a=1&a=2", "http://example.com/?a=1&a=3&a=4",
+			QueryDiff{
+				Added:   []NameValuePair{{Name: "a", Value: "4"}},
+				Changed: []QueryChange{{Name: "a", OldValue: "2", NewValue: "3"}},
+			}},
+		{"6", "http://example.com/?q=a+b&x", "http://example.com/?q=a%20b&x=",
+			QueryDiff{}},
+		{"7", "http://example.com/?a=1&b=2&c=3", "http://example.com/?c=4&d=5&a=1",
+			QueryDiff{
+				Added:   []NameValuePair{{Name: "d", Value: "5"}},
+				Removed: []NameValuePair{{Name: "b", Value: "2"}},
+				Changed: []QueryChange{{Name: "c", OldValue: "3", NewValue: "4"}},
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := Parse(tt.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := Parse(tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := a.DiffQuery(b)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffQuery(%v, %v) = %+v, want %+v", tt.a, tt.b, got, tt.want)
+			}
+			if got.Empty() != tt.want.Empty() {
+				t.Errorf("Empty() = %v, want %v", got.Empty(), tt.want.Empty())
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+// Quirk is a set of deviations from the standard reproducing how a browser parsed urls. Quirks are meant for web
+// archive replay, where a url must sometimes be resolved the way the browser which made the original request did,
+// not the way the standard says.
+//
+// This API is EXPERIMENTAL.
+type Quirk uint
+
+const (
+	// QuirkOpaqueNonSpecialPaths parses everything after the scheme of a url with a non-special scheme as an opaque
+	// path, like Chrome did before version 130. For example, "git://example.com/repo" gets no host and the path
+	// "//example.com/repo", and relative references can not be resolved against such urls.
+	QuirkOpaqueNonSpecialPaths Quirk = 1 << iota
+)
+
+// ChromeLegacyQuirks are the quirks of Chrome before it followed the standard for non-special urls in version 130.
+const ChromeLegacyQuirks = QuirkOpaqueNonSpecialPaths
+
+// WithQuirks makes the parser reproduce the deviations from the standard in q. The option can be given several
+// times, the quirks are added together.
+//
+// This API is EXPERIMENTAL.
+func WithQuirks(q Quirk) ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.quirks |= q
+	})
+}
+
+// has returns true if all the quirks in q are in the set.
+func (q Quirk) has(quirk Quirk) bool {
+	return q&quirk == quirk
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"testing"
+)
+
+func TestWithQuirks(t *testing.T) {
+	tests := []struct {
+		name         string
+		quirks       Quirk
+		input        string
+		wantHref     string
+		wantHost     string
+		wantPathname string
+	}{
+		{"1", 0, "git://example.com/repo", "git://example.com/repo", "example.com", "/repo"},
+		{"2", ChromeLegacyQuirks, "git://example.com/repo", "git://example.com/repo", "", "//example.com/repo"},
+		{"3", ChromeLegacyQuirks, "git://example.com/repo?a b#c", "git://example.com/repo?a%20b#c", "", "//example.com/repo"},
+		{"4", ChromeLegacyQuirks, "http://example.com/a", "http://example.com/a", "example.com", "/a"},
+		{"5", ChromeLegacyQuirks, "mailto:user@example.com", "mailto:user@example.com", "", "user@example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := NewParser(WithQuirks(tt.quirks)).Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			if got := u.Href(false); got != tt.wantHref {
+				t.Errorf("Href() = %v, want %v", got, tt.wantHref)
+			}
+			if got := u.Host(); got != tt.wantHost {
+				t.Errorf("Host() = %v, want %v", got, tt.wantHost)
+			}
+			if got := u.Pathname(); got != tt.wantPathname {
+				t.Errorf("Pathname() = %v, want %v", got, tt.wantPathname)
+			}
+		})
+	}
+
+	if _, err := NewParser(WithQuirks(ChromeLegacyQuirks)).ParseRef("git://example.com/a", "b"); err == nil {
+		t.Errorf("ParseRef() error = nil, want error when resolving against an opaque path")
+	}
+}
@@ -0,0 +1,206 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrorMode decides what a Scanner does with lines which can not be parsed.
+type ErrorMode int
+
+const (
+	// SkipErrors skips lines which can not be parsed. This is the default.
+	SkipErrors ErrorMode = iota
+	// CollectErrors skips lines which can not be parsed and records their errors, which are returned by
+	// Scanner.Errors.
+	CollectErrors
+	// AbortOnError stops scanning at the first line which can not be parsed. The error is returned by Scanner.Err.
+	AbortOnError
+)
+
+// LineError is the error for a line which could not be parsed by a Scanner.
+type LineError struct {
+	// Line is the line number, starting at 1.
+	Line int
+	// Input is the content of the line.
+	Input string
+	Err   error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// DefaultMaxLineLength is the longest line accepted by a Scanner unless changed with WithMaxLineLength.
+const DefaultMaxLineLength = 1024 * 1024
+
+// ScannerOption configures a Scanner.
+type ScannerOption interface {
+	apply(*scannerOptions)
+}
+
+type scannerOptions struct {
+	parser        Parser
+	errorMode     ErrorMode
+	maxLineLength int
+}
+
+type funcScannerOption struct {
+	f func(*scannerOptions)
+}
+
+func (fso *funcScannerOption) apply(o *scannerOptions) {
+	fso.f(o)
+}
+
+func newFuncScannerOption(f func(*scannerOptions)) *funcScannerOption {
+	return &funcScannerOption{
+		f: f,
+	}
+}
+
+// WithScannerParser sets the parser used by the Scanner. The default is the parser used by Parse.
+//
+// This API is EXPERIMENTAL.
+func WithScannerParser(p Parser) ScannerOption {
+	return newFuncScannerOption(func(o *scannerOptions) {
+		o.parser = p
+	})
+}
+
+// WithErrorMode sets what the Scanner does with lines which can not be parsed. The default is SkipErrors.
+//
+// This API is EXPERIMENTAL.
+func WithErrorMode(mode ErrorMode) ScannerOption {
+	return newFuncScannerOption(func(o *scannerOptions) {
+		o.errorMode = mode
+	})
+}
+
+// WithMaxLineLength sets the longest line accepted by the Scanner. Scanning stops with bufio.ErrTooLong if a line is
+// longer. The default is DefaultMaxLineLength.
+//
+// This API is EXPERIMENTAL.
+func WithMaxLineLength(n int) ScannerOption {
+	return newFuncScannerOption(func(o *scannerOptions) {
+		o.maxLineLength = n
+	})
+}
+
+// Scanner reads newline-delimited urls from an io.Reader and parses them one at a time, like bufio.Scanner. Blank
+// lines are skipped and a trailing "\r" is removed from each line.
+//
+//	s := url.NewScanner(os.Stdin, url.WithErrorMode(url.CollectErrors))
+//	for s.Scan() {
+//		fmt.Println(s.Url().Host())
+//	}
+//	if err := s.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, e := range s.Errors() {
+//		log.Println(e)
+//	}
+//
+// This API is EXPERIMENTAL.
+type Scanner struct {
+	opts    scannerOptions
+	scanner *bufio.Scanner
+	line    int
+	text    string
+	url     *Url
+	errs    []*LineError
+	err     error
+}
+
+// NewScanner returns a Scanner reading from r.
+func NewScanner(r io.Reader, opts ...ScannerOption) *Scanner {
+	s := &Scanner{opts: scannerOptions{parser: defaultParser, maxLineLength: DefaultMaxLineLength}}
+	for _, opt := range opts {
+		opt.apply(&s.opts)
+	}
+	s.scanner = bufio.NewScanner(r)
+	initial := 64 * 1024
+	if initial > s.opts.maxLineLength {
+		initial = s.opts.maxLineLength
+	}
+	s.scanner.Buffer(make([]byte, 0, initial), s.opts.maxLineLength)
+	return s
+}
+
+// Scan advances to the next url, which is then available through Url. It returns false when the input is consumed,
+// when reading fails or, with AbortOnError, when a line can not be parsed.
+func (s *Scanner) Scan() bool {
+	s.url = nil
+	if s.err != nil {
+		return false
+	}
+	for s.scanner.Scan() {
+		s.line++
+		s.text = strings.TrimSuffix(s.scanner.Text(), "\r")
+		if strings.TrimSpace(s.text) == "" {
+			continue
+		}
+		u, err := s.opts.parser.Parse(s.text)
+		if err == nil {
+			s.url = u
+			return true
+		}
+		switch s.opts.errorMode {
+		case CollectErrors:
+			s.errs = append(s.errs, &LineError{Line: s.line, Input: s.text, Err: err})
+		case AbortOnError:
+			s.err = &LineError{Line: s.line, Input: s.text, Err: err}
+			return false
+		}
+	}
+	s.err = s.scanner.Err()
+	return false
+}
+
+// Url returns the url parsed by the last call to Scan.
+func (s *Scanner) Url() *Url {
+	return s.url
+}
+
+// Text returns the line of the url returned by Url.
+func (s *Scanner) Text() string {
+	return s.text
+}
+
+// Line returns the line number, starting at 1, of the url returned by Url.
+func (s *Scanner) Line() int {
+	return s.line
+}
+
+// Err returns the error which stopped the scanning: the error reading the input or, with AbortOnError, a *LineError
+// for the line which could not be parsed. Nil is returned at the end of the input.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// Errors returns the errors for the lines which could not be parsed when the error mode is CollectErrors.
+func (s *Scanner) Errors() []*LineError {
+	return s.errs
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"bufio"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScanner(t *testing.T) {
+	input := "http://example.com/a\r\n\nhttp://[::1\n  \nhttps://example.org\nhttp://exa mple.com\n"
+	tests := []struct {
+		name      string
+		mode      ErrorMode
+		wantUrls  []string
+		wantLines []int
+		wantErrs  []int
+		wantErr   int
+	}{
+		{"1", SkipErrors, []string{"http://example.com/a", "https://example.org/"}, []int{1, 5}, nil, 0},
+		{"2", CollectErrors, []string{"http://example.com/a", "https://example.org/"}, []int{1, 5}, []int{3, 6}, 0},
+		{"3", AbortOnError, []string{"http://example.com/a"}, []int{1}, nil, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScanner(strings.NewReader(input), WithErrorMode(tt.mode))
+			var gotUrls []string
+			var gotLines []int
+			for s.Scan() {
+				gotUrls = append(gotUrls, s.Url().Href(false))
+				gotLines = append(gotLines, s.Line())
+			}
+			if !reflect.DeepEqual(gotUrls, tt.wantUrls) {
+				t.Errorf("Scan() urls = %v, want %v", gotUrls, tt.wantUrls)
+			}
+			if !reflect.DeepEqual(gotLines, tt.wantLines) {
+				t.Errorf("Line() = %v, want %v", gotLines, tt.wantLines)
+			}
+			var gotErrs []int
+			for _, e := range s.Errors() {
+				gotErrs = append(gotErrs, e.Line)
+			}
+			if !reflect.DeepEqual(gotErrs, tt.wantErrs) {
+				t.Errorf("Errors() lines = %v, want %v", gotErrs, tt.wantErrs)
+			}
+			var le *LineError
+			if tt.wantErr == 0 {
+				if s.Err() != nil {
+					t.Errorf("Err() = %v, want nil", s.Err())
+				}
+			} else if !errors.As(s.Err(), &le) || le.Line != tt.wantErr {
+				t.Errorf("Err() = %v, want error for line %v", s.Err(), tt.wantErr)
+			}
+			if s.Scan() {
+				t.Errorf("Scan() after end = true, want false")
+			}
+		})
+	}
+}
+
+func TestScanner_Options(t *testing.T) {
+	s := NewScanner(strings.NewReader("http://example.com//a//b\n"), WithScannerParser(NewParser(WithCollapseConsecutiveSlashes())))
+	if !s.Scan() {
+		t.Fatalf("Scan() = false, want true, err = %v", s.Err())
+	}
+	if got, want := s.Url().String(), "http://example.com/a/b"; got != want {
+		t.Errorf("Url() = %v, want %v", got, want)
+	}
+	if got, want := s.Text(), "http://example.com//a//b"; got != want {
+		t.Errorf("Text() = %v, want %v", got, want)
+	}
+
+	s = NewScanner(strings.NewReader("http://example.com/"+strings.Repeat("a", 100)+"\n"), WithMaxLineLength(64))
+	if s.Scan() {
+		t.Errorf("Scan() = true, want false")
+	}
+	if s.Err() != bufio.ErrTooLong {
+		t.Errorf("Err() = %v, want %v", s.Err(), bufio.ErrTooLong)
+	}
+}
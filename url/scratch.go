@@ -0,0 +1,127 @@
+/*
+ * Copyright 2020 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxPooledBufferSize is the largest buffer capacity returned to the pool. Larger buffers are left for the garbage
+// collector to avoid keeping memory from a few huge urls alive.
+const maxPooledBufferSize = 64 * 1024
+
+// parseBuffer is the scratch space used by the basic parser to build the components of a url.
+type parseBuffer struct {
+	b []byte
+}
+
+var parseBufferPool = sync.Pool{
+	New: func() interface{} {
+		return &parseBuffer{b: make([]byte, 0, 256)}
+	},
+}
+
+// getParseBuffer returns an empty buffer, from the pool unless pooling is disabled.
+func (p *parser) getParseBuffer() *parseBuffer {
+	if p.opts.disablePooling {
+		return &parseBuffer{}
+	}
+	return parseBufferPool.Get().(*parseBuffer)
+}
+
+// putParseBuffer returns buf to the pool. The buffer must not be used afterwards.
+func (p *parser) putParseBuffer(buf *parseBuffer) {
+	if p.opts.disablePooling {
+		return
+	}
+	putPooledBuffer(buf)
+}
+
+// putPooledBuffer returns buf to the pool unless it has grown too large. The buffer must not be used afterwards.
+func putPooledBuffer(buf *parseBuffer) {
+	if cap(buf.b) > maxPooledBufferSize {
+		return
+	}
+	buf.b = buf.b[:0]
+	parseBufferPool.Put(buf)
+}
+
+func (buf *parseBuffer) WriteByte(c byte) error {
+	buf.b = append(buf.b, c)
+	return nil
+}
+
+func (buf *parseBuffer) WriteRune(r rune) {
+	buf.b = utf8.AppendRune(buf.b, r)
+}
+
+func (buf *parseBuffer) WriteString(s string) {
+	buf.b = append(buf.b, s...)
+}
+
+// String returns a copy of the content of the buffer.
+func (buf *parseBuffer) String() string {
+	return string(buf.b)
+}
+
+func (buf *parseBuffer) Len() int {
+	return len(buf.b)
+}
+
+func (buf *parseBuffer) Reset() {
+	buf.b = buf.b[:0]
+}
+
+// writePercentEncodedRune writes r to buf, percent-encoded if it is in the encode set tr.
+func (p *parser) writePercentEncodedRune(buf *parseBuffer, r rune, tr *PercentEncodeSet) {
+	if tr != nil && !tr.RuneShouldBeEncoded(r) {
+		buf.WriteRune(r)
+		return
+	}
+
+	var bytes [utf8.UTFMax]byte
+	var n int
+	if p.opts.encodingOverride != nil {
+		var ok bool
+		if bytes[0], ok = p.opts.encodingOverride.EncodeRune(r); !ok {
+			// Code points which can not be encoded are written as an HTML numeric character reference.
+			buf.WriteString("%26%23")
+			buf.b = strconv.AppendInt(buf.b, int64(r), 10)
+			buf.WriteString("%3B")
+			return
+		}
+		n = 1
+	} else {
+		n = utf8.EncodeRune(bytes[:], r)
+	}
+
+	for _, c := range bytes[:n] {
+		buf.b = append(buf.b, '%', "0123456789ABCDEF"[c>>4], "0123456789ABCDEF"[c&15])
+	}
+}
+
+// writePercentEncodedInvalidRune is like writePercentEncodedRune, but also encodes a '%' which does not start a valid
+// percent-encoded byte if the parser is configured to do so.
+func (p *parser) writePercentEncodedInvalidRune(buf *parseBuffer, r rune, tr *PercentEncodeSet) {
+	if p.opts.percentEncodeSinglePercentSign && r == '%' {
+		buf.WriteString("%25")
+		return
+	}
+	p.writePercentEncodedRune(buf, r, tr)
+}
@@ -19,35 +19,46 @@ package url
 import (
 	"sort"
 	"strings"
+	"sync"
+
+	"github.com/nlnwa/whatwg-url/formurlencoded"
 )
 
-type NameValuePair struct {
-	Name, Value string
-}
+// NameValuePair is a name/value pair of the query.
+type NameValuePair = formurlencoded.Pair
 
 // SearchParams represents a set of query parameters.
+//
+// The query of the url is parsed into name/value pairs the first time they are needed, so urls which are only
+// serialized never pay for decoding their query.
 type SearchParams struct {
 	url    *Url
 	params []*NameValuePair
+	// parsed is false if params must be parsed from the query of url before use.
+	parsed bool
+	// mu guards the lazy parsing of params, allowing concurrent readers.
+	mu sync.Mutex
+}
+
+// load parses the query of the url into params if it has changed since params were last parsed.
+func (s *SearchParams) load() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.parsed {
+		return
+	}
+	s.parsed = true
+	s.params = s.params[:0]
+	if s.url != nil && s.url.query != nil {
+		s.init(*s.url.query)
+	}
 }
 
 func (s *SearchParams) init(query string) {
 	s.params = s.params[:0]
-	p := strings.Split(query, "&")
-	for _, q := range p {
-		if q == "" {
-			continue
-		}
-		kv := strings.SplitN(q, "=", 2)
-		name := s.url.parser.DecodePercentEncoded(kv[0])
-		name = strings.ReplaceAll(name, "+", " ")
-		nvp := &NameValuePair{Name: name}
-		if len(kv) == 2 {
-			value := s.url.parser.DecodePercentEncoded(kv[1])
-			value = strings.ReplaceAll(value, "+", " ")
-			nvp.Value = value
-		}
-		s.params = append(s.params, nvp)
+	pairs := formurlencoded.ParseFunc(query, s.url.parser.DecodePercentEncoded)
+	for i := range pairs {
+		s.params = append(s.params, &pairs[i])
 	}
 }
 
@@ -63,24 +74,30 @@ func (s *SearchParams) update() {
 
 // Append appends a new name/value pair to the search parameters.
 func (s *SearchParams) Append(name, value string) {
+	s.load()
 	s.params = append(s.params, &NameValuePair{Name: name, Value: value})
 	s.update()
 }
 
 // Delete deletes the given search parameter, and its associated value(s), from the search parameters.
 func (s *SearchParams) Delete(name string) {
+	s.load()
 	var result []*NameValuePair
 	for _, nvp := range s.params {
 		if nvp.Name != name {
 			result = append(result, nvp)
 		}
 	}
+	if len(result) == len(s.params) {
+		return
+	}
 	s.params = result
 	s.update()
 }
 
 // Get returns the first value associated with the given search parameter name.
 func (s *SearchParams) Get(name string) string {
+	s.load()
 	for _, nvp := range s.params {
 		if nvp.Name == name {
 			return nvp.Value
@@ -91,6 +108,7 @@ func (s *SearchParams) Get(name string) string {
 
 // GetAll returns all the values associated with the given search parameter name.
 func (s *SearchParams) GetAll(name string) []string {
+	s.load()
 	var result []string
 	for _, nvp := range s.params {
 		if nvp.Name == name {
@@ -102,6 +120,7 @@ func (s *SearchParams) GetAll(name string) []string {
 
 // Has returns true if the search parameters contains a parameter with the given name.
 func (s *SearchParams) Has(name string) bool {
+	s.load()
 	for _, nvp := range s.params {
 		if nvp.Name == name {
 			return true
@@ -112,6 +131,7 @@ func (s *SearchParams) Has(name string) bool {
 
 // Set sets the value associated with name to value. It replaces any existing values associated with name.
 func (s *SearchParams) Set(name, value string) {
+	s.load()
 	isSet := false
 	params := s.params[:0]
 	for i, nvp := range s.params {
@@ -133,54 +153,114 @@ func (s *SearchParams) Set(name, value string) {
 	s.update()
 }
 
-// Sort sorts the search parameters by name.
+// Sort sorts the search parameters by name. The query is only updated if the order changed.
 func (s *SearchParams) Sort() {
-	sort.SliceStable(s.params, func(i, j int) bool {
+	s.load()
+	less := func(i, j int) bool {
 		return s.params[i].Name < s.params[j].Name
-	})
+	}
+	if sort.SliceIsSorted(s.params, less) {
+		return
+	}
+	sort.SliceStable(s.params, less)
 	s.update()
 }
 
-// SortAbsolute sorts the search parameters by name and value.
+// SortAbsolute sorts the search parameters by name and value. The query is only updated if the order changed.
 func (s *SearchParams) SortAbsolute() {
-	sort.SliceStable(s.params, func(i, j int) bool {
+	s.load()
+	less := func(i, j int) bool {
 		return s.params[i].Name+s.params[i].Value < s.params[j].Name+s.params[j].Value
-	})
+	}
+	if sort.SliceIsSorted(s.params, less) {
+		return
+	}
+	sort.SliceStable(s.params, less)
 	s.update()
 }
 
-// Iterate iterates over the search parameters.
+// Iterate iterates over the search parameters. The pairs may be modified by f.
+// The query is only updated if a pair was modified.
 func (s *SearchParams) Iterate(f func(pair *NameValuePair)) {
-	for _, nvp := range s.params {
+	s.load()
+	before := make([]NameValuePair, len(s.params))
+	for i, nvp := range s.params {
+		before[i] = *nvp
 		f(nvp)
 	}
+	for i, nvp := range s.params {
+		if *nvp != before[i] {
+			s.update()
+			return
+		}
+	}
+}
+
+// Filter removes the search parameters for which keep returns false. The order of the remaining parameters is preserved.
+// The query is only updated if a parameter was removed.
+func (s *SearchParams) Filter(keep func(pair *NameValuePair) bool) {
+	s.load()
+	// Find the first parameter to remove before modifying anything, so filtering which removes nothing is read-only
+	first := 0
+	for first < len(s.params) && keep(s.params[first]) {
+		first++
+	}
+	if first == len(s.params) {
+		return
+	}
+	params := s.params[:first]
+	for _, nvp := range s.params[first+1:] {
+		if keep(nvp) {
+			params = append(params, nvp)
+		}
+	}
+	for i := len(params); i < len(s.params); i++ {
+		s.params[i] = nil
+	}
+	s.params = params
 	s.update()
 }
 
 func (s *SearchParams) String() string {
-	output := strings.Builder{}
+	s.load()
+	serializer := s.serializer()
+	var output []byte
 	for idx, nvp := range s.params {
 		if idx > 0 {
-			output.WriteRune('&')
+			output = append(output, '&')
 		}
 
-		s.QueryEscape(nvp.Name, &output)
+		output = serializer.AppendEncoded(output, nvp.Name)
 		if !s.url.parser.opts.skipEqualsForEmptySearchParamsValue || nvp.Value != "" {
-			output.WriteRune('=')
-		}
-		if nvp.Value != "" {
-			s.QueryEscape(nvp.Value, &output)
+			output = append(output, '=')
 		}
+		output = serializer.AppendEncoded(output, nvp.Value)
 	}
-	return output.String()
+	return string(output)
 }
 
 func (s *SearchParams) QueryEscape(st string, output *strings.Builder) {
-	for _, b := range st {
-		if b == 0x0020 {
-			output.WriteRune(0x002B)
-		} else {
-			output.WriteString(s.url.parser.percentEncodeRune(b, s.url.parser.opts.queryPercentEncodeSet))
+	serializer := s.serializer()
+	output.Write(serializer.AppendEncoded(nil, st))
+}
+
+// serializer returns an application/x-www-form-urlencoded serializer using the encoding override of the parser.
+// Names and values are encoded with formurlencoded.FormEncodeSet, or with the query or special-query percent-encode
+// set of the parser if WithQueryPercentEncodeSetForSearchParams is set.
+func (s *SearchParams) serializer() formurlencoded.Serializer {
+	var serializer formurlencoded.Serializer
+	if s.url.parser.opts.queryPercentEncodeSetForSearchParams {
+		// Use the same set as the parser, so that the query does not change if it is parsed again.
+		encodeSet := s.url.parser.opts.queryPercentEncodeSet
+		if s.url.isSpecialScheme(s.url.scheme) {
+			encodeSet = s.url.parser.opts.specialQueryPercentEncodeSet
 		}
+		if encodeSet != nil {
+			serializer.EncodeSet = encodeSet
+		}
+	}
+	if s.url.parser.opts.encodingOverride != nil {
+		serializer.Encoding = s.url.parser.opts.encodingOverride
 	}
+	return serializer
 }
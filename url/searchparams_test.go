@@ -19,6 +19,8 @@ package url
 import (
 	"reflect"
 	"testing"
+
+	"github.com/nlnwa/whatwg-url/formurlencoded"
 )
 
 func TestUrlSearchParams_Get(t *testing.T) {
@@ -36,6 +38,7 @@ func TestUrlSearchParams_Get(t *testing.T) {
 		{"5", "http://example.com?foo=bar2&foo=bar", "foo2", "", false},
 		{"6", "http://example.com?foo=bar2&foo2", "foo2", "", true},
 		{"7", "http://example.com/", "foo2", "", false},
+		{"8", "http://example.com?a+b=c%2Bd", "a b", "c+d", true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -191,3 +194,147 @@ func TestUrlSearchParams_String(t *testing.T) {
 		})
 	}
 }
+
+func TestUrlSearchParams_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name           string
+		key            string
+		value          string
+		wantSerialized string
+	}{
+		{"1", "a&b=c+d", "x", "a%26b%3Dc%2Bd=x"},
+		{"2", "a", "b&c=d", "a=b%26c%3Dd"},
+		{"3", "a b", "c/d:e", "a+b=c%2Fd%3Ae"},
+		{"4", "%", "#", "%25=%23"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := Parse("http://example.com/")
+			u.SearchParams().Append(tt.key, tt.value)
+			if got := u.SearchParams().String(); got != tt.wantSerialized {
+				t.Errorf("String() = %v, want %v", got, tt.wantSerialized)
+			}
+			if got, want := u.SearchParams().String(), formurlencoded.Serialize([]formurlencoded.Pair{{Name: tt.key, Value: tt.value}}, nil); got != want {
+				t.Errorf("String() = %v, want formurlencoded.Serialize() = %v", got, want)
+			}
+			u2, err := Parse(u.Href(false))
+			if err != nil {
+				t.Fatalf("Parse(%v) error = %v", u.Href(false), err)
+			}
+			if got := u2.SearchParams().GetAll(tt.key); len(got) != 1 || got[0] != tt.value {
+				t.Errorf("GetAll(%q) after parsing %v = %q, want [%q]", tt.key, u.Href(false), got, tt.value)
+			}
+		})
+	}
+}
+
+func TestWithQueryPercentEncodeSetForSearchParams(t *testing.T) {
+	u, _ := NewParser(WithQueryPercentEncodeSetForSearchParams()).Parse("http://example.com/?a=1")
+	u.SearchParams().Append("b c", "d/e:f'g")
+	if got, want := u.SearchParams().String(), "a=1&b+c=d/e:f%27g"; got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+	u, _ = NewParser(WithQueryPercentEncodeSetForSearchParams()).Parse("foo://example.com/?a=1")
+	u.SearchParams().Append("b c", "d/e:f'g")
+	if got, want := u.SearchParams().String(), "a=1&b+c=d/e:f'g"; got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+}
+
+func TestUrlSearchParams_Filter(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"1", "http://example.com?a=1&b=2&a=3", "http://example.com/?b=2"},
+		{"2", "http://example.com?b=%41", "http://example.com/?b=%41"},
+		{"3", "http://example.com?a=1", "http://example.com/?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, _ := Parse(tt.url)
+			url.SearchParams().Filter(func(pair *NameValuePair) bool {
+				return pair.Name != "a"
+			})
+			if got := url.Href(false); got != tt.want {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUrlSearchParams_Unchanged(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		modify func(s *SearchParams)
+		want   string
+	}{
+		{"1", "http://example.com?a=b%20c&b=1", func(s *SearchParams) { s.Sort() }, "http://example.com/?a=b%20c&b=1"},
+		{"2", "http://example.com?b=1&a=b%20c", func(s *SearchParams) { s.Sort() }, "http://example.com/?a=b+c&b=1"},
+		{"3", "http://example.com?a=b%20c", func(s *SearchParams) { s.Delete("x") }, "http://example.com/?a=b%20c"},
+		{"4", "http://example.com?a=b%20c", func(s *SearchParams) { s.Iterate(func(pair *NameValuePair) {}) }, "http://example.com/?a=b%20c"},
+		{"5", "http://example.com?a=b%20c", func(s *SearchParams) {
+			s.Iterate(func(pair *NameValuePair) { pair.Value = "d" })
+		}, "http://example.com/?a=d"},
+		{"6", "http://example.com?a=b%20c&a=1", func(s *SearchParams) { s.SortAbsolute() }, "http://example.com/?a=1&a=b+c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, _ := Parse(tt.url)
+			tt.modify(url.SearchParams())
+			if got := url.Href(false); got != tt.want {
+				t.Errorf("Href() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUrlSearchParams_SpecialQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"1", "http://example.com?a=%27", "http://example.com/?a=%27&b=%27"},
+		{"2", "foo://example.com?a=%27", "foo://example.com?a='&b='"},
+	}
+	p := NewParser(WithQueryPercentEncodeSetForSearchParams())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, _ := p.Parse(tt.url)
+			url.SearchParams().Append("b", "'")
+			if got := url.Href(false); got != tt.want {
+				t.Errorf("Href() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUrlSearchParams_Lazy(t *testing.T) {
+	url, _ := Parse("http://example.com?a=1")
+	if url.searchParams != nil {
+		t.Errorf("Parse() created search params")
+	}
+	sp := url.SearchParams()
+	if sp.parsed {
+		t.Errorf("SearchParams() parsed the query before it was used")
+	}
+	if got := sp.Get("a"); got != "1" {
+		t.Errorf("Get() = %v, want %v", got, "1")
+	}
+
+	url.SetSearch("a=2&b=3")
+	if got := sp.Get("a"); got != "2" {
+		t.Errorf("Get() after SetSearch() = %v, want %v", got, "2")
+	}
+	url.SetSearch("")
+	if sp.Has("a") {
+		t.Errorf("Has() after SetSearch(\"\") = true, want false")
+	}
+	sp.Append("c", "4")
+	if got := url.Href(false); got != "http://example.com/?c=4" {
+		t.Errorf("Href() = %v, want %v", got, "http://example.com/?c=4")
+	}
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+// ComponentDistance is the distance between two urls broken down by component. Each distance is between 0, when the
+// components are equal, and 1, when they have nothing in common.
+//
+// This API is EXPERIMENTAL.
+type ComponentDistance struct {
+	// Scheme is 0 if the schemes are equal and 1 otherwise.
+	Scheme float64
+	// Host is 0 if the hosts, including the ports, are equal, 0.5 if they share a registrable domain
+	// (e.g. "www.example.com" and "images.example.com") and 1 otherwise.
+	Host float64
+	// Path is 1 minus the number of leading path segments the urls have in common divided by the number of
+	// segments in the longer path. Opaque paths are compared as a single segment.
+	Path float64
+	// Query is 1 minus the overlap of the query parameters. A parameter name present in both urls counts fully if
+	// the values are equal and half if they differ, and the sum is divided by the number of distinct names.
+	Query float64
+}
+
+// The weights of the components in Similarity. They add up to 1.
+const (
+	similaritySchemeWeight = 0.05
+	similarityHostWeight   = 0.4
+	similarityPathWeight   = 0.35
+	similarityQueryWeight  = 0.2
+)
+
+// Similarity returns the weighted similarity of a and b, between 0 and 1 where 1 means that they are equal apart
+// from the fragment. The host weighs most, followed by the path, the query and the scheme. The components are
+// compared as parsed, so the urls should be canonicalized with the same profile first to ignore differences which
+// do not matter, like the order of query parameters.
+//
+// This API is EXPERIMENTAL.
+func Similarity(a, b *Url) float64 {
+	d := Distance(a, b)
+	return 1 - (similaritySchemeWeight*d.Scheme + similarityHostWeight*d.Host + similarityPathWeight*d.Path +
+		similarityQueryWeight*d.Query)
+}
+
+// Distance returns the distance between a and b for each component used by Similarity. The fragment is not
+// compared.
+//
+// This API is EXPERIMENTAL.
+func Distance(a, b *Url) ComponentDistance {
+	d := ComponentDistance{
+		Host:  hostDistance(a, b),
+		Path:  pathDistance(a, b),
+		Query: queryDistance(a, b),
+	}
+	if a.scheme != b.scheme {
+		d.Scheme = 1
+	}
+	return d
+}
+
+func hostDistance(a, b *Url) float64 {
+	if a.Host() == b.Host() {
+		return 0
+	}
+	if a.host != nil && b.host != nil && a.host.Kind == DomainHost && b.host.Kind == DomainHost {
+		da := registrableDomain(a.parser.opts.publicSuffixList, a.host.ASCII)
+		if da != "" && da == registrableDomain(b.parser.opts.publicSuffixList, b.host.ASCII) {
+			return 0.5
+		}
+	}
+	return 1
+}
+
+func pathDistance(a, b *Url) float64 {
+	sa, sb := similaritySegments(a), similaritySegments(b)
+	n := len(sa)
+	if len(sb) > n {
+		n = len(sb)
+	}
+	if n == 0 {
+		return 0
+	}
+	var common int
+	for common < len(sa) && common < len(sb) && sa[common] == sb[common] {
+		common++
+	}
+	return 1 - float64(common)/float64(n)
+}
+
+// similaritySegments returns the path segments of u, with an opaque path as a single segment.
+func similaritySegments(u *Url) []string {
+	if u.path.isOpaque() {
+		return []string{u.Pathname()}
+	}
+	return u.path.p
+}
+
+func queryDistance(a, b *Url) float64 {
+	if a.Query() == b.Query() {
+		return 0
+	}
+	va, vb := queryValues(a), queryValues(b)
+	names := len(va)
+	var overlap float64
+	for name, values := range vb {
+		other, ok := va[name]
+		if !ok {
+			names++
+			continue
+		}
+		if equalValues(values, other) {
+			overlap++
+		} else {
+			overlap += 0.5
+		}
+	}
+	if names == 0 {
+		return 0
+	}
+	return 1 - overlap/float64(names)
+}
+
+// queryValues returns the values of the query parameters of u by name.
+func queryValues(u *Url) map[string][]string {
+	if u.Query() == "" {
+		return nil
+	}
+	sp := u.SearchParams()
+	sp.load()
+	values := make(map[string][]string, len(sp.params))
+	for _, p := range sp.params {
+		values[p.Name] = append(values[p.Name], p.Value)
+	}
+	return values
+}
+
+func equalValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
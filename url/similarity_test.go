@@ -0,0 +1,91 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want ComponentDistance
+	}{
+		{"1", "http://example.com/a/b?c=d#x", "http://example.com/a/b?c=d#y", ComponentDistance{}},
+		{"2", "http://example.com/", "https://example.com/", ComponentDistance{Scheme: 1}},
+		{"3", "http://www.example.com/", "http://images.example.com/", ComponentDistance{Host: 0.5}},
+		{"4", "http://www.example.co.uk/", "http://www.other.co.uk/", ComponentDistance{Host: 1}},
+		{"5", "http://example.com/", "http://example.com:8080/", ComponentDistance{Host: 0.5}},
+		{"6", "http://1.2.3.4/", "http://1.2.3.5/", ComponentDistance{Host: 1}},
+		{"7", "http://example.com/a/b/c/d", "http://example.com/a/b/x", ComponentDistance{Path: 0.5}},
+		{"8", "http://example.com/a", "http://example.com/b", ComponentDistance{Path: 1}},
+		{"9", "http://example.com/?a=1&b=2", "http://example.com/?b=2&a=1", ComponentDistance{}},
+		{"10", "http://example.com/?a=1&b=2", "http://example.com/?a=1&b=3", ComponentDistance{Query: 0.25}},
+		{"11", "http://example.com/?a=1&b=2", "http://example.com/?a=1&c=2", ComponentDistance{Query: 1 - 1.0/3}},
+		{"12", "http://example.com/?a=1", "http://example.com/", ComponentDistance{Query: 1}},
+		{"13", "mailto:a@example.com", "mailto:b@example.com", ComponentDistance{Path: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := Parse(tt.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := Parse(tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := Distance(a, b)
+			if !floatEqual(got.Scheme, tt.want.Scheme) || !floatEqual(got.Host, tt.want.Host) ||
+				!floatEqual(got.Path, tt.want.Path) || !floatEqual(got.Query, tt.want.Query) {
+				t.Errorf("Distance(%v, %v) = %+v, want %+v", tt.a, tt.b, got, tt.want)
+			}
+			if got, back := Distance(a, b), Distance(b, a); got != back {
+				t.Errorf("Distance(%v, %v) = %+v, but reversed = %+v", tt.a, tt.b, got, back)
+			}
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want float64
+	}{
+		{"1", "http://example.com/a?b=c#d", "http://example.com/a?b=c", 1},
+		{"2", "http://example.com/a/b", "http://example.com/a/c", 1 - 0.35*0.5},
+		{"3", "http://www.example.com/", "https://other.org/x?y", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, _ := Parse(tt.a)
+			b, _ := Parse(tt.b)
+			if got := Similarity(a, b); !floatEqual(got, tt.want) {
+				t.Errorf("Similarity(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func floatEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,218 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"strings"
+)
+
+// Span is a byte range [Start, End) of the input a url was parsed from. Start and End are -1 if the component was not
+// in the input.
+//
+// This API is EXPERIMENTAL.
+type Span struct {
+	Start int
+	End   int
+}
+
+// NoSpan is the span of components which were not in the input.
+var NoSpan = Span{Start: -1, End: -1}
+
+// IsSet returns true if the component was in the input.
+func (s Span) IsSet() bool {
+	return s.Start >= 0
+}
+
+// Spans holds, for each component of a url, the byte range of the input it was parsed from. Delimiters are not part
+// of the spans, except for the path which includes its leading '/'. The spans refer to the input as given to the
+// parser, before leading and trailing spaces and control characters and all tabs and newlines are removed.
+//
+// Components not in the input, e.g. those inherited from the base url when parsing a relative reference, have NoSpan.
+// A component removed by the parser, like a default port, still has the span it was parsed from. The spans are
+// not updated when the url is changed by the Set methods.
+//
+// This API is EXPERIMENTAL.
+type Spans struct {
+	Scheme   Span
+	Username Span
+	Password Span
+	Host     Span
+	Port     Span
+	Path     Span
+	Query    Span
+	Fragment Span
+}
+
+// WithRecordSpans makes the parser record the span of the input each component of a url was parsed from, available
+// through Url.Spans. This is meant for linters and editors which need to map the parsed url back to the input, e.g.
+// to highlight the part of the input causing a validation error.
+//
+// The fast path for urls which are already in canonical form is disabled, so parsing is slower.
+//
+// This API is EXPERIMENTAL.
+func WithRecordSpans() ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.recordSpans = true
+	})
+}
+
+// Spans returns the spans of the input the components of u were parsed from. False is returned if u was not parsed
+// by a parser configured with WithRecordSpans.
+func (u *Url) Spans() (Spans, bool) {
+	if u.spans == nil {
+		return Spans{}, false
+	}
+	return *u.spans, true
+}
+
+// Slots for the parts of the input tracked by spanRecorder.
+const (
+	spanScheme = iota
+	spanAuthority
+	spanHost
+	spanPort
+	spanPath
+	spanQuery
+	spanFragment
+	spanCount
+)
+
+// spanRecorder tracks the parts of the input handled by each state of the basic parser. The span of a part is from
+// the first code point handled in its states, to the code point ending it, which is handled by the same states.
+// Offsets are into the input after whitespace is removed and are mapped back to the original input by finish.
+type spanRecorder struct {
+	spans [spanCount]Span
+	// lastAt is the offset of the last '@' in the authority, or -1 if there is none.
+	lastAt int
+}
+
+func newSpanRecorder() *spanRecorder {
+	r := &spanRecorder{lastAt: -1}
+	for i := range r.spans {
+		r.spans[i] = NoSpan
+	}
+	return r
+}
+
+// step records that state handles the code point c at offset.
+func (r *spanRecorder) step(state State, offset int, c rune) {
+	var slot int
+	switch state {
+	case StateSchemeStart, StateScheme:
+		slot = spanScheme
+	case StateNoScheme:
+		// The scheme state has rewound the input, so what looked like a scheme was not one
+		r.spans[spanScheme] = NoSpan
+		return
+	case StateAuthority:
+		if c == '@' {
+			r.lastAt = offset
+		}
+		slot = spanAuthority
+	case StateHost, StateHostname, StateFileHost:
+		slot = spanHost
+	case StatePort:
+		slot = spanPort
+	case StatePathStart, StatePath, StateOpaquePath:
+		slot = spanPath
+	case StateQuery:
+		slot = spanQuery
+	case StateFragment:
+		slot = spanFragment
+	default:
+		return
+	}
+	if !r.spans[slot].IsSet() {
+		r.spans[slot].Start = offset
+	}
+	r.spans[slot].End = offset
+}
+
+// finish returns the recorded spans mapped to original, which was turned into input by removing whitespace.
+func (r *spanRecorder) finish(original, input string) *Spans {
+	m := newOffsetMapper(original, input)
+	s := &Spans{
+		Scheme:   m.span(r.spans[spanScheme]),
+		Username: NoSpan,
+		Password: NoSpan,
+		Host:     m.span(r.spans[spanHost]),
+		Port:     m.span(r.spans[spanPort]),
+		Path:     m.span(r.spans[spanPath]),
+		Query:    m.span(r.spans[spanQuery]),
+		Fragment: m.span(r.spans[spanFragment]),
+	}
+	if auth := r.spans[spanAuthority]; auth.IsSet() && r.lastAt >= 0 {
+		userinfo := Span{Start: auth.Start, End: r.lastAt}
+		if i := strings.IndexByte(input[userinfo.Start:userinfo.End], ':'); i >= 0 {
+			s.Username = m.span(Span{Start: userinfo.Start, End: userinfo.Start + i})
+			s.Password = m.span(Span{Start: userinfo.Start + i + 1, End: userinfo.End})
+		} else {
+			s.Username = m.span(userinfo)
+		}
+	}
+	return s
+}
+
+// offsetMapper maps byte offsets in the input of the basic parser to offsets in the original input.
+type offsetMapper struct {
+	// index holds the offset in the original input of each byte of the input, or is nil if they are the same.
+	index []int
+	end   int
+}
+
+func newOffsetMapper(original, input string) offsetMapper {
+	if original == input {
+		return offsetMapper{}
+	}
+	m := offsetMapper{index: make([]int, 0, len(input))}
+	i := 0
+	for i < len(original) && original[i] <= 0x20 {
+		i++
+	}
+	for ; i < len(original) && len(m.index) < len(input); i++ {
+		if ASCIITabOrNewline.Test(uint(original[i])) {
+			continue
+		}
+		m.index = append(m.index, i)
+	}
+	m.end = i
+	return m
+}
+
+// offset maps an offset before the byte at offset in the input.
+func (m offsetMapper) offset(offset int) int {
+	if m.index == nil {
+		return offset
+	}
+	if offset < len(m.index) {
+		return m.index[offset]
+	}
+	return m.end
+}
+
+// span maps s, making sure whitespace removed after the last byte of s is not included.
+func (m offsetMapper) span(s Span) Span {
+	if !s.IsSet() || m.index == nil {
+		return s
+	}
+	start := m.offset(s.Start)
+	end := start
+	if s.End > s.Start {
+		end = m.index[s.End-1] + 1
+	}
+	return Span{Start: start, End: end}
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"testing"
+)
+
+func TestUrl_Spans(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		base  string
+		want  map[string]string
+	}{
+		{"1", "https://user:pw@example.com:8080/a/b?c=d#e", "", map[string]string{"scheme": "https", "username": "user",
+			"password": "pw", "host": "example.com", "port": "8080", "path": "/a/b", "query": "c=d", "fragment": "e"}},
+		{"2", "  http://EXAMPLE.com\t/a\n/b?# ", "", map[string]string{"scheme": "http", "host": "EXAMPLE.com",
+			"path": "/a\n/b", "query": "", "fragment": ""}},
+		{"3", "http://a@b@example.com", "", map[string]string{"scheme": "http", "username": "a@b", "host": "example.com",
+			"path": ""}},
+		{"4", "mailto:user@example.com?subject=hi", "", map[string]string{"scheme": "mailto", "path": "user@example.com",
+			"query": "subject=hi"}},
+		{"5", "../c?d", "http://example.com/a/b", map[string]string{"path": "../c", "query": "d"}},
+		{"6", "example.com/a", "http://example.org/", map[string]string{"path": "example.com/a"}},
+		{"7", "file:///c:/a", "", map[string]string{"scheme": "file", "host": "", "path": "/c:/a"}},
+		{"8", "http://[::1]:80", "", map[string]string{"scheme": "http", "host": "[::1]", "port": "80", "path": ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser(WithRecordSpans())
+			var u *Url
+			var err error
+			if tt.base == "" {
+				u, err = p.Parse(tt.input)
+			} else {
+				u, err = p.ParseRef(tt.base, tt.input)
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			spans, ok := u.Spans()
+			if !ok {
+				t.Fatalf("Spans() ok = false, want true")
+			}
+			for name, span := range map[string]Span{"scheme": spans.Scheme, "username": spans.Username,
+				"password": spans.Password, "host": spans.Host, "port": spans.Port, "path": spans.Path,
+				"query": spans.Query, "fragment": spans.Fragment} {
+				want, wantSet := tt.want[name]
+				if span.IsSet() != wantSet {
+					t.Errorf("Spans().%s = %v, want set %v", name, span, wantSet)
+					continue
+				}
+				if !span.IsSet() {
+					continue
+				}
+				if got := tt.input[span.Start:span.End]; got != want {
+					t.Errorf("Spans().%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+
+	u, _ := Parse("http://example.com/")
+	if _, ok := u.Spans(); ok {
+		t.Errorf("Spans() ok = true, want false for parser without WithRecordSpans")
+	}
+}
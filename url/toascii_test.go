@@ -103,28 +103,3 @@ func TestIdnaV2(t *testing.T) {
 		}
 	}
 }
-
-func TestContainsOnlyASCIIOrMiscAndNoPunycode(t *testing.T) {
-	tests := []struct {
-		Input  string
-		Output bool
-	}{
-		{"abc", true},
-		{"xn--abc", false},
-		{"abcxn--", true},
-		{"abc.xn--", false},
-		{"xnabc--", true},
-		{"xn.--", true},
-		{"ab\u2260c", true},
-		{"ab\u2261c", false},
-	}
-	for _, tt := range tests {
-		t.Run(tt.Input, func(t *testing.T) {
-			got := containsOnlyASCIIOrMiscAndNoPunycode(tt.Input)
-
-			if got != tt.Output {
-				t.Errorf("containsOnlyASCIIOrMiscAndNoPunycode(%v) = '%v', want '%v'", tt.Input, got, tt.Output)
-			}
-		})
-	}
-}
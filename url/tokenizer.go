@@ -0,0 +1,161 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"strings"
+)
+
+// Component identifies a url component reported by a Tokenizer.
+type Component int
+
+const (
+	ComponentScheme Component = iota
+	ComponentUsername
+	ComponentPassword
+	ComponentHost
+	ComponentPort
+	// ComponentPathSegment is reported once for every segment of a path which is not opaque.
+	ComponentPathSegment
+	// ComponentOpaquePath is reported for the path of urls with an opaque path, e.g. "mailto:user@example.com".
+	ComponentOpaquePath
+	ComponentQuery
+	ComponentFragment
+)
+
+func (c Component) String() string {
+	switch c {
+	case ComponentScheme:
+		return "scheme"
+	case ComponentUsername:
+		return "username"
+	case ComponentPassword:
+		return "password"
+	case ComponentHost:
+		return "host"
+	case ComponentPort:
+		return "port"
+	case ComponentPathSegment:
+		return "path segment"
+	case ComponentOpaquePath:
+		return "opaque path"
+	case ComponentQuery:
+		return "query"
+	case ComponentFragment:
+		return "fragment"
+	}
+	return "unknown component"
+}
+
+// Tokenizer parses urls and reports their components to a callback instead of returning a Url. This is meant for
+// processing large numbers of urls where only a few components are needed, e.g. counting hosts in log files.
+//
+// Urls which are already in canonical form, which is the case for most urls in logs and CDX files, are split into
+// components without building a Url. Other urls are parsed into a Url owned by the Tokenizer, which is reused for
+// every call. A Tokenizer must therefore not be used by several goroutines at the same time.
+//
+// This API is EXPERIMENTAL.
+type Tokenizer struct {
+	parser Parser
+	u      Url
+}
+
+// NewTokenizer returns a Tokenizer using the parser p. If p is nil, the parser used by Parse is used.
+func NewTokenizer(p Parser) *Tokenizer {
+	if p == nil {
+		p = defaultParser
+	}
+	return &Tokenizer{parser: p}
+}
+
+// Tokenize parses rawUrl and calls f with each component of the url, in the order they appear in the serialized url.
+// The values are serialized like in the url, but without delimiters like ':', '?' and '#'. Components the url does not
+// have are not reported, while empty components are, e.g. the empty query of "http://example.com/?".
+//
+// Tokenizing stops if f returns false. If rawUrl can not be parsed, the error is returned and f is not called.
+func (t *Tokenizer) Tokenize(rawUrl string, f func(c Component, value string) bool) error {
+	if p, ok := t.parser.(*parser); ok {
+		if l, ok := p.scanCanonical(rawUrl); ok {
+			l.tokenize(rawUrl, f)
+			return nil
+		}
+	}
+	if err := t.parser.ParseInto(rawUrl, &t.u); err != nil {
+		return err
+	}
+	tokenizeUrl(&t.u, f)
+	return nil
+}
+
+// tokenize reports the components of the canonical url input with the layout l.
+func (l canonicalLayout) tokenize(input string, f func(c Component, value string) bool) {
+	if !f(ComponentScheme, input[:l.schemeEnd]) || !f(ComponentHost, input[l.schemeEnd+3:l.hostEnd]) {
+		return
+	}
+	if l.portEnd > l.hostEnd && !f(ComponentPort, input[l.hostEnd+1:l.portEnd]) {
+		return
+	}
+	for rest := input[l.portEnd+1 : l.pathEnd]; ; {
+		segment, next, found := strings.Cut(rest, "/")
+		if !f(ComponentPathSegment, segment) || !found {
+			break
+		}
+		rest = next
+	}
+	if l.queryEnd > l.pathEnd && !f(ComponentQuery, input[l.pathEnd+1:l.queryEnd]) {
+		return
+	}
+	if l.queryEnd < len(input) {
+		f(ComponentFragment, input[l.queryEnd+1:])
+	}
+}
+
+// tokenizeUrl reports the components of u.
+func tokenizeUrl(u *Url, f func(c Component, value string) bool) {
+	if !f(ComponentScheme, u.scheme) {
+		return
+	}
+	if u.username != "" && !f(ComponentUsername, u.username) {
+		return
+	}
+	if u.password != "" && !f(ComponentPassword, u.password) {
+		return
+	}
+	if u.host != nil && !f(ComponentHost, u.host.String()) {
+		return
+	}
+	if u.port != nil && !f(ComponentPort, *u.port) {
+		return
+	}
+	if u.path.isOpaque() {
+		if !f(ComponentOpaquePath, u.path.String()) {
+			return
+		}
+	} else {
+		for _, segment := range u.path.p {
+			if !f(ComponentPathSegment, segment) {
+				return
+			}
+		}
+	}
+	if u.query != nil && !f(ComponentQuery, *u.query) {
+		return
+	}
+	if u.fragment != nil {
+		f(ComponentFragment, *u.fragment)
+	}
+}
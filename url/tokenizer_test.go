@@ -0,0 +1,90 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizer(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{"1", "http://example.com/", []string{"scheme=http", "host=example.com", "path segment="}, false},
+		{"2", "https://example.com:8080/a/b?c=d#e", []string{"scheme=https", "host=example.com", "port=8080",
+			"path segment=a", "path segment=b", "query=c=d", "fragment=e"}, false},
+		{"3", "HTTP://user:pw@EXAMPLE.com:80/a/../b?#", []string{"scheme=http", "username=user", "password=pw",
+			"host=example.com", "path segment=b", "query=", "fragment="}, false},
+		{"4", "mailto:user@example.com", []string{"scheme=mailto", "opaque path=user@example.com"}, false},
+		{"5", "file:///c:/a", []string{"scheme=file", "host=", "path segment=c:", "path segment=a"}, false},
+		{"6", "http://[::1]:8080/", []string{"scheme=http", "host=[::1]", "port=8080", "path segment="}, false},
+		{"7", "http://exa mple.com/", nil, true},
+	}
+	fast := NewTokenizer(nil)
+	slow := NewTokenizer(NewParser(WithVerifyDNSLength()))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, tok := range []*Tokenizer{fast, slow} {
+				var got []string
+				err := tok.Tokenize(tt.input, func(c Component, value string) bool {
+					got = append(got, c.String()+"="+value)
+					return true
+				})
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("Tokenize(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Tokenize(%q) = %q, want %q", tt.input, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTokenizer_Stop(t *testing.T) {
+	for _, input := range []string{"http://example.com/a/b?c#d", "http://EXAMPLE.com/a/b?c#d"} {
+		var got []Component
+		err := NewTokenizer(nil).Tokenize(input, func(c Component, value string) bool {
+			got = append(got, c)
+			return c != ComponentHost
+		})
+		if err != nil {
+			t.Fatalf("Tokenize(%q) error = %v", input, err)
+		}
+		if want := []Component{ComponentScheme, ComponentHost}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Tokenize(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func BenchmarkTokenizer(b *testing.B) {
+	tok := NewTokenizer(nil)
+	var host string
+	f := func(c Component, value string) bool {
+		host = value
+		return c != ComponentHost
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = tok.Tokenize("https://www.example.com/path/to/resource?query=value", f)
+	}
+	_ = host
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+// TraceEvent describes one step of the basic URL parser: the state machine is in State and is about to handle the
+// code point at Pointer. A state transition shows up as a change of State between two consecutive events.
+//
+// This API is EXPERIMENTAL.
+type TraceEvent struct {
+	// Input is the input being parsed, after leading and trailing C0 controls and spaces and all ASCII tabs and
+	// newlines are removed.
+	Input string
+	// State is the state handling the code point.
+	State State
+	// Pointer is the index, counted in code points, of the code point in Input. It can point past the end of Input.
+	Pointer int
+	// CodePoint is the code point at Pointer. It is utf8.RuneError when EOF is true.
+	CodePoint rune
+	// EOF is true if Pointer points past the end of Input.
+	EOF bool
+	// Buffer is the content of the parser's buffer before the code point is handled.
+	Buffer string
+}
+
+// WithTrace sets a function which is called for every step of the basic URL parser, including steps run by the
+// setters of Url. This is meant for debugging why a url parses the way it does, e.g. by comparing with the
+// state machine in the standard.
+//
+// The fast path for urls which are already in canonical form is disabled while tracing, so every parse runs the
+// full state machine. Tracing is slow and should not be enabled in production.
+//
+// This API is EXPERIMENTAL.
+func WithTrace(f func(ev TraceEvent)) ParserOption {
+	return newFuncParserOption(func(o *parserOptions) {
+		o.trace = f
+	})
+}
+
+// traceStep calls the trace function with the current step of the basic URL parser.
+func (p *parser) traceStep(input *inputString, state State, r rune, buffer *parseBuffer) {
+	p.opts.trace(TraceEvent{
+		Input:     input.s,
+		State:     state,
+		Pointer:   input.pointer,
+		CodePoint: r,
+		EOF:       input.eof,
+		Buffer:    buffer.String(),
+	})
+}
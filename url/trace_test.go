@@ -0,0 +1,60 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWithTrace(t *testing.T) {
+	var events []TraceEvent
+	p := NewParser(WithTrace(func(ev TraceEvent) {
+		events = append(events, ev)
+	}))
+	if _, err := p.Parse(" a:b"); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []TraceEvent{
+		{Input: "a:b", State: StateSchemeStart, Pointer: 0, CodePoint: 'a'},
+		{Input: "a:b", State: StateScheme, Pointer: 1, CodePoint: ':', Buffer: "a"},
+		{Input: "a:b", State: StateOpaquePath, Pointer: 2, CodePoint: 'b'},
+		{Input: "a:b", State: StateOpaquePath, Pointer: 3, CodePoint: utf8.RuneError, EOF: true, Buffer: "b"},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("trace = %+v, want %+v", events, want)
+	}
+
+	// Canonical urls are traced too
+	events = nil
+	if _, err := p.Parse("http://example.com/"); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(events) == 0 {
+		t.Errorf("trace is empty, want events for canonical url")
+	}
+
+	// Setters are traced
+	u, _ := Parse("http://example.com/")
+	events = nil
+	u.parser = p.(*parser)
+	u.SetPort("8080")
+	if len(events) == 0 || events[0].State != StatePort {
+		t.Errorf("trace = %+v, want events starting in %v", events, StatePort)
+	}
+}
@@ -17,16 +17,25 @@
 package url
 
 import (
+	"io"
 	"strings"
+	"sync"
+
+	"github.com/nlnwa/whatwg-url/errors"
 )
 
 // Url represents a URL.
+//
+// A Url is safe for concurrent use by multiple goroutines as long as none of them modifies it. The url is modified by
+// the Set methods, Reset, Parser.ParseInto and the methods of SearchParams which change the parameters (Append,
+// Delete, Set, Sort, SortAbsolute, Filter and Iterate with a function modifying the pairs). Lazily initialized state,
+// like the parsed search parameters, is synchronized internally.
 type Url struct {
 	inputUrl         string
 	scheme           string
 	username         string
 	password         string
-	host             *string
+	host             *Host
 	port             *string
 	decodedPort      int
 	path             *path
@@ -34,44 +43,181 @@ type Url struct {
 	fragment         *string
 	searchParams     *SearchParams
 	validationErrors []error
+	spans            *Spans
 	parser           *parser
-	isIPv4           bool
-	isIPv6           bool
+	cursor           *parseCursor
+	// unstableInput is true while inputUrl may point into a byte slice owned by the caller of Parser.ParseBytes.
+	unstableInput bool
+	// mu guards the lazy creation of searchParams.
+	mu sync.Mutex
+	// own holds the components of a url parsed into this Url by the fast path of Parser.ParseInto. It is kept by
+	// Reset, so parsing canonical urls into a reused Url does not allocate.
+	own *canonicalComponents
+}
+
+// parseCursor tracks the position of the basic parser while a Url is parsed.
+type parseCursor struct {
+	input *inputString
+	state *State
+}
+
+// Reset clears u, making it an empty url which can be reused by Parser.ParseInto. Urls sharing components with u,
+// e.g. urls parsed relative to u, are not affected.
+func (u *Url) Reset() {
+	*u = Url{path: &path{}, parser: u.parser, own: u.own}
 }
 
 // Href implements WHATWG url api (https://url.spec.whatwg.org/#api)
 // If excludeFragment is true, the fragment component will be excluded from the output.
 func (u *Url) Href(excludeFragment bool) string {
-	output := u.scheme + ":"
+	return u.href(excludeFragment, false)
+}
+
+// UnicodeHref is like Href, but a domain host is serialized in its Unicode form (e.g. "http://bücher.example/"
+// instead of "http://xn--bcher-kva.example/"). This is meant for display, parsing the result gives the same url.
+func (u *Url) UnicodeHref(excludeFragment bool) string {
+	return u.href(excludeFragment, true)
+}
+
+// SchemeRelativeHref is like Href, but without the scheme, e.g. "//example.com/a?b#c" for "http://example.com/a?b#c".
+// The result is a scheme-relative url which gets the scheme of the url it is resolved against. A url without a host
+// can not be written as a scheme-relative url, so for such urls the result is the same as for Href.
+func (u *Url) SchemeRelativeHref(excludeFragment bool) string {
+	href := u.href(excludeFragment, false)
+	if u.host == nil {
+		return href
+	}
+	return href[len(u.scheme)+1:]
+}
+
+// AppendHref appends the serialized url, as returned by Href, to dst and returns the extended buffer.
+// This allows a buffer to be reused when serializing many urls.
+func (u *Url) AppendHref(dst []byte, excludeFragment bool) []byte {
+	host := u.serializedHost(false)
+	if size := u.hrefLen(excludeFragment, host); cap(dst)-len(dst) < size {
+		dst = append(make([]byte, 0, len(dst)+size), dst...)
+	}
+
+	dst = append(dst, u.scheme...)
+	dst = append(dst, ':')
 	if u.host != nil {
-		output += "//"
+		dst = append(dst, "//"...)
 		if u.username != "" || u.password != "" {
-			output += u.username
+			dst = append(dst, u.username...)
 			if u.password != "" {
-				output += ":" + u.password
+				dst = append(dst, ':')
+				dst = append(dst, u.password...)
 			}
-			output += "@"
+			dst = append(dst, '@')
 		}
-		output += *u.host
+		dst = append(dst, host...)
 		if u.port != nil {
-			output += ":" + *u.port
+			dst = append(dst, ':')
+			dst = append(dst, *u.port...)
 		}
 	}
 	if u.host == nil && !u.path.isOpaque() && len(u.path.p) > 1 && u.path.p[0] == "" {
-		output += "/."
+		dst = append(dst, "/."...)
 	}
 
-	output += u.path.String()
+	dst = u.path.appendTo(dst)
 
 	if u.query != nil {
-		output += "?" + *u.query
+		dst = append(dst, '?')
+		dst = append(dst, *u.query...)
 	}
 
 	if !excludeFragment && u.fragment != nil {
-		output += "#" + *u.fragment
+		dst = append(dst, '#')
+		dst = append(dst, *u.fragment...)
 	}
+	return dst
+}
 
-	return output
+// AppendTo writes the serialized url, as returned by Href, to w. The url is serialized into a pooled buffer and
+// written to w with a single call to Write.
+func (u *Url) AppendTo(w io.Writer, excludeFragment bool) (int, error) {
+	buf := u.parser.getParseBuffer()
+	defer u.parser.putParseBuffer(buf)
+	buf.b = u.AppendHref(buf.b, excludeFragment)
+	return w.Write(buf.b)
+}
+
+// serializedHost returns the serialized host, or the empty string if the url has no host. If unicodeHost is true,
+// a domain is serialized in its Unicode form.
+func (u *Url) serializedHost(unicodeHost bool) string {
+	if u.host == nil {
+		return ""
+	}
+	if unicodeHost && u.host.Kind == DomainHost && u.host.Unicode != "" {
+		return u.host.Unicode
+	}
+	return u.host.String()
+}
+
+// hrefLen returns an upper bound for the length of the serialized url with the serialized host given by host.
+func (u *Url) hrefLen(excludeFragment bool, host string) int {
+	size := len(u.scheme) + 1 + u.path.serializedLen()
+	if u.host != nil {
+		size += 2 + len(u.username) + 1 + len(u.password) + 1 + len(host)
+		if u.port != nil {
+			size += 1 + len(*u.port)
+		}
+	} else {
+		size += 2
+	}
+	if u.query != nil {
+		size += 1 + len(*u.query)
+	}
+	if !excludeFragment && u.fragment != nil {
+		size += 1 + len(*u.fragment)
+	}
+	return size
+}
+
+func (u *Url) href(excludeFragment bool, unicodeHost bool) string {
+	host := u.serializedHost(unicodeHost)
+
+	// Compute the size up front, so the output is allocated only once
+	size := u.hrefLen(excludeFragment, host)
+
+	var output strings.Builder
+	output.Grow(size)
+	output.WriteString(u.scheme)
+	output.WriteByte(':')
+	if u.host != nil {
+		output.WriteString("//")
+		if u.username != "" || u.password != "" {
+			output.WriteString(u.username)
+			if u.password != "" {
+				output.WriteByte(':')
+				output.WriteString(u.password)
+			}
+			output.WriteByte('@')
+		}
+		output.WriteString(host)
+		if u.port != nil {
+			output.WriteByte(':')
+			output.WriteString(*u.port)
+		}
+	}
+	if u.host == nil && !u.path.isOpaque() && len(u.path.p) > 1 && u.path.p[0] == "" {
+		output.WriteString("/.")
+	}
+
+	u.path.writeTo(&output)
+
+	if u.query != nil {
+		output.WriteByte('?')
+		output.WriteString(*u.query)
+	}
+
+	if !excludeFragment && u.fragment != nil {
+		output.WriteByte('#')
+		output.WriteString(*u.fragment)
+	}
+
+	return output.String()
 }
 
 // Protocol implements WHATWG url api (https://url.spec.whatwg.org/#api)
@@ -98,7 +244,7 @@ func (u *Url) Username() string {
 
 // SetUsername implements WHATWG url api (https://url.spec.whatwg.org/#api)
 func (u *Url) SetUsername(username string) {
-	if u.host == nil || *u.host == "" || u.scheme == "file" {
+	if u.host == nil || u.host.isEmpty() || u.scheme == "file" {
 		return
 	}
 	u.username = u.parser.PercentEncodeString(username, UserInfoPercentEncodeSet)
@@ -111,7 +257,7 @@ func (u *Url) Password() string {
 
 // SetPassword implements WHATWG url api (https://url.spec.whatwg.org/#api)
 func (u *Url) SetPassword(password string) {
-	if u.host == nil || *u.host == "" || u.scheme == "file" {
+	if u.host == nil || u.host.isEmpty() || u.scheme == "file" {
 		return
 	}
 	u.password = u.parser.PercentEncodeString(password, UserInfoPercentEncodeSet)
@@ -123,9 +269,9 @@ func (u *Url) Host() string {
 		return ""
 	}
 	if u.port == nil {
-		return *u.host
+		return u.host.String()
 	}
-	return *u.host + ":" + *u.port
+	return u.host.String() + ":" + *u.port
 }
 
 // SetHost implements WHATWG url api (https://url.spec.whatwg.org/#api)
@@ -141,7 +287,16 @@ func (u *Url) Hostname() string {
 	if u.host == nil {
 		return ""
 	}
-	return *u.host
+	return u.host.String()
+}
+
+// ParsedHost returns a copy of the parsed host or nil if the url has no host.
+func (u *Url) ParsedHost() *Host {
+	if u.host == nil {
+		return nil
+	}
+	h := *u.host
+	return &h
 }
 
 // SetHostname implements WHATWG url api (https://url.spec.whatwg.org/#api)
@@ -162,7 +317,7 @@ func (u *Url) Port() string {
 
 // SetPort implements WHATWG url api (https://url.spec.whatwg.org/#api)
 func (u *Url) SetPort(port string) {
-	if u.host == nil || *u.host == "" || u.scheme == "file" {
+	if u.host == nil || u.host.isEmpty() || u.scheme == "file" {
 		return
 	}
 	if port == "" {
@@ -199,6 +354,17 @@ func (u *Url) OpaquePath() bool {
 	return u.path.opaque
 }
 
+// PathSegments returns a copy of the percent-encoded path segments, or nil if the path is opaque.
+// e.g. "/a/b/" has the segments "a", "b" and "".
+func (u *Url) PathSegments() []string {
+	if u.path.opaque {
+		return nil
+	}
+	segments := make([]string, len(u.path.p))
+	copy(segments, u.path.p)
+	return segments
+}
+
 // Search implements WHATWG url api (https://url.spec.whatwg.org/#api)
 func (u *Url) Search() string {
 	if u.query == nil || len(*u.query) == 0 {
@@ -212,7 +378,7 @@ func (u *Url) SetSearch(query string) {
 	if query == "" {
 		u.query = nil
 		if u.searchParams != nil {
-			u.searchParams.params = u.searchParams.params[:0]
+			u.searchParams.parsed = false
 		}
 		if u.fragment == nil && u.query == nil {
 			u.path.stripTrailingSpacesIfOpaque()
@@ -224,15 +390,15 @@ func (u *Url) SetSearch(query string) {
 		u.query = new(string)
 	}
 	_, _ = u.parser.BasicParser(query, nil, u, StateQuery)
-	if u.searchParams == nil {
-		u.newUrlSearchParams()
-	} else {
-		u.searchParams.init(*u.query)
+	if u.searchParams != nil {
+		u.searchParams.parsed = false
 	}
 }
 
 // SearchParams implements WHATWG url api (https://url.spec.whatwg.org/#api)
 func (u *Url) SearchParams() *SearchParams {
+	u.mu.Lock()
+	defer u.mu.Unlock()
 	if u.searchParams == nil {
 		u.newUrlSearchParams()
 	}
@@ -283,18 +449,76 @@ func (u *Url) ValidationErrors() []error {
 	return u.validationErrors
 }
 
-func (u *Url) newUrlSearchParams() {
-	usp := &SearchParams{url: u}
-	if u.query != nil {
-		usp.init(*u.query)
+// input implements errorSink
+func (u *Url) input() string {
+	if u.unstableInput {
+		// Validation errors may outlive the byte slice
+		return strings.Clone(u.inputUrl)
 	}
-	u.searchParams = usp
+	return u.inputUrl
+}
+
+// addValidationError implements errorSink
+func (u *Url) addValidationError(err error) {
+	u.validationErrors = append(u.validationErrors, err)
+}
+
+// position implements errorSink
+func (u *Url) position() (errors.Position, bool) {
+	if u.cursor == nil {
+		return errors.Position{}, false
+	}
+	offset := u.cursor.input.pointer
+	if offset < 0 {
+		offset = 0
+	} else if length := u.cursor.input.length(); offset > length {
+		offset = length
+	}
+	state := *u.cursor.state
+	return errors.Position{Offset: offset, Component: state.component(), State: state.String()}, true
+}
+
+func (u *Url) newUrlSearchParams() {
+	u.searchParams = &SearchParams{url: u}
 }
 
 func (u *Url) IsIPv4() bool {
-	return u.isIPv4
+	return u.host != nil && u.host.Kind == IPv4Host
 }
 
 func (u *Url) IsIPv6() bool {
-	return u.isIPv6
+	return u.host != nil && u.host.Kind == IPv6Host
+}
+
+// IPv4Original returns the host as written in the input if the host is an IPv4 address.
+// This might differ from Hostname() which returns the normalized dotted-decimal form.
+func (u *Url) IPv4Original() string {
+	if !u.IsIPv4() {
+		return ""
+	}
+	return u.host.IPv4Original
+}
+
+// IPv4Number returns the numeric value of the host if the host is an IPv4 address.
+// The second return value is false if the host is not an IPv4 address.
+func (u *Url) IPv4Number() (uint32, bool) {
+	if !u.IsIPv4() {
+		return 0, false
+	}
+	return uint32(u.host.IPv4), true
+}
+
+// HasTrailingDot returns true if the host is a domain which was written with a trailing dot (e.g. "example.com.").
+// This is also true if the trailing dot was stripped because the parser is configured with WithStripTrailingDot.
+func (u *Url) HasTrailingDot() bool {
+	return u.host != nil && u.host.Kind == DomainHost && u.host.TrailingDot
+}
+
+// IPv6Zone returns the zone identifier if the host is an IPv6 address with a zone identifier.
+// Zone identifiers are only accepted when the parser is configured with WithAllowIPv6ZoneID.
+func (u *Url) IPv6Zone() string {
+	if !u.IsIPv6() {
+		return ""
+	}
+	return u.host.Zone
 }
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"context"
+
+	"github.com/nlnwa/whatwg-url/errors"
+)
+
+// recoverableFailures are the failures Validate recovers from. After these the parser can continue in a
+// well-defined state: the credentials, host or port is dropped and the rest of the url is parsed as usual.
+var recoverableFailures = map[errors.ErrorType]bool{
+	errors.InvalidCredentials: true,
+	errors.HostMissing:        true,
+	errors.PortOutOfRange:     true,
+	errors.PortInvalid:        true,
+}
+
+// Validate parses rawUrl only to find its validation errors, which are returned in the order they were found.
+// A nil slice means rawUrl is valid.
+//
+// Unlike Parse, Validate continues after failures where it is safe to do so, e.g. after an invalid host or port,
+// so the result is the full diagnosis of rawUrl rather than the first failure. Failures which leave nothing
+// sensible to parse, like a missing scheme, still end the validation. The function set by WithResolveHostFunc is
+// not called, and options deciding when parsing fails (like WithFailOnValidationError) do not apply.
+//
+// This API is EXPERIMENTAL.
+func (p *parser) Validate(rawUrl string) []*errors.ValidationError {
+	vp := &parser{opts: p.opts}
+	vp.opts.reportValidationErrors = true
+	vp.opts.failOnValidationError = false
+	vp.opts.collectValidationErrors = false
+	vp.opts.joinValidationErrors = false
+	vp.opts.validationErrorHandler = nil
+	vp.opts.resolveHostFunc = nil
+	vp.opts.idnaCache = nil
+	vp.opts.metricsSink = nil
+	vp.opts.recoverFailures = true
+
+	u := &Url{path: &path{}}
+	_, _ = vp.parse(context.Background(), rawUrl, nil, u, NoState)
+	var errs []*errors.ValidationError
+	for _, err := range u.validationErrors {
+		if ve, ok := err.(*errors.ValidationError); ok {
+			errs = append(errs, ve)
+		}
+	}
+	return errs
+}
+
+// recoverHostFailure returns an empty host instead of the host parser's failure when Validate recovers from
+// failures.
+func (p *parser) recoverHostFailure(h *Host, err error) (*Host, error) {
+	if err != nil && p.opts.recoverFailures {
+		return &Host{}, nil
+	}
+	return h, err
+}
+
+// Validate validates rawUrl with the default parser. See Parser.Validate.
+func Validate(rawUrl string) []*errors.ValidationError {
+	return defaultParser.Validate(rawUrl)
+}
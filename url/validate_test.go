@@ -0,0 +1,60 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package url
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nlnwa/whatwg-url/errors"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantTypes []errors.ErrorType
+	}{
+		{"1", "http://example.com/", nil},
+		{"2", " http://example.com\\a", []errors.ErrorType{errors.InvalidURLUnit, errors.InvalidReverseSolidus}},
+		{"3", "http://example.com:99999/a\\b", []errors.ErrorType{errors.PortOutOfRange, errors.InvalidReverseSolidus}},
+		{"4", "http://example.com:8x0y/a\\b", []errors.ErrorType{errors.PortInvalid, errors.InvalidReverseSolidus}},
+		{"5", "http://@:99999/a b", []errors.ErrorType{errors.InvalidCredentials, errors.HostMissing, errors.PortOutOfRange,
+			errors.InvalidURLUnit}},
+		{"6", "http://:80/a\\b", []errors.ErrorType{errors.HostMissing, errors.InvalidReverseSolidus}},
+		{"7", "http://exa%mple.com/a\\b", []errors.ErrorType{errors.DomainInvalidCodePoint, errors.InvalidReverseSolidus}},
+		{"8", "http://?q#a b", []errors.ErrorType{errors.HostMissing, errors.InvalidURLUnit}},
+		{"9", "a\\b", []errors.ErrorType{errors.MissingSchemeNonRelativeURL}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []errors.ErrorType
+			for _, e := range Validate(tt.input) {
+				got = append(got, e.Type())
+			}
+			if !reflect.DeepEqual(got, tt.wantTypes) {
+				t.Errorf("Validate(%q) = %v, want %v", tt.input, got, tt.wantTypes)
+			}
+		})
+	}
+
+	// Validate ignores options deciding when parsing fails
+	p := NewParser(WithFailOnValidationError())
+	if got := p.Validate("http://user@example.com:99999/"); len(got) != 2 {
+		t.Errorf("Validate() = %v, want 2 errors", got)
+	}
+}
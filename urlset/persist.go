@@ -0,0 +1,206 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package urlset
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// magic starts the serialized form of a Set. The last byte is the format version.
+const magic = "urlset\x00\x01"
+
+const (
+	kindStrings byte = 0
+	kindHashes  byte = 1
+)
+
+// maxKeyLength limits the length of a serialized url read by ReadFrom, protecting against corrupt input.
+const maxKeyLength = 1 << 24
+
+// ErrFormat is returned by ReadFrom when the input is not a serialized Set of the same kind.
+var ErrFormat = errors.New("urlset: invalid format")
+
+// WriteTo writes the URLs in the set to w in a compact binary format readable by ReadFrom. The keys are written in
+// sorted order, so equal sets give identical output.
+func (s *Set) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	kind := kindStrings
+	n := len(s.strings)
+	if s.hashes != nil {
+		kind = kindHashes
+		n = len(s.hashes)
+	}
+	var buf [binary.MaxVarintLen64]byte
+	_, _ = cw.Write([]byte(magic))
+	_, _ = cw.Write([]byte{kind})
+	_, _ = cw.Write(buf[:binary.PutUvarint(buf[:], uint64(n))])
+
+	if s.hashes != nil {
+		keys := make([]uint64, 0, n)
+		for k := range s.hashes {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+		for _, k := range keys {
+			binary.BigEndian.PutUint64(buf[:], k)
+			_, _ = cw.Write(buf[:8])
+		}
+	} else {
+		keys := make([]string, 0, n)
+		for k := range s.strings {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			_, _ = cw.Write(buf[:binary.PutUvarint(buf[:], uint64(len(k)))])
+			_, _ = io.WriteString(cw, k)
+		}
+	}
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, bw.Flush()
+}
+
+// ReadFrom adds the URLs written by WriteTo to the set. The set must use the same kind of keys as the set which was
+// written, i.e. both or none must be created with WithHashKeys. The keys are not canonicalized again, so the sets
+// should use the same profile. The input is buffered, so more than the serialized set may be read from r.
+func (s *Set) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: bufio.NewReader(r)}
+
+	header := make([]byte, len(magic)+1)
+	if _, err := io.ReadFull(cr, header); err != nil {
+		return cr.n, fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+	wantKind := kindStrings
+	if s.hashes != nil {
+		wantKind = kindHashes
+	}
+	if string(header[:len(magic)]) != magic || header[len(magic)] != wantKind {
+		return cr.n, ErrFormat
+	}
+	n, err := binary.ReadUvarint(cr)
+	if err != nil {
+		return cr.n, fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var buf [8]byte
+	for i := uint64(0); i < n; i++ {
+		if s.hashes != nil {
+			if _, err := io.ReadFull(cr, buf[:]); err != nil {
+				return cr.n, fmt.Errorf("%w: %v", ErrFormat, err)
+			}
+			s.hashes[binary.BigEndian.Uint64(buf[:])] = struct{}{}
+			continue
+		}
+		l, err := binary.ReadUvarint(cr)
+		if err != nil {
+			return cr.n, fmt.Errorf("%w: %v", ErrFormat, err)
+		}
+		if l > maxKeyLength {
+			return cr.n, ErrFormat
+		}
+		b := make([]byte, l)
+		if _, err := io.ReadFull(cr, b); err != nil {
+			return cr.n, fmt.Errorf("%w: %v", ErrFormat, err)
+		}
+		s.strings[string(b)] = struct{}{}
+	}
+	return cr.n, nil
+}
+
+// SaveFile writes the set to the file name. The file is written to a temporary file in the same directory which is
+// renamed when complete, so an existing file is not corrupted if writing fails.
+func (s *Set) SaveFile(name string) error {
+	f, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if _, err := s.WriteTo(f); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
+
+// LoadFile creates a Set with the given options and reads the URLs saved with SaveFile from the file name.
+func LoadFile(name string, opts ...Option) (*Set, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	s := New(opts...)
+	if _, err := s.ReadFrom(f); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
+
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
@@ -0,0 +1,184 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package urlset implements a set of URLs keyed on their canonical form.
+//
+// URLs are canonicalized with a canonicalizer profile before they are added or looked up, so equivalent URLs, e.g.
+// "HTTP://Example.com:80" and "http://example.com/", are only stored once. This is the deduplication needed by crawl
+// frontiers and similar services.
+//
+// By default the canonical serialization is stored. With WithHashKeys only the 64-bit hash of it is stored, which
+// uses a fraction of the memory at the cost of a small probability of false positives.
+package urlset
+
+import (
+	"sync"
+
+	"github.com/nlnwa/whatwg-url/canonicalizer"
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+// Option configures a Set.
+type Option interface {
+	apply(*options)
+}
+
+type options struct {
+	profile  *canonicalizer.Profile
+	hashKeys bool
+	sizeHint int
+}
+
+type funcOption struct {
+	f func(*options)
+}
+
+func (fo *funcOption) apply(o *options) {
+	fo.f(o)
+}
+
+func newFuncOption(f func(*options)) *funcOption {
+	return &funcOption{
+		f: f,
+	}
+}
+
+func defaultOptions() options {
+	return options{
+		profile: canonicalizer.WhatWg,
+	}
+}
+
+// WithProfile sets the canonicalizer profile used to canonicalize URLs. Default is canonicalizer.WhatWg.
+func WithProfile(profile *canonicalizer.Profile) Option {
+	return newFuncOption(func(o *options) {
+		o.profile = profile
+	})
+}
+
+// WithHashKeys stores the 64-bit xxHash of the canonical serialization (see canonicalizer.Profile.Key64) instead of
+// the serialization itself. With n URLs in the set, the probability of a false positive is about n/2^64 for each
+// lookup.
+func WithHashKeys() Option {
+	return newFuncOption(func(o *options) {
+		o.hashKeys = true
+	})
+}
+
+// WithSizeHint preallocates room for n URLs.
+func WithSizeHint(n int) Option {
+	return newFuncOption(func(o *options) {
+		o.sizeHint = n
+	})
+}
+
+// Set is a set of URLs keyed on their canonical form.
+//
+// A Set is safe for concurrent use.
+type Set struct {
+	profile *canonicalizer.Profile
+	mu      sync.RWMutex
+	// Only one of strings and hashes is used, depending on WithHashKeys.
+	strings map[string]struct{}
+	hashes  map[uint64]struct{}
+}
+
+// New creates an empty Set.
+func New(opts ...Option) *Set {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+	s := &Set{profile: o.profile}
+	if o.hashKeys {
+		s.hashes = make(map[uint64]struct{}, o.sizeHint)
+	} else {
+		s.strings = make(map[string]struct{}, o.sizeHint)
+	}
+	return s
+}
+
+// Add canonicalizes rawUrl and adds it to the set. It returns true if the url was not already in the set.
+// An error is returned if rawUrl can not be parsed.
+func (s *Set) Add(rawUrl string) (bool, error) {
+	u, err := s.profile.Parse(rawUrl)
+	if err != nil {
+		return false, err
+	}
+	return s.AddUrl(u), nil
+}
+
+// AddUrl adds u to the set. It returns true if the url was not already in the set.
+//
+// u is expected to be canonicalized by the profile of the set, e.g. the result of its Parse. AddUrl does not apply
+// the rules itself.
+func (s *Set) AddUrl(u *url.Url) bool {
+	if s.hashes != nil {
+		key := s.profile.Key64(u)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.hashes[key]; ok {
+			return false
+		}
+		s.hashes[key] = struct{}{}
+		return true
+	}
+	key := u.Href(false)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.strings[key]; ok {
+		return false
+	}
+	s.strings[key] = struct{}{}
+	return true
+}
+
+// Contains canonicalizes rawUrl and returns true if it is in the set.
+// An error is returned if rawUrl can not be parsed.
+func (s *Set) Contains(rawUrl string) (bool, error) {
+	u, err := s.profile.Parse(rawUrl)
+	if err != nil {
+		return false, err
+	}
+	return s.ContainsUrl(u), nil
+}
+
+// ContainsUrl returns true if u is in the set. Like AddUrl, it expects u to be canonicalized by the profile of
+// the set.
+func (s *Set) ContainsUrl(u *url.Url) bool {
+	if s.hashes != nil {
+		key := s.profile.Key64(u)
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		_, ok := s.hashes[key]
+		return ok
+	}
+	key := u.Href(false)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.strings[key]
+	return ok
+}
+
+// Len returns the number of URLs in the set.
+func (s *Set) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.hashes != nil {
+		return len(s.hashes)
+	}
+	return len(s.strings)
+}
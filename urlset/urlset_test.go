@@ -0,0 +1,157 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package urlset
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/nlnwa/whatwg-url/canonicalizer"
+)
+
+func TestSet(t *testing.T) {
+	for _, hashKeys := range []bool{false, true} {
+		var opts []Option
+		if hashKeys {
+			opts = append(opts, WithHashKeys())
+		}
+		s := New(opts...)
+		tests := []struct {
+			name      string
+			url       string
+			wantAdded bool
+		}{
+			{"1", "http://example.com/", true},
+			{"2", "HTTP://Example.com:80", false},
+			{"3", "http://example.com/a/../", false},
+			{"4", "http://example.com/?a", true},
+			{"5", "http://www.example.com/", true},
+			{"6", "http://bücher.example/", true},
+			{"7", "http://xn--bcher-kva.example/", false},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				added, err := s.Add(tt.url)
+				if err != nil {
+					t.Fatalf("Add() error = %v", err)
+				}
+				if added != tt.wantAdded {
+					t.Errorf("Add() = %v, want %v", added, tt.wantAdded)
+				}
+				if ok, _ := s.Contains(tt.url); !ok {
+					t.Errorf("Contains() = false after Add()")
+				}
+			})
+		}
+		if got := s.Len(); got != 4 {
+			t.Errorf("Len() = %v, want %v", got, 4)
+		}
+		if ok, _ := s.Contains("http://example.org/"); ok {
+			t.Errorf("Contains() = true for url not in set")
+		}
+		if _, err := s.Add("http://exa mple.com"); err == nil {
+			t.Errorf("Add() error = nil for invalid url")
+		}
+	}
+}
+
+func TestSet_WithProfile(t *testing.T) {
+	s := New(WithProfile(canonicalizer.GoogleSafeBrowsing))
+	_, _ = s.Add("http://example.com/a#frag")
+	if ok, _ := s.Contains("http://example.com/a"); !ok {
+		t.Errorf("Contains() = false, want true")
+	}
+	if ok, _ := New().Contains("http://example.com/a#frag"); ok {
+		t.Errorf("Contains() = true for empty set")
+	}
+}
+
+func TestSet_Persistence(t *testing.T) {
+	urls := []string{"http://example.com/", "http://example.org/a?b", "https://example.net/ø"}
+	for _, hashKeys := range []bool{false, true} {
+		var opts []Option
+		if hashKeys {
+			opts = append(opts, WithHashKeys())
+		}
+		s := New(opts...)
+		for _, u := range urls {
+			_, _ = s.Add(u)
+		}
+
+		name := filepath.Join(t.TempDir(), "set")
+		if err := s.SaveFile(name); err != nil {
+			t.Fatalf("SaveFile() error = %v", err)
+		}
+		loaded, err := LoadFile(name, opts...)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if loaded.Len() != len(urls) {
+			t.Errorf("LoadFile() Len() = %v, want %v", loaded.Len(), len(urls))
+		}
+		for _, u := range urls {
+			if ok, _ := loaded.Contains(u); !ok {
+				t.Errorf("LoadFile() Contains(%v) = false", u)
+			}
+		}
+
+		var b1, b2 bytes.Buffer
+		n, err := s.WriteTo(&b1)
+		if err != nil || n != int64(b1.Len()) {
+			t.Errorf("WriteTo() = %v, %v, want %v, nil", n, err, b1.Len())
+		}
+		_, _ = loaded.WriteTo(&b2)
+		if !bytes.Equal(b1.Bytes(), b2.Bytes()) {
+			t.Errorf("WriteTo() output differs for equal sets")
+		}
+
+		// Reading into a set with the other kind of keys fails
+		other := New()
+		if !hashKeys {
+			other = New(WithHashKeys())
+		}
+		if _, err := other.ReadFrom(bytes.NewReader(b1.Bytes())); !errors.Is(err, ErrFormat) {
+			t.Errorf("ReadFrom() error = %v, want %v", err, ErrFormat)
+		}
+		if _, err := New(opts...).ReadFrom(bytes.NewReader(b1.Bytes()[:b1.Len()-1])); !errors.Is(err, ErrFormat) {
+			t.Errorf("ReadFrom() error = %v, want %v", err, ErrFormat)
+		}
+	}
+}
+
+func TestSet_Concurrent(t *testing.T) {
+	s := New()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, u := range []string{"http://a.example/", "http://b.example/", "http://c.example/"} {
+				_, _ = s.Add(u)
+				_, _ = s.Contains(u)
+				_ = s.Len()
+			}
+		}()
+	}
+	wg.Wait()
+	if got := s.Len(); got != 3 {
+		t.Errorf("Len() = %v, want %v", got, 3)
+	}
+}
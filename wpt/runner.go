@@ -0,0 +1,437 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package wpt runs the URL conformance tests from [web-platform-tests] against a url.Parser.
+//
+// The tests use the JSON resources of the WPT url directory, which are kept in the testdata directory of this module
+// and updated by testdata/refresh.sh. Forks and users of custom parser options can run the same suite from their own
+// tests and skip the cases where their options deliberately deviate from the standard:
+//
+//	func TestConformance(t *testing.T) {
+//		r := &wpt.TestDataRunner{
+//			Parser: url.NewParser(url.WithLaxHostParsing()),
+//			Dir:    "testdata",
+//			Skip: func(c wpt.Case) bool {
+//				return c.Suite == wpt.ToASCIIFile
+//			},
+//		}
+//		r.Run(t)
+//	}
+//
+// [web-platform-tests]: https://github.com/web-platform-tests/wpt/tree/master/url
+package wpt
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+// The names of the WPT resources read by the runner.
+const (
+	URLTestDataFile       = "urltestdata.json"
+	JavaScriptOnlyFile    = "urltestdata-javascript-only.json"
+	SettersFile           = "setters_tests.json"
+	ToASCIIFile           = "toascii.json"
+	IdnaTestV2File        = "IdnaTestV2.json"
+	PercentEncodingFile   = "percent-encoding.json"
+	SetterStrippingSuite  = "setters-stripping"
+	defaultTestDataFolder = "testdata"
+)
+
+// Case identifies a single test case passed to TestDataRunner.Skip.
+type Case struct {
+	// Suite is the file name of the resource the case is read from, or SetterStrippingSuite for the generated
+	// setter stripping tests.
+	Suite string
+	// Name is the name of the subtest running the case.
+	Name string
+	// Input is the input of the test case. For setter tests, it is the new value.
+	Input string
+}
+
+// TestDataRunner runs the WPT URL tests as subtests of a *testing.T.
+type TestDataRunner struct {
+	// Parser is the parser under test. If nil, a parser with default options is used.
+	Parser url.Parser
+	// Dir is the directory containing the WPT resources. If empty, "testdata" is used.
+	Dir string
+	// Skip is called for each test case if set. Cases for which it returns true are skipped.
+	Skip func(c Case) bool
+}
+
+// Run runs all the test suites. Suites whose resource is missing from Dir are skipped.
+func (r *TestDataRunner) Run(t *testing.T) {
+	t.Run(URLTestDataFile, func(t *testing.T) { r.RunURLTestData(t, URLTestDataFile) })
+	t.Run(JavaScriptOnlyFile, func(t *testing.T) { r.RunURLTestData(t, JavaScriptOnlyFile) })
+	t.Run(SettersFile, func(t *testing.T) { r.RunSetters(t, SettersFile) })
+	t.Run(SetterStrippingSuite, r.RunSetterStripping)
+	t.Run(ToASCIIFile, func(t *testing.T) { r.RunToASCII(t, ToASCIIFile) })
+	t.Run(IdnaTestV2File, func(t *testing.T) { r.RunToASCII(t, IdnaTestV2File) })
+	t.Run(PercentEncodingFile, func(t *testing.T) { r.RunPercentEncoding(t, PercentEncodingFile) })
+}
+
+func (r *TestDataRunner) parser() url.Parser {
+	if r.Parser == nil {
+		return url.NewParser()
+	}
+	return r.Parser
+}
+
+// run runs f as a subtest unless the case is skipped.
+func (r *TestDataRunner) run(t *testing.T, c Case, f func(t *testing.T)) {
+	t.Run(c.Name, func(t *testing.T) {
+		if r.Skip != nil && r.Skip(c) {
+			t.Skip("skipped by TestDataRunner.Skip")
+		}
+		f(t)
+	})
+}
+
+// load reads the test cases in file into a slice of v, skipping the comments. The test is skipped if the file does
+// not exist.
+func (r *TestDataRunner) load(t *testing.T, file string, v interface{}) {
+	t.Helper()
+	dir := r.Dir
+	if dir == "" {
+		dir = defaultTestDataFolder
+	}
+	data, err := os.ReadFile(filepath.Join(dir, file))
+	if os.IsNotExist(err) {
+		t.Skipf("%s not found in %s, run testdata/refresh.sh to fetch it", file, dir)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("%s: %v", file, err)
+	}
+}
+
+// loadCases reads a JSON array from file and returns the elements which are objects. Strings in the array are
+// comments and are left out.
+func (r *TestDataRunner) loadCases(t *testing.T, file string) []json.RawMessage {
+	t.Helper()
+	var raw []json.RawMessage
+	r.load(t, file, &raw)
+	cases := raw[:0]
+	for _, c := range raw {
+		if len(c) > 0 && c[0] == '{' {
+			cases = append(cases, c)
+		}
+	}
+	return cases
+}
+
+// urlTest is a test case from urltestdata.json and urltestdata-javascript-only.json.
+type urlTest struct {
+	Input    string
+	Base     *string
+	Href     string
+	Origin   *string
+	Protocol string
+	Username string
+	Password string
+	Host     string
+	Hostname string
+	Port     string
+	Pathname string
+	Search   string
+	Hash     string
+	Failure  bool
+}
+
+// RunURLTestData runs the parsing tests in file, which has the format of urltestdata.json. Each url is also
+// checked to parse back to itself.
+func (r *TestDataRunner) RunURLTestData(t *testing.T, file string) {
+	p := r.parser()
+	for i, raw := range r.loadCases(t, file) {
+		var tt urlTest
+		if err := json.Unmarshal(raw, &tt); err != nil {
+			t.Fatalf("%s: case %d: %v", file, i+1, err)
+		}
+		r.run(t, Case{Suite: file, Name: strconv.Itoa(i + 1), Input: tt.Input}, func(t *testing.T) {
+			var got *url.Url
+			var err error
+			if tt.Base != nil {
+				got, err = p.ParseRef(*tt.Base, tt.Input)
+			} else {
+				got, err = p.Parse(tt.Input)
+			}
+			if tt.Failure {
+				if err == nil {
+					t.Errorf("Parse(%q) base %v = %v, want failure", tt.Input, base(tt.Base), got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) base %v error = %v", tt.Input, base(tt.Base), err)
+			}
+			checkComponents(t, got, map[string]string{
+				"href":     tt.Href,
+				"protocol": tt.Protocol,
+				"username": tt.Username,
+				"password": tt.Password,
+				"host":     tt.Host,
+				"hostname": tt.Hostname,
+				"port":     tt.Port,
+				"pathname": tt.Pathname,
+				"search":   tt.Search,
+				"hash":     tt.Hash,
+			})
+			if tt.Origin != nil && got.Origin() != *tt.Origin {
+				t.Errorf("Origin() = %q, want %q", got.Origin(), *tt.Origin)
+			}
+			reparsed, err := p.Parse(got.Href(false))
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v, want reparse to succeed", got.Href(false), err)
+			}
+			if reparsed.Href(false) != got.Href(false) {
+				t.Errorf("Parse(%q) = %q, want same url", got.Href(false), reparsed.Href(false))
+			}
+		})
+	}
+}
+
+func base(b *string) string {
+	if b == nil {
+		return "<nil>"
+	}
+	return strconv.Quote(*b)
+}
+
+// getters are the url components checked by the tests, keyed by the attribute names of the URL class.
+var getters = map[string]func(u *url.Url) string{
+	"href":     func(u *url.Url) string { return u.Href(false) },
+	"protocol": (*url.Url).Protocol,
+	"username": (*url.Url).Username,
+	"password": (*url.Url).Password,
+	"host":     (*url.Url).Host,
+	"hostname": (*url.Url).Hostname,
+	"port":     (*url.Url).Port,
+	"pathname": (*url.Url).Pathname,
+	"search":   (*url.Url).Search,
+	"hash":     (*url.Url).Hash,
+}
+
+// setters are the url setters tested, keyed by the attribute names of the URL class.
+var setters = map[string]func(u *url.Url, v string){
+	"protocol": (*url.Url).SetProtocol,
+	"username": (*url.Url).SetUsername,
+	"password": (*url.Url).SetPassword,
+	"host":     (*url.Url).SetHost,
+	"hostname": (*url.Url).SetHostname,
+	"port":     (*url.Url).SetPort,
+	"pathname": (*url.Url).SetPathname,
+	"search":   (*url.Url).SetSearch,
+	"hash":     (*url.Url).SetHash,
+}
+
+// setterOrder is the order of the setters in setters_tests.json.
+var setterOrder = []string{"protocol", "username", "password", "host", "hostname", "port", "pathname", "search", "hash"}
+
+// checkComponents reports an error for each component of u which differs from want.
+func checkComponents(t *testing.T, u *url.Url, want map[string]string) {
+	t.Helper()
+	for _, name := range append([]string{"href"}, setterOrder...) {
+		w, ok := want[name]
+		if !ok {
+			continue
+		}
+		if got := getters[name](u); got != w {
+			t.Errorf("%s = %q, want %q", name, got, w)
+		}
+	}
+}
+
+// setterTest is a test case from setters_tests.json.
+type setterTest struct {
+	Href     string
+	NewValue string `json:"new_value"`
+	Expected map[string]string
+}
+
+// RunSetters runs the setter tests in file, which has the format of setters_tests.json.
+func (r *TestDataRunner) RunSetters(t *testing.T, file string) {
+	p := r.parser()
+	var tests map[string][]json.RawMessage
+	r.load(t, file, &tests)
+	for _, name := range setterOrder {
+		set := setters[name]
+		t.Run(name, func(t *testing.T) {
+			for i, raw := range tests[name] {
+				var tt setterTest
+				if err := json.Unmarshal(raw, &tt); err != nil {
+					t.Fatalf("%s: %s case %d: %v", file, name, i+1, err)
+				}
+				r.run(t, Case{Suite: file, Name: strconv.Itoa(i + 1), Input: tt.NewValue}, func(t *testing.T) {
+					u, err := p.Parse(tt.Href)
+					if err != nil {
+						t.Fatalf("Parse(%q) error = %v", tt.Href, err)
+					}
+					set(u, tt.NewValue)
+					checkComponents(t, u, tt.Expected)
+				})
+			}
+		})
+	}
+}
+
+// toASCIITest is a test case from toascii.json and IdnaTestV2.json.
+type toASCIITest struct {
+	Input  string
+	Output *string
+}
+
+// RunToASCII runs the domain to ASCII tests in file, which has the format of toascii.json or IdnaTestV2.json. Like
+// the WPT test scripts, the domain is used as the host of an url and set with the host and hostname setters. Empty
+// inputs and inputs containing '%' or code points ending the host, like '/' and '?', are left out, since they do
+// not test domain to ASCII when parsed as part of an url.
+func (r *TestDataRunner) RunToASCII(t *testing.T, file string) {
+	p := r.parser()
+	for i, raw := range r.loadCases(t, file) {
+		var tt toASCIITest
+		if err := json.Unmarshal(raw, &tt); err != nil {
+			t.Fatalf("%s: case %d: %v", file, i+1, err)
+		}
+		if tt.Input == "" || strings.ContainsAny(tt.Input, "%/?#\\@:[") {
+			continue
+		}
+		r.run(t, Case{Suite: file, Name: strconv.Itoa(i + 1), Input: tt.Input}, func(t *testing.T) {
+			input := "https://" + tt.Input + "/x"
+			u, err := p.Parse(input)
+			if tt.Output == nil {
+				if err == nil {
+					t.Errorf("Parse(%q) = %v, want failure", input, u)
+				}
+			} else if err != nil {
+				t.Errorf("Parse(%q) error = %v", input, err)
+			} else {
+				checkComponents(t, u, map[string]string{
+					"href":     "https://" + *tt.Output + "/x",
+					"host":     *tt.Output,
+					"hostname": *tt.Output,
+					"pathname": "/x",
+				})
+			}
+
+			for _, name := range []string{"host", "hostname"} {
+				u, err := p.Parse("https://x/x")
+				if err != nil {
+					t.Fatal(err)
+				}
+				setters[name](u, tt.Input)
+				want := "x"
+				if tt.Output != nil {
+					want = *tt.Output
+				}
+				if got := getters[name](u); got != want {
+					t.Errorf("Set%s(%q): %s = %q, want %q", name, tt.Input, name, got, want)
+				}
+			}
+		})
+	}
+}
+
+// percentEncodingTest is a test case from percent-encoding.json.
+type percentEncodingTest struct {
+	Input  string
+	Output map[string]string
+}
+
+// RunPercentEncoding runs the percent-encoding tests in file, which has the format of percent-encoding.json. The
+// input is used as the query of a special url parsed with the encoding given by each output. Encodings which can not
+// be set with url.WithEncodingOverride, like the multi-byte encodings, are skipped.
+func (r *TestDataRunner) RunPercentEncoding(t *testing.T, file string) {
+	for i, raw := range r.loadCases(t, file) {
+		var tt percentEncodingTest
+		if err := json.Unmarshal(raw, &tt); err != nil {
+			t.Fatalf("%s: case %d: %v", file, i+1, err)
+		}
+		r.run(t, Case{Suite: file, Name: strconv.Itoa(i + 1), Input: tt.Input}, func(t *testing.T) {
+			for _, label := range sortedKeys(tt.Output) {
+				want := tt.Output[label]
+				t.Run(label, func(t *testing.T) {
+					p, err := r.parserWithEncoding(label)
+					if err != nil {
+						t.Skip(err)
+					}
+					input := "https://doesnotmatter.invalid/?" + tt.Input
+					u, err := p.Parse(input)
+					if err != nil {
+						t.Fatalf("Parse(%q) error = %v", input, err)
+					}
+					if got := u.Search(); got != "?"+want {
+						t.Errorf("Parse(%q) search = %q, want %q", input, got, "?"+want)
+					}
+				})
+			}
+		})
+	}
+}
+
+// parserWithEncoding returns the parser under test with the encoding override for the encoding label. The
+// encoding override is left unset for UTF-8.
+func (r *TestDataRunner) parserWithEncoding(label string) (url.Parser, error) {
+	if isUTF8Label(label) {
+		return r.parser(), nil
+	}
+	cm, err := charmapForLabel(label)
+	if err != nil {
+		return nil, err
+	}
+	return r.parser().With(url.WithEncodingOverride(cm)), nil
+}
+
+func isUTF8Label(label string) bool {
+	switch label {
+	case "utf-8", "utf8", "unicode-1-1-utf-8":
+		return true
+	}
+	return false
+}
+
+// charmapForLabel returns the single-byte encoding with the given WHATWG Encoding Standard label.
+func charmapForLabel(label string) (*charmap.Charmap, error) {
+	enc, err := htmlindex.Get(label)
+	if err != nil {
+		return nil, err
+	}
+	cm, ok := enc.(*charmap.Charmap)
+	if !ok {
+		return nil, fmt.Errorf("encoding %q is not supported by url.WithEncodingOverride", label)
+	}
+	return cm, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
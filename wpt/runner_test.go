@@ -0,0 +1,57 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wpt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTestDataRunner(t *testing.T) {
+	r := &TestDataRunner{Dir: "../testdata"}
+	r.Run(t)
+}
+
+func TestTestDataRunner_RunPercentEncoding(t *testing.T) {
+	dir := t.TempDir()
+	data := `["comment", {"input": "†", "output": {"utf-8": "%E2%80%A0", "windows-1252": "%86", "iso-8859-2": "%26%238224%3B"}}]`
+	if err := os.WriteFile(filepath.Join(dir, PercentEncodingFile), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r := &TestDataRunner{Dir: dir}
+	r.RunPercentEncoding(t, PercentEncodingFile)
+}
+
+func TestTestDataRunner_Skip(t *testing.T) {
+	var skipped int
+	r := &TestDataRunner{
+		Dir: "../testdata",
+		Skip: func(c Case) bool {
+			if c.Suite == SetterStrippingSuite && strings.Contains(c.Name, "U+0000") {
+				skipped++
+				return true
+			}
+			return false
+		},
+	}
+	r.RunSetterStripping(t)
+	if skipped == 0 {
+		t.Errorf("Skip was not called for %s", SetterStrippingSuite)
+	}
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright 2026 National Library of Norway.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *       http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wpt
+
+import (
+	"fmt"
+	"testing"
+)
+
+// strippingURL is the url used by the setter stripping tests. Its String method builds the expected href from the
+// components.
+type strippingURL struct {
+	scheme, username, password, host, port, pathname, search, hash string
+}
+
+var defaultStrippingURL = strippingURL{
+	scheme:   "https",
+	username: "username",
+	password: "password",
+	host:     "host",
+	port:     "8000",
+	pathname: "path",
+	search:   "query",
+	hash:     "fragment",
+}
+
+func (s strippingURL) String() string {
+	return s.scheme + "://" + s.username + ":" + s.password + "@" + s.host + ":" + s.port + "/" + s.pathname + "?" +
+		s.search + "#" + s.hash
+}
+
+// strippingCodePoints are the code points tested by RunSetterStripping. Tab and newlines are removed by the setters,
+// while the other C0 controls are kept. Like in WPT, only the first and the last of those are tested.
+var strippingCodePoints = []rune{0x00, 0x09, 0x0A, 0x0D, 0x1F}
+
+// RunSetterStripping runs the tests of url-setters-stripping.any.js from WPT, which are generated rather than
+// read from a resource. A C0 control is inserted at the start, in the middle or at the end of the value given to
+// each setter. Tab and newlines must be removed by the setters using the basic URL parser, while the other C0
+// controls are percent-encoded or make the setter fail, depending on the component.
+func (r *TestDataRunner) RunSetterStripping(t *testing.T) {
+	p := r.parser()
+	for _, scheme := range []string{"https", "wpt++"} {
+		for _, cp := range strippingCodePoints {
+			cp := cp
+			stripped := cp == 0x09 || cp == 0x0A || cp == 0x0D
+			cpString := string(cp)
+			cpEncoded := fmt.Sprintf("%%%02X", cp)
+			cpRef := fmt.Sprintf("U+%04X", cp)
+
+			// check sets the component with the setter of property to value and compares the property and the
+			// href with the expected values.
+			check := func(name, property, value, want string, wantURL strippingURL) {
+				r.run(t, Case{Suite: SetterStrippingSuite, Name: name, Input: value}, func(t *testing.T) {
+					s := defaultStrippingURL
+					s.scheme = scheme
+					u, err := p.Parse(s.String())
+					if err != nil {
+						t.Fatalf("Parse(%q) error = %v", s.String(), err)
+					}
+					setters[property](u, value)
+					checkComponents(t, u, map[string]string{property: want, "href": wantURL.String()})
+				})
+			}
+
+			// insert returns s with c inserted at position.
+			insert := func(s, c, position string) string {
+				switch position {
+				case "leading":
+					return c + s
+				case "middle":
+					return s[:len(s)/2] + c + s[len(s)/2:]
+				}
+				return s + c
+			}
+
+			newScheme := "http"
+			if scheme != "https" {
+				newScheme = "wpt--"
+			}
+			wantScheme := scheme
+			if stripped {
+				wantScheme = newScheme
+			}
+			for _, position := range []string{"leading", "trailing"} {
+				wantURL := defaultStrippingURL
+				wantURL.scheme = wantScheme
+				check(fmt.Sprintf("Setting protocol with %s %s (%s:)", position, cpRef, scheme), "protocol",
+					insert(newScheme, cpString, position), wantScheme+":", wantURL)
+			}
+
+			for _, position := range []string{"leading", "middle", "trailing"} {
+				// want returns the expected value of a component set to value with the code point inserted,
+				// when the code point is percent-encoded.
+				want := func(value string) string {
+					if stripped {
+						return value
+					}
+					return insert(value, cpEncoded, position)
+				}
+				name := func(property string) string {
+					return fmt.Sprintf("Setting %s with %s %s (%s:)", property, position, cpRef, scheme)
+				}
+				wantURL := defaultStrippingURL
+				wantURL.scheme = scheme
+
+				// The username and password setters do not use the basic URL parser, so nothing is removed.
+				for _, property := range []string{"username", "password"} {
+					w := insert("test", cpEncoded, position)
+					u := wantURL
+					if property == "username" {
+						u.username = w
+					} else {
+						u.password = w
+					}
+					check(name(property), property, insert("test", cpString, position), w, u)
+				}
+
+				for _, property := range []string{"host", "hostname"} {
+					// Special hosts fail on all C0 controls, opaque hosts only on U+0000.
+					w := want("test")
+					if !stripped && (cp == 0x00 || scheme == "https") {
+						w = defaultStrippingURL.host
+					}
+					u := wantURL
+					u.host = w
+					if property == "host" {
+						w += ":" + u.port
+					}
+					check(name(property), property, insert("test", cpString, position), w, u)
+				}
+
+				w := "9000"
+				if !stripped {
+					switch position {
+					case "leading":
+						w = defaultStrippingURL.port
+					case "middle":
+						w = "90"
+					}
+				}
+				u := wantURL
+				u.port = w
+				check(name("port"), "port", insert("9000", cpString, position), w, u)
+
+				u = wantURL
+				u.pathname = want("test")
+				check(name("pathname"), "pathname", insert("test", cpString, position), "/"+u.pathname, u)
+
+				u = wantURL
+				u.search = want("test")
+				check(name("search"), "search", insert("test", cpString, position), "?"+u.search, u)
+
+				u = wantURL
+				u.hash = want("test")
+				check(name("hash"), "hash", insert("test", cpString, position), "#"+u.hash, u)
+			}
+		}
+	}
+}